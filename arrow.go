@@ -0,0 +1,95 @@
+//go:build arrow
+// +build arrow
+
+package sparse
+
+import (
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+)
+
+// ArrowCSR represents a Compressed Sparse Row matrix as a set of Apache Arrow arrays
+// corresponding to the buffers of Arrow's SparseCSRMatrix sparse tensor index i.e.
+// the row index pointers (indptr), the column indices (indices) and the non-zero values
+// themselves (data).  This allows CSR matrices to be exchanged with Arrow based
+// systems and data pipelines.
+//
+// Support for Apache Arrow is optional and only included in builds specifying the
+// `arrow` build tag e.g. `go build -tags arrow` so that users not requiring Arrow
+// interoperability need not take on the additional dependency.
+type ArrowCSR struct {
+	Rows, Cols int
+	Indptr     *array.Int64
+	Indices    *array.Int64
+	Data       *array.Float64
+}
+
+// Release releases the Arrow arrays held by the receiver returning their underlying
+// memory back to the allocator used to create them.
+func (a *ArrowCSR) Release() {
+	a.Indptr.Release()
+	a.Indices.Release()
+	a.Data.Release()
+}
+
+// ToArrowCSR returns an Apache Arrow representation of the matrix with the index pointers,
+// indices and data values of the receiver each copied into new Arrow arrays allocated
+// from pool.  If pool is nil, the Go allocator (memory.DefaultAllocator) will be used.
+// The returned ArrowCSR will not share underlying storage with the receiver nor is the
+// receiver modified by this call.  The caller should call Release on the returned
+// ArrowCSR once it is no longer required.
+func (c *CSR) ToArrowCSR(pool memory.Allocator) *ArrowCSR {
+	if pool == nil {
+		pool = memory.DefaultAllocator
+	}
+
+	ib := array.NewInt64Builder(pool)
+	defer ib.Release()
+
+	ib.Reserve(len(c.matrix.Indptr))
+	for _, v := range c.matrix.Indptr {
+		ib.UnsafeAppend(int64(v))
+	}
+	indptr := ib.NewInt64Array()
+
+	ib.Reserve(len(c.matrix.Ind))
+	for _, v := range c.matrix.Ind {
+		ib.UnsafeAppend(int64(v))
+	}
+	indices := ib.NewInt64Array()
+
+	db := array.NewFloat64Builder(pool)
+	defer db.Release()
+	db.AppendValues(c.matrix.Data, nil)
+	data := db.NewFloat64Array()
+
+	return &ArrowCSR{
+		Rows:    c.matrix.I,
+		Cols:    c.matrix.J,
+		Indptr:  indptr,
+		Indices: indices,
+		Data:    data,
+	}
+}
+
+// FromArrowCSR creates a new CSR matrix from the Apache Arrow representation a.  As Arrow
+// stores indices as 64 bit integers, the index pointers and indices are converted into
+// Go int slices and so FromArrowCSR always copies the contents of a into storage owned
+// by the returned CSR matrix.  The returned matrix therefore remains valid after a has
+// been released.  As Arrow buffers are frequently received from other processes, the
+// converted matrix is validated in the same way as NewCSRChecked and an error returned
+// if the arrays are inconsistent with each other or with the dimensions of a.
+func FromArrowCSR(a *ArrowCSR) (*CSR, error) {
+	indptr := make([]int, a.Indptr.Len())
+	for i, v := range a.Indptr.Int64Values() {
+		indptr[i] = int(v)
+	}
+	ind := make([]int, a.Indices.Len())
+	for i, v := range a.Indices.Int64Values() {
+		ind[i] = int(v)
+	}
+	data := make([]float64, a.Data.Len())
+	copy(data, a.Data.Float64Values())
+
+	return NewCSRChecked(a.Rows, a.Cols, indptr, ind, data)
+}
@@ -0,0 +1,106 @@
+//go:build arrow
+// +build arrow
+
+package sparse
+
+import (
+	"testing"
+
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/memory"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestArrowCSRRoundTrip(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 7,
+				0, 0, 0, 0,
+				3, 0, 3, 6,
+			},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		csr := CreateCSR(test.r, test.c, test.data).(*CSR)
+
+		a := csr.ToArrowCSR(pool)
+
+		if a.Rows != test.r || a.Cols != test.c {
+			t.Logf("Expected dimensions %dx%d but received %dx%d", test.r, test.c, a.Rows, a.Cols)
+			t.Fail()
+		}
+		if a.Indptr.Len() != test.r+1 || a.Indices.Len() != csr.NNZ() || a.Data.Len() != csr.NNZ() {
+			t.Logf("Arrow array lengths incorrect: indptr %d, indices %d, data %d", a.Indptr.Len(), a.Indices.Len(), a.Data.Len())
+			t.Fail()
+		}
+
+		result, err := FromArrowCSR(a)
+		a.Release()
+		pool.AssertSize(t, 0)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+
+		if !mat.Equal(csr, result) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(csr), mat.Formatted(result))
+			t.Fail()
+		}
+	}
+}
+
+func TestFromArrowCSRInvalid(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		r, c    int
+		indptr  []int64
+		indices []int64
+		data    []float64
+	}{
+		{desc: "indptr length", r: 2, c: 2, indptr: []int64{0, 1}, indices: []int64{0}, data: []float64{1}},
+		{desc: "indices/data length", r: 1, c: 2, indptr: []int64{0, 1}, indices: []int64{0}, data: []float64{1, 2}},
+		{desc: "indptr not starting at zero", r: 1, c: 2, indptr: []int64{1, 1}, indices: []int64{0}, data: []float64{1}},
+		{desc: "indptr decreasing", r: 2, c: 2, indptr: []int64{0, 2, 1}, indices: []int64{0}, data: []float64{1}},
+		{desc: "indptr not ending at nnz", r: 1, c: 2, indptr: []int64{0, 1}, indices: []int64{0, 1}, data: []float64{1, 2}},
+		{desc: "column index out of range", r: 1, c: 2, indptr: []int64{0, 1}, indices: []int64{2}, data: []float64{1}},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		ib := array.NewInt64Builder(pool)
+		ib.AppendValues(test.indptr, nil)
+		indptr := ib.NewInt64Array()
+		ib.AppendValues(test.indices, nil)
+		indices := ib.NewInt64Array()
+		ib.Release()
+		db := array.NewFloat64Builder(pool)
+		db.AppendValues(test.data, nil)
+		data := db.NewFloat64Array()
+		db.Release()
+
+		a := &ArrowCSR{Rows: test.r, Cols: test.c, Indptr: indptr, Indices: indices, Data: data}
+		if _, err := FromArrowCSR(a); err == nil {
+			t.Errorf("Expected error converting Arrow CSR with invalid %s", test.desc)
+		}
+		a.Release()
+		pool.AssertSize(t, 0)
+	}
+}
@@ -475,3 +475,108 @@ func BenchmarkTraceDIA(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkToCOO(b *testing.B) {
+	csr := Random(CSRFormat, 1000, 1000, 0.01).(*CSR)
+
+	b.Run("Unpooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			csr.ToCOO()
+		}
+	})
+
+	b.Run("Pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		pool := NewConversionPool()
+		for n := 0; n < b.N; n++ {
+			pool.ReleaseCOO(csr.ToCOOWith(ConversionOptions{Pool: pool}))
+		}
+	})
+}
+
+func BenchmarkMulParallel(b *testing.B) {
+	lhs := Random(CSRFormat, 2000, 2000, 0.01).(*CSR)
+	rhs := Random(CSRFormat, 2000, 2000, 0.01).(*CSR)
+
+	b.Run("Mul", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var c CSR
+			c.Mul(lhs, rhs)
+		}
+	})
+	for _, workers := range []int{1, 2, 4, 0} {
+		b.Run(fmt.Sprintf("MulParallel-%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var c CSR
+				MulParallel(&c, lhs, rhs, workers)
+			}
+		})
+	}
+}
+
+func BenchmarkCSRAt(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	csr := Random(CSRFormat, 200, 2000, 0.2).(*CSR)
+
+	b.Run("Linear", func(b *testing.B) {
+		csr.sorted = false
+		for n := 0; n < b.N; n++ {
+			csr.At(rnd.Intn(200), rnd.Intn(2000))
+		}
+	})
+
+	b.Run("Sorted", func(b *testing.B) {
+		csr.SortIndices()
+		for n := 0; n < b.N; n++ {
+			csr.At(rnd.Intn(200), rnd.Intn(2000))
+		}
+	})
+}
+
+func BenchmarkCSRMulGrow(b *testing.B) {
+	lhs := Random(CSRFormat, 500, 500, 0.02).(*CSR)
+	rhs := Random(CSRFormat, 500, 500, 0.02).(*CSR)
+	var result CSR
+	result.Mul(lhs, rhs)
+	nnz := result.NNZ()
+
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			var c CSR
+			c.Mul(lhs, rhs)
+		}
+	})
+
+	b.Run("Grow", func(b *testing.B) {
+		b.ReportAllocs()
+		var c CSR
+		c.Grow(nnz)
+		for n := 0; n < b.N; n++ {
+			c.Reset()
+			c.Mul(lhs, rhs)
+		}
+	})
+}
+
+func BenchmarkCSRMulDense(b *testing.B) {
+	lhs := Random(CSRFormat, 1000, 1000, 0.01).(*CSR)
+	rhs := Random(DenseFormat, 1000, 64, 1).(*mat.Dense)
+
+	b.Run("MulVecPerColumn", func(b *testing.B) {
+		dst := mat.NewDense(1000, 64, nil)
+		for n := 0; n < b.N; n++ {
+			dst.Zero()
+			MulMatMat(false, 1, lhs, rhs, dst)
+		}
+	})
+
+	b.Run("MulDense", func(b *testing.B) {
+		b.ReportAllocs()
+		var dst mat.Dense
+		for n := 0; n < b.N; n++ {
+			lhs.MulDense(&dst, rhs)
+		}
+	})
+}
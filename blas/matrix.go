@@ -1,6 +1,8 @@
 package blas
 
 import (
+	"sort"
+
 	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
@@ -16,10 +18,10 @@ type SparseMatrix struct {
 
 // At returns the element of the matrix located at coordinate i, j.
 func (m *SparseMatrix) At(i, j int) float64 {
-	if uint(i) < 0 || uint(i) >= uint(m.I) {
+	if i < 0 || i >= m.I {
 		panic("sparse/blas: index out of range")
 	}
-	if uint(j) < 0 || uint(j) >= uint(m.J) {
+	if j < 0 || j >= m.J {
 		panic("sparse/blas: index out of range")
 	}
 
@@ -32,13 +34,33 @@ func (m *SparseMatrix) At(i, j int) float64 {
 	return 0
 }
 
+// AtSorted returns the element of the matrix located at coordinate i, j in the same way
+// as At but locates the element using a binary search over the indices of row i in
+// O(log nnz) rather than a linear scan.  The indices of each row must be sorted in
+// ascending order otherwise the result is undefined.
+func (m *SparseMatrix) AtSorted(i, j int) float64 {
+	if i < 0 || i >= m.I {
+		panic("sparse/blas: index out of range")
+	}
+	if j < 0 || j >= m.J {
+		panic("sparse/blas: index out of range")
+	}
+
+	begin, end := m.Indptr[i], m.Indptr[i+1]
+	if k := begin + sort.SearchInts(m.Ind[begin:end], j); k < end && m.Ind[k] == j {
+		return m.Data[k]
+	}
+
+	return 0
+}
+
 // Set is a generic method to set a matrix element.  Note: setting a non-zero element to zero
 // does not remove the element from the sparcity pattern but will actually store a zero value.
 func (m *SparseMatrix) Set(i, j int, v float64) {
-	if uint(i) < 0 || uint(i) >= uint(m.I) {
+	if i < 0 || i >= m.I {
 		panic("sparse/blas: index out of range")
 	}
-	if uint(j) < 0 || uint(j) >= uint(m.J) {
+	if j < 0 || j >= m.J {
 		panic("sparse/blas: index out of range")
 	}
 
@@ -120,3 +142,24 @@ func (m *SparseMatrix) Cull(epsilon float64) *SparseMatrix {
 		Data:   newData,
 	}
 }
+
+// Prune removes, in place, all entries within tol of 0, compacting the Indptr, Ind
+// and Data slices of the receiver.  Unlike Cull, no new storage is allocated.
+func (m *SparseMatrix) Prune(tol float64) {
+	k := 0
+	begin := 0
+	for major := 0; major < len(m.Indptr)-1; major++ {
+		end := m.Indptr[major+1]
+		for minor := begin; minor < end; minor++ {
+			if v := m.Data[minor]; !floats.EqualWithinAbs(v, 0, tol) {
+				m.Ind[k] = m.Ind[minor]
+				m.Data[k] = v
+				k++
+			}
+		}
+		begin = end
+		m.Indptr[major+1] = k
+	}
+	m.Ind = m.Ind[:k]
+	m.Data = m.Data[:k]
+}
@@ -176,3 +176,34 @@ func TestSparseMatrixSet(t *testing.T) {
 		}
 	}
 }
+
+func TestSparseMatrixAtSorted(t *testing.T) {
+	// 1, 0, 2, 0,
+	// 0, 0, 0, 0,
+	// 0, 3, 4, 5,
+	m := &SparseMatrix{
+		I: 3, J: 4,
+		Indptr: []int{0, 2, 2, 5},
+		Ind:    []int{0, 2, 1, 2, 3},
+		Data:   []float64{1, 2, 3, 4, 5},
+	}
+
+	for i := 0; i < m.I; i++ {
+		for j := 0; j < m.J; j++ {
+			if v, e := m.AtSorted(i, j), m.At(i, j); v != e {
+				t.Errorf("Expected %v at (%d, %d) but received %v", e, i, j, v)
+			}
+		}
+	}
+
+	for _, ij := range [][2]int{{-1, 0}, {3, 0}, {0, -1}, {0, 4}} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic for index (%d, %d)", ij[0], ij[1])
+				}
+			}()
+			m.AtSorted(ij[0], ij[1])
+		}()
+	}
+}
@@ -0,0 +1,257 @@
+package sparse
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Sparser = (*BSR)(nil)
+)
+
+// BSR is a Block Sparse Row format sparse matrix implementation and implements the Matrix interface
+// from gonum/matrix.  BSR is a generalisation of CSR where, rather than individual elements, the
+// matrix is partitioned into dense blocks of br * bc (block rows * block columns) elements and only
+// blocks containing non-zero elements are stored.  The block structure is compressed by block row in
+// the same way as CSR i.e. indptr[bi] to indptr[bi+1] indexes the blocks stored in block row bi,
+// with ind holding the block column index of each stored block.  The values of the stored blocks are
+// held in data as consecutive dense br * bc blocks, each in row major order, so the element at row
+// ii, column jj within stored block p is held at data[p*br*bc + ii*bc + jj].
+//
+// BSR suits matrices with a natural block structure, such as those arising from finite element
+// discretisations with several degrees of freedom per node, as a single index is stored per block
+// rather than per element and operations may work on whole dense blocks at a time.  As stored
+// blocks are dense, any zero elements within them are explicitly stored.
+type BSR struct {
+	r, c   int
+	br, bc int
+	indptr []int
+	ind    []int
+	data   []float64
+}
+
+// NewBSR creates a new Block Sparse Row format sparse matrix with r rows and c columns partitioned
+// into blocks of br * bc elements.  indptr, ind and data are the block row pointers, block column
+// indices and row major block values respectively and are used as the backing storage of the
+// matrix.  If indptr, ind and data are all nil, an empty matrix is created.  NewBSR will panic if
+// the dimensions are negative, the block dimensions are not positive or do not divide the matrix
+// dimensions, the slices are of inconsistent lengths or any block column index is out of range.
+func NewBSR(r, c, br, bc int, indptr, ind []int, data []float64) *BSR {
+	if r < 0 {
+		panic(mat.ErrRowAccess)
+	}
+	if c < 0 {
+		panic(mat.ErrColAccess)
+	}
+	if br <= 0 || bc <= 0 || r%br != 0 || c%bc != 0 {
+		panic(mat.ErrShape)
+	}
+	if indptr == nil && ind == nil && data == nil {
+		indptr = make([]int, r/br+1)
+	}
+	if len(indptr) != r/br+1 || indptr[0] != 0 || indptr[r/br] != len(ind) || len(data) != len(ind)*br*bc {
+		panic(mat.ErrShape)
+	}
+	for bi := 0; bi < r/br; bi++ {
+		if indptr[bi+1] < indptr[bi] {
+			panic(mat.ErrShape)
+		}
+	}
+	for _, bj := range ind {
+		if bj < 0 || bj >= c/bc {
+			panic(mat.ErrColAccess)
+		}
+	}
+
+	return &BSR{r: r, c: c, br: br, bc: bc, indptr: indptr, ind: ind, data: data}
+}
+
+// Dims returns the size of the matrix as the number of rows and columns
+func (b *BSR) Dims() (r, c int) {
+	return b.r, b.c
+}
+
+// BlockDims returns the size of the blocks of the matrix as the number of rows and columns
+// in each block.
+func (b *BSR) BlockDims() (br, bc int) {
+	return b.br, b.bc
+}
+
+// At returns the element of the matrix located at row i and column j.  The block containing the
+// element is located by scanning the block column indices of the block row containing row i and
+// the element then read from within the block.  At will panic if specified values for i or j fall
+// outside the dimensions of the matrix.
+func (b *BSR) At(i, j int) float64 {
+	if i < 0 || i >= b.r {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= b.c {
+		panic(mat.ErrColAccess)
+	}
+
+	bi, bj := i/b.br, j/b.bc
+	for p := b.indptr[bi]; p < b.indptr[bi+1]; p++ {
+		if b.ind[p] == bj {
+			return b.data[p*b.br*b.bc+(i%b.br)*b.bc+j%b.bc]
+		}
+	}
+	return 0
+}
+
+// T transposes the matrix.  This is an implicit transpose, wrapping the matrix in a mat.Transpose type.
+func (b *BSR) T() mat.Matrix {
+	return mat.Transpose{Matrix: b}
+}
+
+// DoNonZero calls the function fn for each of the stored elements of the receiver i.e. every
+// element of every stored block, including any explicitly stored zeros within the blocks.
+// The function fn takes a row/column index and the element value of the receiver at (i, j).
+func (b *BSR) DoNonZero(fn func(i, j int, v float64)) {
+	size := b.br * b.bc
+	for bi := 0; bi < len(b.indptr)-1; bi++ {
+		for p := b.indptr[bi]; p < b.indptr[bi+1]; p++ {
+			block := b.data[p*size : (p+1)*size]
+			for ii := 0; ii < b.br; ii++ {
+				for jj := 0; jj < b.bc; jj++ {
+					fn(bi*b.br+ii, b.ind[p]*b.bc+jj, block[ii*b.bc+jj])
+				}
+			}
+		}
+	}
+}
+
+// NNZ returns the number of stored elements in the sparse matrix i.e. the number of stored
+// blocks multiplied by the number of elements in each block.
+func (b *BSR) NNZ() int {
+	return len(b.data)
+}
+
+// NNZB returns the number of stored blocks in the sparse matrix.
+func (b *BSR) NNZB() int {
+	return len(b.ind)
+}
+
+// Raw returns the block dimensions along with the block row pointers, block column indices and
+// row major block values backing the receiver.  The returned slices share storage with the
+// receiver.
+func (b *BSR) Raw() (br, bc int, indptr, ind []int, data []float64) {
+	return b.br, b.bc, b.indptr, b.ind, b.data
+}
+
+// FromCSR sets the receiver to a Block Sparse Row format copy of the CSR matrix a partitioned
+// into blocks of br * bc elements.  A block is stored for every block containing at least one
+// stored element of a with the block column indices of each block row sorted in ascending
+// order.  Duplicate elements in a are summed.  The receiver will not share underlying storage
+// with a.  FromCSR will panic if br or bc are not positive or do not divide the dimensions of a.
+func (b *BSR) FromCSR(a *CSR, br, bc int) {
+	r, c := a.Dims()
+	if br <= 0 || bc <= 0 || r%br != 0 || c%bc != 0 {
+		panic(mat.ErrShape)
+	}
+	size := br * bc
+	nbr, nbc := r/br, c/bc
+
+	// pos maps block column index to position of the stored block within the current block row
+	pos := getInts(nbc, false)
+	defer putInts(pos)
+	for k := range pos {
+		pos[k] = -1
+	}
+
+	indptr := make([]int, nbr+1)
+	var ind []int
+	var data []float64
+	for bi := 0; bi < nbr; bi++ {
+		begin := len(ind)
+		for i := bi * br; i < (bi+1)*br; i++ {
+			for k := a.matrix.Indptr[i]; k < a.matrix.Indptr[i+1]; k++ {
+				if bj := a.matrix.Ind[k] / bc; pos[bj] == -1 {
+					pos[bj] = 0
+					ind = append(ind, bj)
+				}
+			}
+		}
+		sort.Ints(ind[begin:])
+		for p := begin; p < len(ind); p++ {
+			pos[ind[p]] = p
+		}
+		data = append(data, make([]float64, (len(ind)-begin)*size)...)
+
+		for i := bi * br; i < (bi+1)*br; i++ {
+			for k := a.matrix.Indptr[i]; k < a.matrix.Indptr[i+1]; k++ {
+				j := a.matrix.Ind[k]
+				data[pos[j/bc]*size+(i%br)*bc+j%bc] += a.matrix.Data[k]
+			}
+		}
+		for p := begin; p < len(ind); p++ {
+			pos[ind[p]] = -1
+		}
+		indptr[bi+1] = len(ind)
+	}
+
+	*b = BSR{r: r, c: c, br: br, bc: bc, indptr: indptr, ind: ind, data: data}
+}
+
+// ToCSR returns a CSR (Compressed Sparse Row)(AKA CRS (Compressed Row Storage)) sparse format
+// version of the matrix.  Zero elements within stored blocks are not included in the returned
+// matrix.  The returned CSR matrix will not share underlying storage with the receiver nor is
+// the receiver modified by this call.
+func (b *BSR) ToCSR() *CSR {
+	size := b.br * b.bc
+	indptr := make([]int, b.r+1)
+	var ind []int
+	var data []float64
+
+	for i := 0; i < b.r; i++ {
+		bi, ii := i/b.br, i%b.br
+		for p := b.indptr[bi]; p < b.indptr[bi+1]; p++ {
+			row := b.data[p*size+ii*b.bc : p*size+(ii+1)*b.bc]
+			for jj, v := range row {
+				if v != 0 {
+					ind = append(ind, b.ind[p]*b.bc+jj)
+					data = append(data, v)
+				}
+			}
+		}
+		indptr[i+1] = len(ind)
+	}
+
+	return NewCSR(b.r, b.c, indptr, ind, data)
+}
+
+// MulVecTo performs matrix vector multiplication (dst+=A*x or dst+=A^T*x), where A is
+// the receiver, and stores the result in dst.  Each stored block is multiplied as a
+// dense block.  MulVecTo panics if ac != len(x) or ar != len(dst)
+func (b *BSR) MulVecTo(dst []float64, trans bool, x []float64) {
+	ar, ac := b.Dims()
+	if trans {
+		ar, ac = ac, ar
+	}
+	if ac != len(x) || ar != len(dst) {
+		panic(mat.ErrShape)
+	}
+
+	size := b.br * b.bc
+	for bi := 0; bi < len(b.indptr)-1; bi++ {
+		for p := b.indptr[bi]; p < b.indptr[bi+1]; p++ {
+			block := b.data[p*size : (p+1)*size]
+			roff, coff := bi*b.br, b.ind[p]*b.bc
+			for ii := 0; ii < b.br; ii++ {
+				row := block[ii*b.bc : (ii+1)*b.bc]
+				if trans {
+					xi := x[roff+ii]
+					for jj, v := range row {
+						dst[coff+jj] += v * xi
+					}
+					continue
+				}
+				var sum float64
+				for jj, v := range row {
+					sum += v * x[coff+jj]
+				}
+				dst[roff+ii] += sum
+			}
+		}
+	}
+}
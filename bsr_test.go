@@ -0,0 +1,181 @@
+package sparse
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestBSR(t *testing.T) {
+	var tests = []struct {
+		r, c   int
+		br, bc int
+		data   []float64
+		indptr []int
+		ind    []int
+		vals   []float64
+	}{
+		{
+			r: 4, c: 6,
+			br: 2, bc: 2,
+			data: []float64{
+				1, 2, 0, 0, 0, 0,
+				0, 3, 0, 0, 4, 0,
+				0, 0, 5, 0, 0, 0,
+				0, 0, 0, 0, 0, 0,
+			},
+			indptr: []int{0, 2, 3},
+			ind:    []int{0, 2, 1},
+			vals:   []float64{1, 2, 0, 3, 0, 0, 4, 0, 5, 0, 0, 0},
+		},
+		{
+			r: 3, c: 4,
+			br: 3, bc: 1,
+			data: []float64{
+				0, 1, 0, 0,
+				0, 0, 0, 2,
+				0, 3, 0, 0,
+			},
+			indptr: []int{0, 2},
+			ind:    []int{1, 3},
+			vals:   []float64{1, 0, 3, 0, 2, 0},
+		},
+		{
+			r: 2, c: 2,
+			br: 1, bc: 1,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+			indptr: []int{0, 0, 0},
+			ind:    nil,
+			vals:   nil,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.data)
+		csr := CreateCSR(test.r, test.c, test.data).(*CSR)
+
+		var bsr BSR
+		bsr.FromCSR(csr, test.br, test.bc)
+
+		if r, c := bsr.Dims(); r != test.r || c != test.c {
+			t.Logf("Expected dimensions %dx%d but received %dx%d\n", test.r, test.c, r, c)
+			t.Fail()
+		}
+		if br, bc := bsr.BlockDims(); br != test.br || bc != test.bc {
+			t.Logf("Expected block dimensions %dx%d but received %dx%d\n", test.br, test.bc, br, bc)
+			t.Fail()
+		}
+		_, _, indptr, ind, vals := bsr.Raw()
+		if !reflect.DeepEqual(indptr, test.indptr) || !reflect.DeepEqual(ind, test.ind) || !reflect.DeepEqual(vals, test.vals) {
+			t.Logf("Expected indptr=%v, ind=%v, data=%v but received indptr=%v, ind=%v, data=%v\n",
+				test.indptr, test.ind, test.vals, indptr, ind, vals)
+			t.Fail()
+		}
+		if !mat.Equal(expected, &bsr) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(&bsr))
+			t.Fail()
+		}
+		if bsr.NNZB() != len(test.ind) || bsr.NNZ() != len(test.vals) {
+			t.Logf("Expected %d blocks, %d elements but found %d, %d\n", len(test.ind), len(test.vals), bsr.NNZB(), bsr.NNZ())
+			t.Fail()
+		}
+
+		visited := mat.NewDense(test.r, test.c, nil)
+		bsr.DoNonZero(func(i, j int, v float64) {
+			visited.Set(i, j, visited.At(i, j)+v)
+		})
+		if !mat.Equal(expected, visited) {
+			t.Logf("DoNonZero: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(visited))
+			t.Fail()
+		}
+
+		back := bsr.ToCSR()
+		if !mat.Equal(expected, back) || back.NNZ() != csr.NNZ() {
+			t.Logf("ToCSR: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(back))
+			t.Fail()
+		}
+
+		fromRaw := NewBSR(test.r, test.c, test.br, test.bc, test.indptr, test.ind, test.vals)
+		if !mat.Equal(expected, fromRaw) {
+			t.Logf("NewBSR: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(fromRaw))
+			t.Fail()
+		}
+
+		for _, trans := range []bool{false, true} {
+			var a mat.Matrix = expected
+			if trans {
+				a = expected.T()
+			}
+			ar, ac := a.Dims()
+			x := make([]float64, ac)
+			for i := range x {
+				x[i] = float64(i + 1)
+			}
+			dst := make([]float64, ar)
+			for i := range dst {
+				dst[i] = 1
+			}
+			var want mat.VecDense
+			want.MulVec(a, mat.NewVecDense(ac, x))
+			want.AddVec(&want, mat.NewVecDense(ar, dst))
+
+			bsr.MulVecTo(dst, trans, x)
+			if !mat.Equal(&want, mat.NewVecDense(ar, dst)) {
+				t.Logf("MulVecTo (trans=%t): Expected %v but received %v\n", trans, want.RawVector().Data, dst)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestBSRDuplicates(t *testing.T) {
+	csr := NewCSR(2, 2, []int{0, 3, 3}, []int{1, 0, 1}, []float64{1, 2, 3})
+	expected := mat.NewDense(2, 2, []float64{
+		2, 4,
+		0, 0,
+	})
+
+	var bsr BSR
+	bsr.FromCSR(csr, 1, 2)
+	if !mat.Equal(expected, &bsr) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(&bsr))
+	}
+}
+
+func TestNewBSRInvalid(t *testing.T) {
+	var tests = []struct {
+		desc     string
+		r, c     int
+		br, bc   int
+		indptr   []int
+		ind      []int
+		data     []float64
+		expected error
+	}{
+		{desc: "negative rows", r: -2, c: 2, br: 1, bc: 1, expected: mat.ErrRowAccess},
+		{desc: "zero block size", r: 2, c: 2, br: 0, bc: 1, expected: mat.ErrShape},
+		{desc: "block rows do not divide rows", r: 3, c: 2, br: 2, bc: 1, expected: mat.ErrShape},
+		{desc: "block cols do not divide cols", r: 2, c: 3, br: 1, bc: 2, expected: mat.ErrShape},
+		{desc: "indptr length", r: 2, c: 2, br: 1, bc: 1, indptr: []int{0, 0}, expected: mat.ErrShape},
+		{desc: "data length", r: 2, c: 2, br: 1, bc: 1, indptr: []int{0, 1, 1}, ind: []int{0}, data: []float64{1, 2}, expected: mat.ErrShape},
+		{desc: "decreasing indptr", r: 2, c: 2, br: 1, bc: 1, indptr: []int{0, 2, 1}, ind: []int{0}, data: []float64{1}, expected: mat.ErrShape},
+		{desc: "block column out of range", r: 2, c: 2, br: 1, bc: 1, indptr: []int{0, 1, 1}, ind: []int{2}, data: []float64{1}, expected: mat.ErrColAccess},
+	}
+
+	for _, test := range tests {
+		func() {
+			defer func() {
+				if r := recover(); r != test.expected {
+					t.Errorf("%s: Expected panic %v but received %v", test.desc, test.expected, r)
+				}
+			}()
+			NewBSR(test.r, test.c, test.br, test.bc, test.indptr, test.ind, test.data)
+		}()
+	}
+}
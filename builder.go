@@ -0,0 +1,233 @@
+package sparse
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/james-bowman/sparse/blas"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrUnsortedIndices is the panic value used when indices that are required to be sorted
+// in strictly ascending order (and so also free of duplicates) are not.
+var ErrUnsortedIndices = errors.New("sparse: indices not sorted in strictly ascending order")
+
+// CSCBuilder is used to incrementally construct CSC (Compressed Sparse Column) matrices.
+// Elements are appended to the builder, in any order, as (row, column, value) triplets and
+// buffered until Build is called at which point they are compressed into column major order.
+// This provides an efficient construction path where a CSC matrix is ultimately required
+// without the need to construct and then convert an intermediate matrix format.
+type CSCBuilder struct {
+	r, c int
+	rows []int
+	cols []int
+	data []float64
+}
+
+// NewCSCBuilder creates a new CSCBuilder for constructing CSC matrices of r * c dimensions
+// (rows * columns).
+func NewCSCBuilder(r, c int) *CSCBuilder {
+	if r < 0 {
+		panic(mat.ErrRowAccess)
+	}
+	if c < 0 {
+		panic(mat.ErrColAccess)
+	}
+	return &CSCBuilder{r: r, c: c}
+}
+
+// Append appends the element v located at row i and column j of the matrix being built.
+// Elements may be appended in any order and duplicate elements will be summed together
+// when the matrix is built.  Append will panic if i or j fall outside the dimensions
+// of the matrix.
+func (b *CSCBuilder) Append(i, j int, v float64) {
+	if i < 0 || i >= b.r {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= b.c {
+		panic(mat.ErrColAccess)
+	}
+	b.rows = append(b.rows, i)
+	b.cols = append(b.cols, j)
+	b.data = append(b.data, v)
+}
+
+// Build compresses the elements appended to the builder into column major order returning a
+// new CSC matrix.  Duplicate elements are summed together and the row indices within each
+// column of the returned matrix are sorted in ascending order.  The returned matrix does not
+// share storage with the builder and so the builder may continue to be used after calling
+// Build.
+func (b *CSCBuilder) Build() *CSC {
+	// compress into row major order first so that the subsequent compression into column
+	// major order visits elements in ascending row order leaving row indices sorted
+	ia, ja, d := compress(b.rows, b.cols, b.data, b.r)
+	rows := make([]int, len(ja))
+	for i := 0; i < b.r; i++ {
+		for k := ia[i]; k < ia[i+1]; k++ {
+			rows[k] = i
+		}
+	}
+
+	indptr, ind, data := compress(ja, rows, d, b.c)
+	ind, data = dedupe(indptr, ind, data, b.c, b.r)
+	return NewCSC(b.r, b.c, indptr, ind, data)
+}
+
+// AppendRow appends a new row to the bottom of the matrix, incrementing the number of rows,
+// with the non-zero elements of the row specified by their column indices, ind, and
+// corresponding values, data.  This allows CSR matrices to be constructed efficiently
+// one row at a time, for example when streaming rows from a data source, without
+// building an intermediate format.  The values of ind and data are copied into the
+// receiver's storage.  AppendRow will panic with mat.ErrShape if ind and data are not
+// the same length, mat.ErrColAccess if any of the column indices are out of range and
+// ErrUnsortedIndices if the column indices are not sorted in strictly ascending order.
+func (c *CSR) AppendRow(ind []int, data []float64) {
+	appendCompressed(&c.matrix, ind, data, mat.ErrColAccess)
+}
+
+// AppendCol appends a new column to the right of the matrix, incrementing the number of
+// columns, with the non-zero elements of the column specified by their row indices, ind,
+// and corresponding values, data.  This allows CSC matrices to be constructed
+// efficiently one column at a time without building an intermediate format.  The values
+// of ind and data are copied into the receiver's storage.  AppendCol will panic with
+// mat.ErrShape if ind and data are not the same length, mat.ErrRowAccess if any of the
+// row indices are out of range and ErrUnsortedIndices if the row indices are not sorted
+// in strictly ascending order.
+func (c *CSC) AppendCol(ind []int, data []float64) {
+	appendCompressed(&c.matrix, ind, data, mat.ErrRowAccess)
+}
+
+// appendCompressed appends a new row (or column for column major matrices) to the
+// compressed sparse matrix m after validating ind and data.  indErr is the panic value
+// used if any of the indices are out of range.
+func appendCompressed(m *blas.SparseMatrix, ind []int, data []float64, indErr error) {
+	if len(ind) != len(data) {
+		panic(mat.ErrShape)
+	}
+	for k, j := range ind {
+		if j < 0 || j >= m.J {
+			panic(indErr)
+		}
+		if k > 0 && j <= ind[k-1] {
+			panic(ErrUnsortedIndices)
+		}
+	}
+
+	if len(m.Indptr) == 0 {
+		m.Indptr = append(m.Indptr, 0)
+	}
+	m.Ind = append(m.Ind, ind...)
+	m.Data = append(m.Data, data...)
+	m.Indptr = append(m.Indptr, len(m.Ind))
+	m.I++
+}
+
+// ConcurrentBuilder is used to construct CSR (Compressed Sparse Row) matrices in parallel
+// from multiple goroutines, for example when assembling a matrix from independent
+// contributions (such as finite elements) across multiple cores.  Rather than
+// synchronising every append through a global lock, each goroutine obtains its own
+// LocalBuilder, by calling Local, to which it appends elements without contention.
+// Build then merges the elements of all the local builders into a single CSR matrix.
+//
+// Local must be called once per goroutine and each LocalBuilder must only be used by
+// the goroutine that obtained it.  Build must only be called once all goroutines have
+// finished appending elements.
+type ConcurrentBuilder struct {
+	r, c   int
+	mu     sync.Mutex
+	locals []*LocalBuilder
+}
+
+// NewConcurrentBuilder creates a new ConcurrentBuilder for constructing CSR matrices of
+// r * c dimensions (rows * columns).
+func NewConcurrentBuilder(r, c int) *ConcurrentBuilder {
+	if r < 0 {
+		panic(mat.ErrRowAccess)
+	}
+	if c < 0 {
+		panic(mat.ErrColAccess)
+	}
+	return &ConcurrentBuilder{r: r, c: c}
+}
+
+// Local returns a new LocalBuilder, registered with the receiver, for the exclusive use
+// of the calling goroutine.  Local is safe to call concurrently from multiple goroutines.
+func (b *ConcurrentBuilder) Local() *LocalBuilder {
+	l := &LocalBuilder{r: b.r, c: b.c}
+	b.mu.Lock()
+	b.locals = append(b.locals, l)
+	b.mu.Unlock()
+	return l
+}
+
+// Build merges the elements appended to all of the local builders returning a new CSR
+// matrix.  Each local builder is first compressed into a CSR matrix and the results then
+// merged pairwise, in parallel, via a tree reduction such that the merge requires
+// O(log p) parallel steps for p local builders.  Duplicate elements, whether appended
+// to the same or different local builders, are summed together.  The returned matrix
+// does not share storage with the builders.
+func (b *ConcurrentBuilder) Build() *CSR {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.locals) == 0 {
+		return NewCSR(b.r, b.c, make([]int, b.r+1), nil, nil)
+	}
+
+	parts := make([]*CSR, len(b.locals))
+	var wg sync.WaitGroup
+	for i, l := range b.locals {
+		wg.Add(1)
+		go func(i int, l *LocalBuilder) {
+			defer wg.Done()
+			parts[i] = NewCOO(l.r, l.c, l.rows, l.cols, l.data).ToCSR()
+		}(i, l)
+	}
+	wg.Wait()
+
+	for len(parts) > 1 {
+		merged := make([]*CSR, (len(parts)+1)/2)
+		for i := 0; i+1 < len(parts); i += 2 {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				var m CSR
+				m.Add(parts[i], parts[i+1])
+				merged[i/2] = &m
+			}(i)
+		}
+		if len(parts)%2 == 1 {
+			merged[len(merged)-1] = parts[len(parts)-1]
+		}
+		wg.Wait()
+		parts = merged
+	}
+
+	return parts[0]
+}
+
+// LocalBuilder accumulates elements appended by a single goroutine for a
+// ConcurrentBuilder.  LocalBuilders are obtained by calling ConcurrentBuilder.Local
+// and are not safe for concurrent use by multiple goroutines.
+type LocalBuilder struct {
+	r, c int
+	rows []int
+	cols []int
+	data []float64
+}
+
+// Append appends the element v located at row i and column j of the matrix being built.
+// Elements may be appended in any order and duplicate elements will be summed together
+// when the matrix is built.  Append will panic if i or j fall outside the dimensions
+// of the matrix.
+func (l *LocalBuilder) Append(i, j int, v float64) {
+	if i < 0 || i >= l.r {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= l.c {
+		panic(mat.ErrColAccess)
+	}
+	l.rows = append(l.rows, i)
+	l.cols = append(l.cols, j)
+	l.data = append(l.data, v)
+}
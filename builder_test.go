@@ -0,0 +1,210 @@
+package sparse
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCSCBuilder(t *testing.T) {
+	type triplet struct {
+		i, j int
+		v    float64
+	}
+	var tests = []struct {
+		r, c     int
+		elements []triplet
+		expected []float64
+		nnz      int
+	}{
+		{
+			r: 3, c: 4,
+			elements: []triplet{
+				{2, 3, 6}, {0, 0, 1}, {2, 0, 3}, {1, 2, 4}, {0, 3, 7}, {2, 2, 3}, {1, 1, 2},
+			},
+			expected: []float64{
+				1, 0, 0, 7,
+				0, 2, 4, 0,
+				3, 0, 3, 6,
+			},
+			nnz: 7,
+		},
+		{
+			r: 3, c: 3,
+			elements: []triplet{
+				{2, 1, 1}, {0, 1, 2}, {2, 1, 3}, {1, 1, 4}, {0, 1, -2},
+			},
+			expected: []float64{
+				0, 0, 0,
+				0, 4, 0,
+				0, 4, 0,
+			},
+			nnz: 3,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		b := NewCSCBuilder(test.r, test.c)
+		for _, e := range test.elements {
+			b.Append(e.i, e.j, e.v)
+		}
+		csc := b.Build()
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		if !mat.Equal(expected, csc) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csc))
+			t.Fail()
+		}
+		if csc.NNZ() != test.nnz {
+			t.Logf("Expected NNZ of %d but received %d", test.nnz, csc.NNZ())
+			t.Fail()
+		}
+
+		raw := csc.RawMatrix()
+		if len(raw.Indptr) != test.c+1 {
+			t.Logf("Expected column pointers of length %d but received %d", test.c+1, len(raw.Indptr))
+			t.Fail()
+		}
+		for j := 0; j < test.c; j++ {
+			rows := raw.Ind[raw.Indptr[j]:raw.Indptr[j+1]]
+			if !sort.IntsAreSorted(rows) {
+				t.Logf("Expected sorted row indices for column %d but received %v", j, rows)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestConcurrentBuilder(t *testing.T) {
+	var tests = []struct {
+		r, c       int
+		goroutines int
+	}{
+		{r: 20, c: 30, goroutines: 1},
+		{r: 20, c: 30, goroutines: 4},
+		{r: 50, c: 40, goroutines: 7},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		builder := NewConcurrentBuilder(test.r, test.c)
+		expected := mat.NewDense(test.r, test.c, nil)
+
+		// each goroutine appends every element of the matrix (creating duplicates across
+		// local builders) with a value depending upon the goroutine index
+		var wg sync.WaitGroup
+		for g := 0; g < test.goroutines; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				local := builder.Local()
+				for i := 0; i < test.r; i++ {
+					for j := (i + g) % 3; j < test.c; j += 3 {
+						local.Append(i, j, float64(g+1))
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		for g := 0; g < test.goroutines; g++ {
+			for i := 0; i < test.r; i++ {
+				for j := (i + g) % 3; j < test.c; j += 3 {
+					expected.Set(i, j, expected.At(i, j)+float64(g+1))
+				}
+			}
+		}
+
+		result := builder.Build()
+		if !mat.Equal(expected, result) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+			t.Fail()
+		}
+	}
+}
+
+func TestConcurrentBuilderEmpty(t *testing.T) {
+	result := NewConcurrentBuilder(3, 4).Build()
+	if r, c := result.Dims(); r != 3 || c != 4 || result.NNZ() != 0 {
+		t.Errorf("Expected empty 3x4 matrix but received %dx%d with %d non zero elements", r, c, result.NNZ())
+	}
+}
+
+func TestCSRCSCAppend(t *testing.T) {
+	rows := []struct {
+		ind  []int
+		data []float64
+	}{
+		{ind: []int{0, 2}, data: []float64{1, 2}},
+		{ind: []int{}, data: []float64{}},
+		{ind: []int{1, 2, 3}, data: []float64{3, 4, 5}},
+	}
+	expected := mat.NewDense(3, 4, []float64{
+		1, 0, 2, 0,
+		0, 0, 0, 0,
+		0, 3, 4, 5,
+	})
+
+	csr := NewCSR(0, 4, nil, nil, nil)
+	csc := NewCSC(4, 0, nil, nil, nil)
+	for _, row := range rows {
+		csr.AppendRow(row.ind, row.data)
+		csc.AppendCol(row.ind, row.data)
+	}
+
+	if !mat.Equal(expected, csr) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csr))
+	}
+	if !mat.Equal(expected.T(), csc) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected.T()), mat.Formatted(csc))
+	}
+	if errs := Validate(csr); len(errs) != 0 {
+		t.Errorf("Appended CSR is invalid: %v", errs)
+	}
+
+	// appending to an existing matrix
+	existing := CreateCSR(1, 4, []float64{0, 6, 0, 7}).(*CSR)
+	existing.AppendRow([]int{3}, []float64{8})
+	want := mat.NewDense(2, 4, []float64{
+		0, 6, 0, 7,
+		0, 0, 0, 8,
+	})
+	if !mat.Equal(want, existing) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(want), mat.Formatted(existing))
+	}
+
+	var tests = []struct {
+		ind      []int
+		data     []float64
+		expected interface{}
+	}{
+		{ind: []int{0, 1}, data: []float64{1}, expected: mat.ErrShape},
+		{ind: []int{0, 4}, data: []float64{1, 2}, expected: mat.ErrColAccess},
+		{ind: []int{-1}, data: []float64{1}, expected: mat.ErrColAccess},
+		{ind: []int{2, 1}, data: []float64{1, 2}, expected: ErrUnsortedIndices},
+		{ind: []int{1, 1}, data: []float64{1, 2}, expected: ErrUnsortedIndices},
+	}
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		m := NewCSR(0, 4, nil, nil, nil)
+		func() {
+			defer func() {
+				if r := recover(); r != test.expected {
+					t.Logf("Expected panic %v but received %v\n", test.expected, r)
+					t.Fail()
+				}
+			}()
+			m.AppendRow(test.ind, test.data)
+		}()
+		if r, _ := m.Dims(); r != 0 {
+			t.Logf("Expected matrix to be unmodified after panic but has %d rows\n", r)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package sparse
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ mat.CMatrix = (*CSRComplex)(nil)
+)
+
+// CSRComplex is a Compressed Sparse Row format sparse matrix of complex128 values and implements the
+// CMatrix interface from gonum/mat.  CSRComplex mirrors the storage layout of the float64 CSR type i.e.
+// indptr[i] to indptr[i+1] indexes the stored elements of row i with ind holding the column index of each
+// stored element and data its (complex) value.  This allows complex-valued sparse matrices, for example
+// those arising in signal processing or frequency domain analysis, to be stored and multiplied by complex
+// vectors efficiently.
+type CSRComplex struct {
+	i, j   int
+	indptr []int
+	ind    []int
+	data   []complex128
+}
+
+// NewCSRComplex creates a new Compressed Sparse Row format sparse matrix of complex128 values.
+// The matrix is initialised to the size of the specified r * c dimensions (rows * columns)
+// with the specified slices containing row pointers and cols indexes of non-zero elements
+// and the non-zero data values themselves respectively.  If ia, ja and data are all nil, an
+// empty matrix is created.  Otherwise the supplied slices will be used as the backing storage to
+// the matrix so changes to values of the slices will be reflected in the created matrix
+// and vice versa.
+func NewCSRComplex(r int, c int, ia []int, ja []int, data []complex128) *CSRComplex {
+	if r < 0 {
+		panic(mat.ErrRowAccess)
+	}
+	if c < 0 {
+		panic(mat.ErrColAccess)
+	}
+	if ia == nil && ja == nil && data == nil {
+		ia = make([]int, r+1)
+	}
+
+	return &CSRComplex{i: r, j: c, indptr: ia, ind: ja, data: data}
+}
+
+// Dims returns the size of the matrix as the number of rows and columns
+func (c *CSRComplex) Dims() (int, int) {
+	return c.i, c.j
+}
+
+// At returns the element of the matrix located at row i and column j.  At will panic if specified values
+// for i or j fall outside the dimensions of the matrix.
+func (c *CSRComplex) At(i, j int) complex128 {
+	if i < 0 || i >= c.i {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= c.j {
+		panic(mat.ErrColAccess)
+	}
+
+	for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+		if c.ind[k] == j {
+			return c.data[k]
+		}
+	}
+	return 0
+}
+
+// Set sets the element of the matrix located at row i and column j to value v.  If the element is
+// not already stored, it is inserted at the end of row i, updating the sparsity pattern.  As for
+// CSR, this is relatively expensive and so CSRComplex matrices are better constructed from
+// complete index and data slices.  Set will panic if specified values for i or j fall outside the
+// dimensions of the matrix.
+func (c *CSRComplex) Set(i, j int, v complex128) {
+	if i < 0 || i >= c.i {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= c.j {
+		panic(mat.ErrColAccess)
+	}
+
+	for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+		if c.ind[k] == j {
+			c.data[k] = v
+			return
+		}
+	}
+
+	if v == 0 {
+		// don't bother storing new zero values
+		return
+	}
+
+	p := c.indptr[i+1]
+	c.ind = append(c.ind, 0)
+	copy(c.ind[p+1:], c.ind[p:])
+	c.ind[p] = j
+
+	c.data = append(c.data, 0)
+	copy(c.data[p+1:], c.data[p:])
+	c.data[p] = v
+
+	for n := i + 1; n <= c.i; n++ {
+		c.indptr[n]++
+	}
+}
+
+// T transposes the matrix.  This is an implicit transpose, wrapping the matrix in a mat.CTranspose type.
+func (c *CSRComplex) T() mat.CMatrix {
+	return mat.CTranspose{CMatrix: c}
+}
+
+// H returns the conjugate transpose of the matrix.  This is an implicit conjugate transpose, wrapping
+// the matrix in a mat.ConjTranspose type.
+func (c *CSRComplex) H() mat.CMatrix {
+	return mat.ConjTranspose{CMatrix: c}
+}
+
+// NNZ returns the Number of Non Zero elements in the sparse matrix.
+func (c *CSRComplex) NNZ() int {
+	return len(c.data)
+}
+
+// DoNonZero calls the function fn for each of the stored non-zero elements of the receiver.
+// The function fn takes a row/column index and the element value of the receiver at
+// (i, j).  The order of visiting to each non-zero element is row major.
+func (c *CSRComplex) DoNonZero(fn func(i, j int, v complex128)) {
+	for i := 0; i < c.i; i++ {
+		for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+			fn(i, c.ind[k], c.data[k])
+		}
+	}
+}
+
+// Raw returns the row pointers, column indices and values backing the receiver.  The returned
+// slices share storage with the receiver.
+func (c *CSRComplex) Raw() (indptr, ind []int, data []complex128) {
+	return c.indptr, c.ind, c.data
+}
+
+// ToCDense returns a mat.CDense dense format version of the matrix.  The returned mat.CDense
+// matrix will not share underlying storage with the receiver nor is the receiver modified by this call.
+func (c *CSRComplex) ToCDense() *mat.CDense {
+	dense := mat.NewCDense(c.i, c.j, nil)
+	c.DoNonZero(func(i, j int, v complex128) {
+		dense.Set(i, j, dense.At(i, j)+v)
+	})
+	return dense
+}
+
+// MulVecTo performs matrix vector multiplication (dst+=A*x or dst+=A^T*x), where A is
+// the receiver, and stores the result in dst.  Note that trans specifies the transpose
+// of A rather than the conjugate transpose; to multiply by the conjugate transpose,
+// conjugate x and dst before and after the call.  MulVecTo panics if ac != len(x) or
+// ar != len(dst)
+func (c *CSRComplex) MulVecTo(dst []complex128, trans bool, x []complex128) {
+	ar, ac := c.Dims()
+	if trans {
+		ar, ac = ac, ar
+	}
+	if ac != len(x) || ar != len(dst) {
+		panic(mat.ErrShape)
+	}
+
+	for i := 0; i < c.i; i++ {
+		if trans {
+			xi := x[i]
+			for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+				dst[c.ind[k]] += c.data[k] * xi
+			}
+			continue
+		}
+		var sum complex128
+		for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+			sum += c.data[k] * x[c.ind[k]]
+		}
+		dst[i] += sum
+	}
+}
@@ -0,0 +1,110 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCSRComplex(t *testing.T) {
+	// 1+i, 0,  2,
+	// 0,   0,  0,
+	// 0,  -3i, 4-i,
+	csr := NewCSRComplex(3, 3, []int{0, 2, 2, 4}, []int{0, 2, 1, 2}, []complex128{1 + 1i, 2, -3i, 4 - 1i})
+	expected := mat.NewCDense(3, 3, []complex128{
+		1 + 1i, 0, 2,
+		0, 0, 0,
+		0, -3i, 4 - 1i,
+	})
+
+	if !mat.CEqual(expected, csr) {
+		t.Errorf("Expected:\n%v\n but received:\n%v", expected, csr.ToCDense())
+	}
+	if !mat.CEqual(expected, csr.ToCDense()) {
+		t.Errorf("Expected dense:\n%v\n but received:\n%v", expected, csr.ToCDense())
+	}
+	if csr.NNZ() != 4 {
+		t.Errorf("Expected 4 stored elements but received %d", csr.NNZ())
+	}
+
+	// conjugate transpose
+	h := csr.H()
+	if r, c := h.Dims(); r != 3 || c != 3 {
+		t.Errorf("Expected 3x3 conjugate transpose but received %dx%d", r, c)
+	}
+	if v := h.At(1, 2); v != 3i {
+		t.Errorf("Expected conjugate transpose element (1, 2) of 3i but received %v", v)
+	}
+	if v := h.At(0, 0); v != 1-1i {
+		t.Errorf("Expected conjugate transpose element (0, 0) of 1-1i but received %v", v)
+	}
+	if v := csr.T().At(1, 2); v != -3i {
+		t.Errorf("Expected transpose element (1, 2) of -3i but received %v", v)
+	}
+
+	// set existing and new elements
+	csr.Set(1, 1, 5+5i)
+	csr.Set(0, 2, 7)
+	csr.Set(2, 0, 0)
+	expected.Set(1, 1, 5+5i)
+	expected.Set(0, 2, 7)
+	if !mat.CEqual(expected, csr) {
+		t.Errorf("Expected after Set:\n%v\n but received:\n%v", expected, csr.ToCDense())
+	}
+	if csr.NNZ() != 5 {
+		t.Errorf("Expected 5 stored elements after Set but received %d", csr.NNZ())
+	}
+
+	empty := NewCSRComplex(2, 2, nil, nil, nil)
+	empty.Set(1, 0, 1i)
+	if empty.At(1, 0) != 1i || empty.NNZ() != 1 {
+		t.Errorf("Expected element set in empty matrix but received %v", empty.At(1, 0))
+	}
+}
+
+func TestCSRComplexMulVecTo(t *testing.T) {
+	csr := NewCSRComplex(2, 3, []int{0, 2, 3}, []int{0, 2, 1}, []complex128{1 + 1i, 2, -3i})
+	x := []complex128{1, 1i, 2 - 1i}
+
+	var tests = []struct {
+		trans    bool
+		x        []complex128
+		dst      []complex128
+		expected []complex128
+	}{
+		{
+			// (1+i)*1 + 2*(2-i), -3i*i
+			trans:    false,
+			x:        x,
+			dst:      []complex128{0, 1},
+			expected: []complex128{5 - 1i, 4},
+		},
+		{
+			// (1+i)*1, -3i*1i, 2*1
+			trans:    true,
+			x:        []complex128{1, 1i},
+			dst:      make([]complex128, 3),
+			expected: []complex128{1 + 1i, 3, 2},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		csr.MulVecTo(test.dst, test.trans, test.x)
+		for i := range test.expected {
+			if test.dst[i] != test.expected[i] {
+				t.Logf("Expected %v but received %v\n", test.expected, test.dst)
+				t.Fail()
+				break
+			}
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != mat.ErrShape {
+			t.Errorf("Expected panic with mat.ErrShape but received %v", r)
+		}
+	}()
+	csr.MulVecTo(make([]complex128, 3), false, x)
+}
@@ -1,6 +1,10 @@
 package sparse
 
 import (
+	"fmt"
+	"math"
+	"sort"
+
 	"github.com/james-bowman/sparse/blas"
 	"gonum.org/v1/gonum/mat"
 )
@@ -18,6 +22,7 @@ var (
 	_ mat.Mutable        = (*CSC)(nil)
 	_ mat.ColViewer      = (*CSC)(nil)
 	_ mat.ColNonZeroDoer = (*CSC)(nil)
+	_ mat.Reseter        = (*CSC)(nil)
 )
 
 // CSR is a Compressed Sparse Row format sparse matrix implementation (sometimes called Compressed Row
@@ -42,6 +47,9 @@ var (
 // mat package e.g. mat.Dense.
 type CSR struct {
 	matrix blas.SparseMatrix
+
+	// sorted indicates the column indices of each row are known to be sorted
+	sorted bool
 }
 
 // NewCSR creates a new Compressed Sparse Row format sparse matrix.
@@ -51,10 +59,10 @@ type CSR struct {
 // backing storage to the matrix so changes to values of the slices will be reflected in the created matrix
 // and vice versa.
 func NewCSR(r int, c int, ia []int, ja []int, data []float64) *CSR {
-	if uint(r) < 0 {
+	if r < 0 {
 		panic(mat.ErrRowAccess)
 	}
-	if uint(c) < 0 {
+	if c < 0 {
 		panic(mat.ErrColAccess)
 	}
 
@@ -68,20 +76,218 @@ func NewCSR(r int, c int, ia []int, ja []int, data []float64) *CSR {
 	}
 }
 
+// NewCSRChecked creates a new Compressed Sparse Row format sparse matrix in the same way as
+// NewCSR but first verifies the supplied slices are consistent with each other and
+// with the dimensions of the matrix, returning an error naming the first invariant
+// found to be violated.  The following invariants are checked:
+//   - r and c are not negative
+//   - len(ia) == r+1
+//   - len(ja) == len(data)
+//   - ia[0] == 0 and ia[r] == len(data)
+//   - ia is monotonically non-decreasing
+//   - all column indices in ja are within the range [0, c)
+//
+// Column indices are not required to be sorted within each row.  As with NewCSR, the
+// supplied slices will be used as the backing storage to the matrix.
+func NewCSRChecked(r int, c int, ia []int, ja []int, data []float64) (*CSR, error) {
+	if err := checkCompressed(r, c, ia, ja, data, "row", "column"); err != nil {
+		return nil, err
+	}
+	return NewCSR(r, c, ia, ja, data), nil
+}
+
+// NewCSRFromDense creates a new CSR matrix from the (typically dense) matrix d storing only
+// the elements whose absolute value exceeds tol.  Elements with an absolute value less than
+// or equal to tol, such as tiny floating point residuals left by dense computations, are
+// treated as zero and not stored keeping the sparse form compact.  With a tol of 0 (or
+// less) every non-zero element is stored, as with Clone.  NaN values are always stored.
+// The column indices of each row of the returned matrix are sorted and the returned matrix
+// does not share storage with d.
+func NewCSRFromDense(d mat.Matrix, tol float64) *CSR {
+	r, c := d.Dims()
+	keep := func(v float64) bool {
+		return v != 0 && !(math.Abs(v) <= tol)
+	}
+
+	indptr := make([]int, r+1)
+	var ind []int
+	var data []float64
+	if rm, ok := d.(mat.RawMatrixer); ok {
+		raw := rm.RawMatrix()
+		for i := 0; i < r; i++ {
+			for j, v := range raw.Data[i*raw.Stride : i*raw.Stride+c] {
+				if keep(v) {
+					ind = append(ind, j)
+					data = append(data, v)
+				}
+			}
+			indptr[i+1] = len(ind)
+		}
+	} else {
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				if v := d.At(i, j); keep(v) {
+					ind = append(ind, j)
+					data = append(data, v)
+				}
+			}
+			indptr[i+1] = len(ind)
+		}
+	}
+
+	m := NewCSR(r, c, indptr, ind, data)
+	m.sorted = true
+	return m
+}
+
+// Identity returns a new n x n CSR identity matrix i.e. with ones along the main diagonal
+// and zeros elsewhere.  Exactly n elements are stored, with the backing storage allocated
+// to exactly the required size.  Identity matrices are useful, for example, to regularise
+// or shift a matrix (A + lambda*I).  Identity will panic if n is negative.
+func Identity(n int) *CSR {
+	if n < 0 {
+		panic(mat.ErrShape)
+	}
+	indptr := make([]int, n+1)
+	ind := make([]int, n)
+	data := make([]float64, n)
+	for i := 0; i < n; i++ {
+		indptr[i+1] = i + 1
+		ind[i] = i
+		data[i] = 1
+	}
+	return NewCSR(n, n, indptr, ind, data)
+}
+
+// Eye returns a new n x n CSR identity matrix.  Eye is an alias for Identity.
+func Eye(n int) *CSR {
+	return Identity(n)
+}
+
+// Diag returns a new square CSR matrix with the specified values along the main diagonal
+// and zeros elsewhere i.e. element (i, i) of the returned matrix is values[i].  Zero values
+// are not stored and the backing storage is allocated to exactly the required size.  The
+// returned matrix does not share storage with values.  For a diagonal matrix that is not
+// converted to CSR, see NewDIA.
+func Diag(values []float64) *CSR {
+	n := len(values)
+	nnz := 0
+	for _, v := range values {
+		if v != 0 {
+			nnz++
+		}
+	}
+	indptr := make([]int, n+1)
+	ind := make([]int, 0, nnz)
+	data := make([]float64, 0, nnz)
+	for i, v := range values {
+		if v != 0 {
+			ind = append(ind, i)
+			data = append(data, v)
+		}
+		indptr[i+1] = len(ind)
+	}
+	return NewCSR(n, n, indptr, ind, data)
+}
+
+// Diags returns a new r x c CSR matrix with the specified diagonals populated and zeros
+// elsewhere.  data[k] holds the values of the diagonal at offsets[k] where an offset of 0
+// refers to the main diagonal, positive offsets to diagonals above the main diagonal and
+// negative offsets to diagonals below it.  Values along each diagonal are ordered from top
+// left to bottom right so element (i, i+offsets[k]) is data[k][i] for non-negative offsets
+// and element (j-offsets[k], j) is data[k][j] for negative offsets.  Zero values are not
+// stored and the returned matrix, with sorted column indices, does not share storage with
+// data.  Diags will panic with mat.ErrShape if len(data) != len(offsets), an offset lies
+// outside the matrix or is repeated, or a diagonal slice does not have exactly the length of
+// the diagonal at its offset.
+func Diags(data [][]float64, offsets []int, r, c int) *CSR {
+	if r < 0 || c < 0 || len(data) != len(offsets) {
+		panic(mat.ErrShape)
+	}
+
+	order := make([]int, len(offsets))
+	for k, off := range offsets {
+		if len(data[k]) != diagLen(r, c, off) || len(data[k]) == 0 {
+			panic(mat.ErrShape)
+		}
+		order[k] = k
+	}
+	sort.Slice(order, func(a, b int) bool { return offsets[order[a]] < offsets[order[b]] })
+	for k := 1; k < len(order); k++ {
+		if offsets[order[k]] == offsets[order[k-1]] {
+			panic(mat.ErrShape)
+		}
+	}
+
+	nnz := 0
+	for _, d := range data {
+		for _, v := range d {
+			if v != 0 {
+				nnz++
+			}
+		}
+	}
+
+	indptr := make([]int, r+1)
+	ind := make([]int, 0, nnz)
+	vals := make([]float64, 0, nnz)
+	for i := 0; i < r; i++ {
+		for _, k := range order {
+			j := i + offsets[k]
+			if j < 0 || j >= c {
+				continue
+			}
+			p := i
+			if offsets[k] < 0 {
+				p = j
+			}
+			if v := data[k][p]; v != 0 {
+				ind = append(ind, j)
+				vals = append(vals, v)
+			}
+		}
+		indptr[i+1] = len(ind)
+	}
+	m := NewCSR(r, c, indptr, ind, vals)
+	m.sorted = true
+	return m
+}
+
+// diagLen returns the number of elements along the diagonal at offset off of an r x c
+// matrix, returning 0 if the diagonal lies outside the matrix.
+func diagLen(r, c, off int) int {
+	var n int
+	if off >= 0 {
+		n = min(r, c-off)
+	} else {
+		n = min(r+off, c)
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
 // Dims returns the size of the matrix as the number of rows and columns
 func (c *CSR) Dims() (int, int) {
 	return c.matrix.I, c.matrix.J
 }
 
 // At returns the element of the matrix located at row i and column j.  At will panic if specified values
-// for i or j fall outside the dimensions of the matrix.
+// for i or j fall outside the dimensions of the matrix.  If the column indices of the
+// matrix are known to be sorted (see SortIndices) the element is located using a binary
+// search of the row, otherwise the row is scanned linearly.
 func (c *CSR) At(m, n int) float64 {
+	if c.sorted {
+		return c.matrix.AtSorted(m, n)
+	}
 	return c.matrix.At(m, n)
 }
 
 // Set sets the element of the matrix located at row i and column j to value v.  Set will panic if
 // specified values for i or j fall outside the dimensions of the matrix.
 func (c *CSR) Set(m, n int, v float64) {
+	c.sorted = c.sorted && setKeepsSorted(&c.matrix, m, n, v)
 	c.matrix.Set(m, n, v)
 }
 
@@ -96,8 +302,34 @@ func (c *CSR) NNZ() int {
 	return len(c.matrix.Data)
 }
 
-// Trace returns the trace.
+// Density returns the proportion of the elements of the matrix that are stored i.e.
+// NNZ / (rows * cols) as a value between 0 and 1.  Explicitly stored zero values are
+// counted as stored elements.  As a matrix with no rows or columns has no elements,
+// Density returns 0 for such matrices rather than dividing by zero.  Density is useful,
+// for example, when deciding whether a matrix would be better stored in dense format.
+func (c *CSR) Density() float64 {
+	if c.matrix.I == 0 || c.matrix.J == 0 {
+		return 0
+	}
+	// duplicate elements could otherwise exceed the number of elements
+	return math.Min(1, float64(c.NNZ())/(float64(c.matrix.I)*float64(c.matrix.J)))
+}
+
+// Sparsity returns the proportion of the elements of the matrix that are not stored i.e.
+// 1 - Density as a value between 0 and 1.  A matrix with no rows or columns has a
+// Sparsity of 1.
+func (c *CSR) Sparsity() float64 {
+	return 1 - c.Density()
+}
+
+// Trace returns the trace (the sum of the diagonal elements) of the matrix.  The
+// trace is computed in a single structural pass scanning the index list of each
+// row for the diagonal element.  Trace will panic with mat.ErrShape if the
+// matrix is not square.
 func (c *CSR) Trace() float64 {
+	if c.matrix.I != c.matrix.J {
+		panic(mat.ErrShape)
+	}
 	var trace float64
 	nrows := len(c.matrix.Indptr) - 1
 	for i := 0; i < nrows; i++ {
@@ -111,6 +343,23 @@ func (c *CSR) Trace() float64 {
 	return trace
 }
 
+// Diagonal returns a new slice of length min(rows, cols) containing the elements of the
+// main diagonal of the matrix i.e. entry k is the element at row k, column k.  The
+// diagonal is extracted by scanning the index list of each row for the diagonal element.
+func (c *CSR) Diagonal() []float64 {
+	return compressedDiagonal(&c.matrix)
+}
+
+// SetDiagonal sets the elements of the main diagonal of the matrix to the values in
+// diag such that the element at row k, column k is set to diag[k].  Existing diagonal
+// elements are updated in place and new elements are inserted for any non-zero values
+// where the diagonal element was not previously stored.  In keeping with Set, zero
+// values for previously stored diagonal elements are stored as explicit zeros.
+// SetDiagonal will panic with mat.ErrShape if len(diag) != min(rows, cols).
+func (c *CSR) SetDiagonal(diag []float64) {
+	compressedSetDiagonal(&c.matrix, diag)
+}
+
 // RawMatrix returns a pointer to the underlying blas sparse matrix.
 func (c *CSR) RawMatrix() *blas.SparseMatrix {
 	return &c.matrix
@@ -143,6 +392,10 @@ func (c *CSR) Clone(b mat.Matrix) {
 		return
 	}
 
+	if c.checkOverlap(b) {
+		// don't reuse storage shared with b
+		c.matrix.Indptr, c.matrix.Ind, c.matrix.Data = nil, nil, nil
+	}
 	c.Reset()
 	c.reuseAs(row, col, row*col/10, true)
 	k := 0
@@ -161,6 +414,10 @@ func (c *CSR) Clone(b mat.Matrix) {
 
 // cloneCSR copies the specified CSR matrix into the receiver
 func (c *CSR) cloneCSR(b *CSR) {
+	if c != b && c.checkOverlap(b) {
+		// don't reuse storage shared with b
+		c.matrix.Indptr, c.matrix.Ind, c.matrix.Data = nil, nil, nil
+	}
 	c.Reset()
 	c.reuseAs(b.matrix.I, b.matrix.J, b.NNZ(), false)
 	copy(c.matrix.Indptr, b.matrix.Indptr)
@@ -180,6 +437,28 @@ func (c *CSR) ToDense() *mat.Dense {
 	return mat
 }
 
+// ToDenseInto returns a mat.Dense dense format version of the matrix using dst as the
+// storage for the result.  Dst is zeroed before the non-zero elements of the receiver are
+// scattered into it.  If dst is nil or is not the same size as the receiver, a new
+// mat.Dense matrix will be allocated instead.  This avoids repeated allocation when
+// converting many matrices of the same size to dense format, for example in a loop.  The
+// returned matrix will not share underlying storage with the receiver nor is the receiver
+// modified by this call.
+func (c *CSR) ToDenseInto(dst *mat.Dense) *mat.Dense {
+	if dst == nil || !sameDims(dst, c.matrix.I, c.matrix.J) {
+		dst = mat.NewDense(c.matrix.I, c.matrix.J, nil)
+	} else {
+		dst.Zero()
+	}
+	raw := dst.RawMatrix()
+	for i := 0; i < len(c.matrix.Indptr)-1; i++ {
+		for j := c.matrix.Indptr[i]; j < c.matrix.Indptr[i+1]; j++ {
+			raw.Data[i*raw.Stride+c.matrix.Ind[j]] = c.matrix.Data[j]
+		}
+	}
+	return dst
+}
+
 // ToDOK returns a DOK (Dictionary Of Keys) sparse format version of the matrix.  The returned DOK
 // matrix will not share underlying storage with the receiver nor is the receiver modified by this call.
 func (c *CSR) ToDOK() *DOK {
@@ -195,19 +474,24 @@ func (c *CSR) ToDOK() *DOK {
 // ToCOO returns a COOrdinate sparse format version of the matrix.  The returned COO matrix will
 // not share underlying storage with the receiver nor is the receiver modified by this call.
 func (c *CSR) ToCOO() *COO {
-	rows := make([]int, c.NNZ())
-	cols := make([]int, c.NNZ())
-	data := make([]float64, c.NNZ())
+	return c.ToCOOWith(ConversionOptions{})
+}
+
+// ToCOOWith returns a COOrdinate sparse format version of the matrix as per ToCOO but
+// allocating the storage for the returned matrix according to opts.  If opts.Pool is
+// not nil, the storage is drawn from the pool and backs the returned matrix until it is
+// released back to the pool with ConversionPool.ReleaseCOO.
+func (c *CSR) ToCOOWith(opts ConversionOptions) *COO {
+	coo := opts.newCOO(c.matrix.I, c.matrix.J, c.NNZ())
 
 	for i := 0; i < len(c.matrix.Indptr)-1; i++ {
 		for j := c.matrix.Indptr[i]; j < c.matrix.Indptr[i+1]; j++ {
-			rows[j] = i
+			coo.rows[j] = i
 		}
 	}
-	copy(cols, c.matrix.Ind)
-	copy(data, c.matrix.Data)
+	copy(coo.cols, c.matrix.Ind)
+	copy(coo.data, c.matrix.Data)
 
-	coo := NewCOO(c.matrix.I, c.matrix.J, rows, cols, data)
 	return coo
 }
 
@@ -231,12 +515,28 @@ func (c *CSR) ToType(matType MatrixType) mat.Matrix {
 
 // RowNNZ returns the Number of Non Zero values in the specified row i.  RowNNZ will panic if i is out of range.
 func (c *CSR) RowNNZ(i int) int {
-	if uint(i) < 0 || uint(i) >= uint(c.matrix.I) {
+	if i < 0 || i >= c.matrix.I {
 		panic(mat.ErrRowAccess)
 	}
 	return c.matrix.Indptr[i+1] - c.matrix.Indptr[i]
 }
 
+// ColNNZs returns a slice of length cols containing the number of stored elements in each
+// column of the receiver i.e. element j is the number of stored elements in column j.  As
+// CSR is row major, the counts are computed in a single O(NNZ) pass over the column
+// indices of the receiver.  See ColNNZsTo to avoid allocating.
+func (c *CSR) ColNNZs() []int {
+	return c.ColNNZsTo(nil)
+}
+
+// ColNNZsTo counts the number of stored elements in each column of the receiver as per
+// ColNNZs, storing the result in dst and returning it.  If dst is nil, a new slice of the
+// correct length will be allocated.  ColNNZsTo will panic if dst is not nil and its length
+// is not equal to the number of columns of the receiver.
+func (c *CSR) ColNNZsTo(dst []int) []int {
+	return compressedMinorNNZs(&c.matrix, dst)
+}
+
 // RowView slices the Compressed Sparse Row matrix along its primary axis.
 // Returns a VecCOO sparse Vector that shares the same storage with
 // the receiver for row i.
@@ -251,6 +551,41 @@ func (c *CSR) RowView(i int) mat.Vector {
 	)
 }
 
+// RawRow returns the column indices and values of the stored elements of row i.  The
+// returned slices alias the underlying storage of the receiver so changes to values in
+// the returned data slice will be reflected in the receiver.  The capacity of the
+// returned slices is limited to the length of the row so appending to them will not
+// overwrite the elements of subsequent rows.  The column indices are not guaranteed to
+// be sorted.  RawRow will panic if i is out of range.
+func (c *CSR) RawRow(i int) (ind []int, data []float64) {
+	if i >= c.matrix.I || i < 0 {
+		panic(mat.ErrRowAccess)
+	}
+	begin, end := c.matrix.Indptr[i], c.matrix.Indptr[i+1]
+	return c.matrix.Ind[begin:end:end], c.matrix.Data[begin:end:end]
+}
+
+// SortIndices sorts the column indices of each row of the receiver into ascending order,
+// in place, keeping each stored value paired with its column index.  The sort is stable
+// so the relative order of any duplicate elements within a row is preserved.
+//
+// Once sorted, the receiver records that its indices are sorted allowing At to use a
+// binary search to locate elements.  Operations on the receiver maintain this record
+// but if the indices are subsequently modified directly (e.g. via RawMatrix or the
+// slices passed to NewCSR), SortIndices must be called again.
+func (c *CSR) SortIndices() {
+	sortCompressedIndices(&c.matrix)
+	c.sorted = true
+}
+
+// HasSortedIndices returns true if the column indices of each row of the receiver are
+// sorted in ascending order.  If they are, the receiver records that its indices are
+// sorted in the same way as SortIndices.
+func (c *CSR) HasSortedIndices() bool {
+	c.sorted = c.sorted || hasSortedIndices(&c.matrix)
+	return c.sorted
+}
+
 // ScatterRow returns a slice representing row i of the matrix in dense format.  Row
 // is used as the storage for the operation unless it is nil in which case, new
 // storage of the correct length will be allocated.  This method will panic if i
@@ -276,10 +611,16 @@ func (c *CSR) ScatterRow(i int, row []float64) []float64 {
 }
 
 // Reset zeros the dimensions of the matrix so that it can be reused as the
-// receiver of a dimensionally restricted operation.
+// receiver of a dimensionally restricted operation.  Reset discards the sparsity
+// structure of the matrix, truncating the underlying Indptr, Ind and Data slices to
+// zero length, but retains their capacity so that subsequent operations using the
+// receiver may reuse the allocated storage rather than reallocating, mirroring
+// mat.Dense.Reset.  To retain the structure of the matrix and zero only the stored
+// values, use Zero instead.
 //
 // See the Gonum mat.Reseter interface for more information.
 func (c *CSR) Reset() {
+	c.sorted = false
 	c.matrix.I, c.matrix.J = 0, 0
 	c.matrix.Indptr = c.matrix.Indptr[:0]
 	c.matrix.Ind = c.matrix.Ind[:0]
@@ -293,17 +634,49 @@ func (c *CSR) IsZero() bool {
 	return c.matrix.I == 0 && c.matrix.J == 0
 }
 
+// Zero sets all the stored values of the receiver to zero while retaining its
+// dimensions and sparsity structure i.e. the stored elements remain stored, as
+// explicit zeros, so that values may subsequently be accumulated into them without
+// altering the structure.  Unlike Reset, which discards the structure, Zero does not
+// modify the Indptr or Ind slices of the receiver.
+func (c *CSR) Zero() {
+	for i := range c.matrix.Data {
+		c.matrix.Data[i] = 0
+	}
+}
+
+// Grow ensures the receiver has sufficient capacity in its underlying storage to
+// hold at least nnz stored elements without reallocating, retaining any existing
+// stored elements.  This allows storage to be preallocated ahead of an operation
+// such as Mul or Add when the approximate number of non-zero elements in the result
+// is known in advance.  Operations writing to the receiver reuse its existing
+// storage wherever the capacity is at least their own initial estimate of the
+// number of non-zero elements in the result (e.g. NNZ(a) + NNZ(b) for Mul) and
+// otherwise extend it as needed.  Grow may be called on a zero-sized receiver, in
+// which case the row pointers (Indptr) are sized by the subsequent operation.  If
+// new storage is allocated, the receiver will no longer share storage with any
+// slices previously returned from it e.g. by RawRow.
+func (c *CSR) Grow(nnz int) {
+	if nnz <= cap(c.matrix.Ind) && nnz <= cap(c.matrix.Data) {
+		return
+	}
+	ind := make([]int, len(c.matrix.Ind), nnz)
+	copy(ind, c.matrix.Ind)
+	data := make([]float64, len(c.matrix.Data), nnz)
+	copy(data, c.matrix.Data)
+	c.matrix.Ind, c.matrix.Data = ind, data
+}
+
 // reuseAs resizes a zero-sized matrix to be rxc or checks a non-zero-sized matrix
 // is already the correct size (rxc).  If the matrix is resized, the method will
 // ensure there is sufficient initial capacity allocated in the underlying storage
 // to store up to nnz non-zero elements although this will be extended
 // automatically later as needed (using Go's built-in append function).
 func (c *CSR) reuseAs(row, col, nnz int, zero bool) {
+	c.sorted = false
 	if c.IsZero() {
-		c.matrix = blas.SparseMatrix{
-			I: row,
-			J: col,
-		}
+		// retain any existing storage (e.g. following Reset or Grow) for reuse
+		c.matrix.I, c.matrix.J = row, col
 	} else if row != c.matrix.I || col != c.matrix.J {
 		panic(mat.ErrShape)
 	}
@@ -366,6 +739,9 @@ func (c *CSR) checkOverlap(a mat.Matrix) bool {
 // e.g. mat.Dense.
 type CSC struct {
 	matrix blas.SparseMatrix
+
+	// sorted indicates the row indices of each column are known to be sorted
+	sorted bool
 }
 
 // NewCSC creates a new Compressed Sparse Column format sparse matrix.
@@ -375,10 +751,10 @@ type CSC struct {
 // backing storage to the matrix so changes to values of the slices will be reflected in the created matrix
 // and vice versa.
 func NewCSC(r int, c int, indptr []int, ind []int, data []float64) *CSC {
-	if uint(r) < 0 {
+	if r < 0 {
 		panic(mat.ErrRowAccess)
 	}
-	if uint(c) < 0 {
+	if c < 0 {
 		panic(mat.ErrColAccess)
 	}
 
@@ -392,20 +768,39 @@ func NewCSC(r int, c int, indptr []int, ind []int, data []float64) *CSC {
 	}
 }
 
+// NewCSCChecked creates a new Compressed Sparse Column format sparse matrix in the same way
+// as NewCSC but first verifies the supplied slices are consistent with each other and
+// with the dimensions of the matrix, returning an error naming the first invariant
+// found to be violated.  The invariants checked are those of NewCSRChecked with the
+// roles of rows and columns exchanged i.e. len(indptr) == c+1 and all row indices in
+// ind are within the range [0, r).
+func NewCSCChecked(r int, c int, indptr []int, ind []int, data []float64) (*CSC, error) {
+	if err := checkCompressed(c, r, indptr, ind, data, "column", "row"); err != nil {
+		return nil, err
+	}
+	return NewCSC(r, c, indptr, ind, data), nil
+}
+
 // Dims returns the size of the matrix as the number of rows and columns
 func (c *CSC) Dims() (int, int) {
 	return c.matrix.J, c.matrix.I
 }
 
 // At returns the element of the matrix located at row i and column j.  At will panic if specified values
-// for i or j fall outside the dimensions of the matrix.
+// for i or j fall outside the dimensions of the matrix.  If the row indices of the
+// matrix are known to be sorted (see SortIndices) the element is located using a binary
+// search of the column, otherwise the column is scanned linearly.
 func (c *CSC) At(m, n int) float64 {
+	if c.sorted {
+		return c.matrix.AtSorted(n, m)
+	}
 	return c.matrix.At(n, m)
 }
 
 // Set sets the element of the matrix located at row i and column j to value v.  Set will panic if
 // specified values for i or j fall outside the dimensions of the matrix.
 func (c *CSC) Set(m, n int, v float64) {
+	c.sorted = c.sorted && setKeepsSorted(&c.matrix, n, m, v)
 	c.matrix.Set(n, m, v)
 }
 
@@ -438,8 +833,14 @@ func (c *CSC) NNZ() int {
 	return len(c.matrix.Data)
 }
 
-// Trace returns the trace.
+// Trace returns the trace (the sum of the diagonal elements) of the matrix.  The
+// trace is computed in a single structural pass scanning the index list of each
+// column for the diagonal element.  Trace will panic with mat.ErrShape if the
+// matrix is not square.
 func (c *CSC) Trace() float64 {
+	if c.matrix.I != c.matrix.J {
+		panic(mat.ErrShape)
+	}
 	var trace float64
 	ncols := len(c.matrix.Indptr) - 1
 	for i := 0; i < ncols; i++ {
@@ -453,6 +854,24 @@ func (c *CSC) Trace() float64 {
 	return trace
 }
 
+// Diagonal returns a new slice of length min(rows, cols) containing the elements of the
+// main diagonal of the matrix i.e. entry k is the element at row k, column k.  The
+// diagonal is extracted by scanning the index list of each column for the diagonal
+// element.
+func (c *CSC) Diagonal() []float64 {
+	return compressedDiagonal(&c.matrix)
+}
+
+// SetDiagonal sets the elements of the main diagonal of the matrix to the values in
+// diag such that the element at row k, column k is set to diag[k].  Existing diagonal
+// elements are updated in place and new elements are inserted for any non-zero values
+// where the diagonal element was not previously stored.  In keeping with Set, zero
+// values for previously stored diagonal elements are stored as explicit zeros.
+// SetDiagonal will panic with mat.ErrShape if len(diag) != min(rows, cols).
+func (c *CSC) SetDiagonal(diag []float64) {
+	compressedSetDiagonal(&c.matrix, diag)
+}
+
 // RawMatrix returns a pointer to the underlying blas sparse matrix.
 func (c *CSC) RawMatrix() *blas.SparseMatrix {
 	return &c.matrix
@@ -470,6 +889,28 @@ func (c *CSC) ToDense() *mat.Dense {
 	return mat
 }
 
+// ToDenseInto returns a mat.Dense dense format version of the matrix using dst as the
+// storage for the result.  Dst is zeroed before the non-zero elements of the receiver are
+// scattered into it.  If dst is nil or is not the same size as the receiver, a new
+// mat.Dense matrix will be allocated instead.  This avoids repeated allocation when
+// converting many matrices of the same size to dense format, for example in a loop.  The
+// returned matrix will not share underlying storage with the receiver nor is the receiver
+// modified by this call.
+func (c *CSC) ToDenseInto(dst *mat.Dense) *mat.Dense {
+	if dst == nil || !sameDims(dst, c.matrix.J, c.matrix.I) {
+		dst = mat.NewDense(c.matrix.J, c.matrix.I, nil)
+	} else {
+		dst.Zero()
+	}
+	raw := dst.RawMatrix()
+	for i := 0; i < len(c.matrix.Indptr)-1; i++ {
+		for j := c.matrix.Indptr[i]; j < c.matrix.Indptr[i+1]; j++ {
+			raw.Data[c.matrix.Ind[j]*raw.Stride+i] = c.matrix.Data[j]
+		}
+	}
+	return dst
+}
+
 // ToDOK returns a DOK (Dictionary Of Keys) sparse format version of the matrix.  The returned DOK
 // matrix will not share underlying storage with the receiver nor is the receiver modified by this call.
 func (c *CSC) ToDOK() *DOK {
@@ -485,19 +926,24 @@ func (c *CSC) ToDOK() *DOK {
 // ToCOO returns a COOrdinate sparse format version of the matrix.  The returned COO matrix will
 // not share underlying storage with the receiver nor is the receiver modified by this call.
 func (c *CSC) ToCOO() *COO {
-	rows := make([]int, c.NNZ())
-	cols := make([]int, c.NNZ())
-	data := make([]float64, c.NNZ())
+	return c.ToCOOWith(ConversionOptions{})
+}
+
+// ToCOOWith returns a COOrdinate sparse format version of the matrix as per ToCOO but
+// allocating the storage for the returned matrix according to opts.  If opts.Pool is
+// not nil, the storage is drawn from the pool and backs the returned matrix until it is
+// released back to the pool with ConversionPool.ReleaseCOO.
+func (c *CSC) ToCOOWith(opts ConversionOptions) *COO {
+	coo := opts.newCOO(c.matrix.J, c.matrix.I, c.NNZ())
 
 	for i := 0; i < len(c.matrix.Indptr)-1; i++ {
 		for j := c.matrix.Indptr[i]; j < c.matrix.Indptr[i+1]; j++ {
-			cols[j] = i
+			coo.cols[j] = i
 		}
 	}
-	copy(rows, c.matrix.Ind)
-	copy(data, c.matrix.Data)
+	copy(coo.rows, c.matrix.Ind)
+	copy(coo.data, c.matrix.Data)
 
-	coo := NewCOO(c.matrix.J, c.matrix.I, rows, cols, data)
 	return coo
 }
 
@@ -521,12 +967,28 @@ func (c *CSC) ToType(matType MatrixType) mat.Matrix {
 
 // ColNNZ returns the Number of Non Zero values in the specified col i.  ColNNZ will panic if i is out of range.
 func (c *CSC) ColNNZ(i int) int {
-	if uint(i) < 0 || uint(i) >= uint(c.matrix.I) {
+	if i < 0 || i >= c.matrix.I {
 		panic(mat.ErrColAccess)
 	}
 	return c.matrix.Indptr[i+1] - c.matrix.Indptr[i]
 }
 
+// RowNNZs returns a slice of length rows containing the number of stored elements in each
+// row of the receiver i.e. element i is the number of stored elements in row i.  As CSC is
+// column major, the counts are computed in a single O(NNZ) pass over the row indices of
+// the receiver.  See RowNNZsTo to avoid allocating.
+func (c *CSC) RowNNZs() []int {
+	return c.RowNNZsTo(nil)
+}
+
+// RowNNZsTo counts the number of stored elements in each row of the receiver as per
+// RowNNZs, storing the result in dst and returning it.  If dst is nil, a new slice of the
+// correct length will be allocated.  RowNNZsTo will panic if dst is not nil and its length
+// is not equal to the number of rows of the receiver.
+func (c *CSC) RowNNZsTo(dst []int) []int {
+	return compressedMinorNNZs(&c.matrix, dst)
+}
+
 // ColView slices the Compressed Sparse Column matrix along its primary axis.
 // Returns a VecCOO sparse Vector that shares the same underlying storage as
 // column i of the receiver.
@@ -541,6 +1003,74 @@ func (c *CSC) ColView(j int) mat.Vector {
 	)
 }
 
+// RawCol returns the row indices and values of the stored elements of column j.  The
+// returned slices alias the underlying storage of the receiver so changes to values in
+// the returned data slice will be reflected in the receiver.  The capacity of the
+// returned slices is limited to the length of the column so appending to them will not
+// overwrite the elements of subsequent columns.  The row indices are not guaranteed to
+// be sorted.  RawCol will panic if j is out of range.
+func (c *CSC) RawCol(j int) (ind []int, data []float64) {
+	if j >= c.matrix.I || j < 0 {
+		panic(mat.ErrColAccess)
+	}
+	begin, end := c.matrix.Indptr[j], c.matrix.Indptr[j+1]
+	return c.matrix.Ind[begin:end:end], c.matrix.Data[begin:end:end]
+}
+
+// SortIndices sorts the row indices of each column of the receiver into ascending order,
+// in place, keeping each stored value paired with its row index.  The sort is stable
+// so the relative order of any duplicate elements within a column is preserved.
+//
+// Once sorted, the receiver records that its indices are sorted allowing At to use a
+// binary search to locate elements.  Operations on the receiver maintain this record
+// but if the indices are subsequently modified directly (e.g. via RawMatrix or the
+// slices passed to NewCSC), SortIndices must be called again.
+func (c *CSC) SortIndices() {
+	sortCompressedIndices(&c.matrix)
+	c.sorted = true
+}
+
+// HasSortedIndices returns true if the row indices of each column of the receiver are
+// sorted in ascending order.  If they are, the receiver records that its indices are
+// sorted in the same way as SortIndices.
+func (c *CSC) HasSortedIndices() bool {
+	c.sorted = c.sorted || hasSortedIndices(&c.matrix)
+	return c.sorted
+}
+
+// Reset zeros the dimensions of the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.  Reset discards the sparsity
+// structure of the matrix, truncating the underlying Indptr, Ind and Data slices to
+// zero length, but retains their capacity so that the allocated storage may be
+// reused.  To retain the structure of the matrix and zero only the stored values,
+// use Zero instead.
+//
+// See the Gonum mat.Reseter interface for more information.
+func (c *CSC) Reset() {
+	c.sorted = false
+	c.matrix.I, c.matrix.J = 0, 0
+	c.matrix.Indptr = c.matrix.Indptr[:0]
+	c.matrix.Ind = c.matrix.Ind[:0]
+	c.matrix.Data = c.matrix.Data[:0]
+}
+
+// IsZero returns whether the receiver is zero-sized. CSC matrices can be zeroed
+// using the Reset method.
+func (c *CSC) IsZero() bool {
+	return c.matrix.I == 0 && c.matrix.J == 0
+}
+
+// Zero sets all the stored values of the receiver to zero while retaining its
+// dimensions and sparsity structure i.e. the stored elements remain stored, as
+// explicit zeros, so that values may subsequently be accumulated into them without
+// altering the structure.  Unlike Reset, which discards the structure, Zero does not
+// modify the Indptr or Ind slices of the receiver.
+func (c *CSC) Zero() {
+	for i := range c.matrix.Data {
+		c.matrix.Data[i] = 0
+	}
+}
+
 // ScatterCol returns a slice representing column j of the matrix in dense format.  Col
 // is used as the storage for the operation unless it is nil in which case, new
 // storage of the correct length will be allocated.  This method will panic if j
@@ -565,6 +1095,45 @@ func (c *CSC) ScatterCol(j int, col []float64) []float64 {
 	return col
 }
 
+// ScatterCols scatters the columns of the matrix specified in cols into the columns of
+// the dense matrix dst such that column k of dst contains column cols[k] of the receiver.
+// This is equivalent to calling ScatterCol for each of the specified columns but scatters
+// all of them into dst in a single call.  Dst is used as the storage for the operation
+// unless it is nil in which case a new matrix of the correct size will be allocated.  Any
+// existing values in dst are overwritten.  As Gonum dense matrices may not have zero
+// columns, if cols is empty there is nothing to scatter and dst is returned unchanged.
+// This method will panic if any of the specified columns are out of range or if dst is
+// not of size r x len(cols) where r is the number of rows in the receiver.
+func (c *CSC) ScatterCols(cols []int, dst *mat.Dense) *mat.Dense {
+	if len(cols) == 0 {
+		return dst
+	}
+	for _, j := range cols {
+		if j >= c.matrix.I || j < 0 {
+			panic(mat.ErrColAccess)
+		}
+	}
+	if dst == nil {
+		dst = mat.NewDense(c.matrix.J, len(cols), nil)
+	} else {
+		if r, cc := dst.Dims(); r != c.matrix.J || cc != len(cols) {
+			panic(mat.ErrShape)
+		}
+		dst.Zero()
+	}
+
+	raw := dst.RawMatrix()
+	for k, j := range cols {
+		blas.Dussc(
+			c.matrix.Data[c.matrix.Indptr[j]:c.matrix.Indptr[j+1]],
+			raw.Data[k:],
+			raw.Stride,
+			c.matrix.Ind[c.matrix.Indptr[j]:c.matrix.Indptr[j+1]],
+		)
+	}
+	return dst
+}
+
 // Cull removes all entries within epsilon of 0.
 func (c *CSR) Cull(epsilon float64) {
 	newM := c.matrix.Cull(epsilon)
@@ -576,3 +1145,287 @@ func (c *CSC) Cull(epsilon float64) {
 	newM := c.matrix.Cull(epsilon)
 	c.matrix = *newM
 }
+
+// Prune removes, in place, all stored entries whose absolute value is less than or
+// equal to tol, compacting the receiver's underlying storage.  A tol of 0 removes only
+// explicitly stored zero values.  Unlike Cull, Prune does not allocate new storage and
+// so views sharing storage with the receiver will observe the compaction.
+func (c *CSR) Prune(tol float64) {
+	c.matrix.Prune(tol)
+}
+
+// Prune removes, in place, all stored entries whose absolute value is less than or
+// equal to tol, compacting the receiver's underlying storage.  A tol of 0 removes only
+// explicitly stored zero values.  Unlike Cull, Prune does not allocate new storage and
+// so views sharing storage with the receiver will observe the compaction.
+func (c *CSC) Prune(tol float64) {
+	c.matrix.Prune(tol)
+}
+
+// Round rounds each of the stored non-zero values of the receiver, in place, to the
+// specified number of decimal places.  Negative values for decimals round to the
+// corresponding power of ten to the left of the decimal point e.g. a decimals value
+// of -1 rounds to the nearest ten.  Values already exact at the requested precision,
+// including all values where decimals exceeds the range of float64, are unchanged.
+// Values that round to zero remain stored in the sparsity pattern as explicit zeros
+// (see RoundPruned to remove them).
+func (c *CSR) Round(decimals int) {
+	if decimals >= 0 {
+		pow := math.Pow(10, float64(decimals))
+		if math.IsInf(pow, 1) {
+			// values are already exact at this precision
+			return
+		}
+		for i, v := range c.matrix.Data {
+			if scaled := v * pow; !math.IsInf(scaled, 0) {
+				c.matrix.Data[i] = math.Round(scaled) / pow
+			}
+		}
+		return
+	}
+	pow := math.Pow(10, float64(-decimals))
+	for i, v := range c.matrix.Data {
+		if math.IsInf(pow, 1) {
+			// all finite values are negligible at this precision
+			if !math.IsInf(v, 0) && !math.IsNaN(v) {
+				c.matrix.Data[i] = 0
+			}
+			continue
+		}
+		c.matrix.Data[i] = math.Round(v/pow) * pow
+	}
+}
+
+// RoundPruned behaves as Round but additionally removes, in place, any stored values that
+// round to zero, compacting the receiver's underlying storage as Prune.
+func (c *CSR) RoundPruned(decimals int) {
+	c.Round(decimals)
+	c.matrix.Prune(0)
+}
+
+// compressedDiagonal returns the main diagonal of the compressed sparse matrix m.  As the
+// main diagonal of a matrix is also the main diagonal of its transpose, m may be either
+// row or column major.
+func compressedDiagonal(m *blas.SparseMatrix) []float64 {
+	diag := make([]float64, min(m.I, m.J))
+	for k := range diag {
+		for p := m.Indptr[k]; p < m.Indptr[k+1]; p++ {
+			if m.Ind[p] == k {
+				diag[k] = m.Data[p]
+				break
+			}
+		}
+	}
+	return diag
+}
+
+// compressedSetDiagonal sets the main diagonal of the compressed sparse matrix m to the
+// values in diag.  Existing diagonal elements are updated in place and, if any non-zero
+// values are to be inserted, m is rebuilt in a single pass with each new diagonal
+// element inserted before the first element of its row (or column) with a greater
+// index so that sorted indices remain sorted.
+func compressedSetDiagonal(m *blas.SparseMatrix, diag []float64) {
+	if len(diag) != min(m.I, m.J) {
+		panic(mat.ErrShape)
+	}
+
+	var missing int
+	for k, v := range diag {
+		found := false
+		for p := m.Indptr[k]; p < m.Indptr[k+1]; p++ {
+			if m.Ind[p] == k {
+				m.Data[p] = v
+				found = true
+				break
+			}
+		}
+		if !found && v != 0 {
+			missing++
+		}
+	}
+	if missing == 0 {
+		return
+	}
+
+	nnz := len(m.Data) + missing
+	ind := make([]int, 0, nnz)
+	data := make([]float64, 0, nnz)
+	begin := 0
+	for k := 0; k < m.I; k++ {
+		end := m.Indptr[k+1]
+		insert := k < len(diag) && diag[k] != 0
+		for p := begin; p < end; p++ {
+			if m.Ind[p] == k {
+				insert = false
+			}
+		}
+		for p := begin; p < end; p++ {
+			if insert && m.Ind[p] > k {
+				ind = append(ind, k)
+				data = append(data, diag[k])
+				insert = false
+			}
+			ind = append(ind, m.Ind[p])
+			data = append(data, m.Data[p])
+		}
+		if insert {
+			ind = append(ind, k)
+			data = append(data, diag[k])
+		}
+		begin = end
+		m.Indptr[k+1] = len(ind)
+	}
+	m.Ind = ind
+	m.Data = data
+}
+
+// checkCompressed checks the index pointers, indices and data slices of a compressed
+// sparse matrix with the specified number of major (rows for CSR) and minor (columns
+// for CSR) dimensions are consistent, returning an error describing the first
+// inconsistency found.  majorName and minorName are used to describe the dimensions
+// in error messages.
+func checkCompressed(major, minor int, indptr, ind []int, data []float64, majorName, minorName string) error {
+	if major < 0 || minor < 0 {
+		return fmt.Errorf("sparse: negative dimensions (%d %ss, %d %ss)", major, majorName, minor, minorName)
+	}
+	if err := checkIndptrLen(major, indptr, majorName); err != nil {
+		return err
+	}
+	if err := checkDataLen(ind, data); err != nil {
+		return err
+	}
+	if err := checkIndptrStart(indptr); err != nil {
+		return err
+	}
+	if err := checkIndptrEnd(indptr, len(data)); err != nil {
+		return err
+	}
+	if err := checkIndptrOrder(indptr, majorName); err != nil {
+		return err
+	}
+	for i := 0; i < major; i++ {
+		for k := indptr[i]; k < indptr[i+1]; k++ {
+			if err := checkMinorIndex(i, ind[k], minor, majorName, minorName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkIndptrLen checks indptr holds one more index pointer than the major dimension.
+func checkIndptrLen(major int, indptr []int, majorName string) error {
+	if len(indptr) != major+1 {
+		return fmt.Errorf("sparse: length of indptr is %d, expected %d (%ss+1)", len(indptr), major+1, majorName)
+	}
+	return nil
+}
+
+// checkDataLen checks the indices and data slices are of the same length.
+func checkDataLen(ind []int, data []float64) error {
+	if len(ind) != len(data) {
+		return fmt.Errorf("sparse: length of indices (%d) does not match length of data (%d)", len(ind), len(data))
+	}
+	return nil
+}
+
+// checkIndptrStart checks the first index pointer of the non-empty indptr is zero.
+func checkIndptrStart(indptr []int) error {
+	if indptr[0] != 0 {
+		return fmt.Errorf("sparse: indptr[0] is %d, expected 0", indptr[0])
+	}
+	return nil
+}
+
+// checkIndptrEnd checks the last index pointer of the non-empty indptr is nnz.
+func checkIndptrEnd(indptr []int, nnz int) error {
+	if n := len(indptr) - 1; indptr[n] != nnz {
+		return fmt.Errorf("sparse: indptr[%d] is %d, expected length of data (%d)", n, indptr[n], nnz)
+	}
+	return nil
+}
+
+// checkIndptrOrder checks the index pointers indptr are non-decreasing.
+func checkIndptrOrder(indptr []int, majorName string) error {
+	for i := 1; i < len(indptr); i++ {
+		if indptr[i] < indptr[i-1] {
+			return fmt.Errorf("sparse: indptr is decreasing at %s %d (%d > %d)", majorName, i-1, indptr[i-1], indptr[i])
+		}
+	}
+	return nil
+}
+
+// checkMinorIndex checks the minor index j of an element stored in major index i lies
+// within the minor dimension.
+func checkMinorIndex(i, j, minor int, majorName, minorName string) error {
+	if j < 0 || j >= minor {
+		return fmt.Errorf("sparse: %s index %d out of range [0, %d) in %s %d", minorName, j, minor, majorName, i)
+	}
+	return nil
+}
+
+// compressedMinorNNZs counts the number of stored elements of m with each minor axis
+// index (column for CSR or row for CSC) into dst, allocating dst if it is nil.
+func compressedMinorNNZs(m *blas.SparseMatrix, dst []int) []int {
+	if dst == nil {
+		dst = make([]int, m.J)
+	} else if len(dst) != m.J {
+		panic(mat.ErrShape)
+	} else {
+		for i := range dst {
+			dst[i] = 0
+		}
+	}
+	for _, j := range m.Ind[:m.Indptr[m.I]] {
+		dst[j]++
+	}
+	return dst
+}
+
+// sortCompressedIndices sorts the minor indices of each row (or column) of the compressed
+// sparse matrix m into ascending order in place, keeping the values paired with their
+// indices.  Rows that are already sorted are left untouched.
+func sortCompressedIndices(m *blas.SparseMatrix) {
+	var pairs []indexPair
+	for i := 0; i < m.I; i++ {
+		begin, end := m.Indptr[i], m.Indptr[i+1]
+		if sort.IntsAreSorted(m.Ind[begin:end]) {
+			continue
+		}
+		pairs = pairs[:0]
+		for k := begin; k < end; k++ {
+			pairs = append(pairs, indexPair{index: m.Ind[k], value: m.Data[k]})
+		}
+		sort.SliceStable(pairs, func(a, b int) bool {
+			return pairs[a].index < pairs[b].index
+		})
+		for k, p := range pairs {
+			m.Ind[begin+k] = p.index
+			m.Data[begin+k] = p.value
+		}
+	}
+}
+
+// hasSortedIndices returns true if the minor indices of each row (or column) of the
+// compressed sparse matrix m are sorted in ascending order.
+func hasSortedIndices(m *blas.SparseMatrix) bool {
+	for i := 0; i < m.I; i++ {
+		if !sort.IntsAreSorted(m.Ind[m.Indptr[i]:m.Indptr[i+1]]) {
+			return false
+		}
+	}
+	return true
+}
+
+// setKeepsSorted returns true if setting the element at coordinate i, j of the compressed
+// sparse matrix m, whose indices are sorted, to v will leave the indices sorted i.e. if
+// the element is already stored, v is zero (and so not inserted) or the new element
+// will be appended after the last element of the row.
+func setKeepsSorted(m *blas.SparseMatrix, i, j int, v float64) bool {
+	if i < 0 || i >= m.I || v == 0 {
+		return true
+	}
+	begin, end := m.Indptr[i], m.Indptr[i+1]
+	k := begin + sort.SearchInts(m.Ind[begin:end], j)
+	return k == end || m.Ind[k] == j
+}
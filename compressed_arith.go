@@ -1,10 +1,18 @@
 package sparse
 
 import (
+	"errors"
+	"sort"
+
 	"github.com/james-bowman/sparse/blas"
+	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
 
+// ErrStructureMismatch is the panic value used when matrices that are required to have
+// exactly the same sparsity structure do not.
+var ErrStructureMismatch = errors.New("sparse: matrices do not have the same sparsity structure")
+
 // MulMatRawVec computes the matrix vector product between lhs and rhs and stores
 // the result in out
 func MulMatRawVec(lhs *CSR, rhs []float64, out []float64) {
@@ -38,6 +46,59 @@ func (c *CSC) MulVecTo(dst []float64, trans bool, x []float64) {
 	c.T().(*CSR).MulVecTo(dst, !trans, x)
 }
 
+// MulVecInto performs matrix vector multiplication (dst=A*x or dst=A^T*x), where A is
+// the receiver, overwriting the contents of dst with the result.  Unlike MulVecTo, the
+// result is not accumulated into dst.  If dst is empty it will be resized to the correct
+// length, otherwise its backing array is reused and no allocation is performed, making
+// MulVecInto suitable for use inside iterative solvers.  The transposed product is
+// computed by scattering each row of the receiver into dst.  MulVecInto panics if
+// ac != x.Len() or if dst is not empty and ar != dst.Len().
+//
+// MulVecInto differs from the MulVecTo convention of Gonum only in name as the
+// MulVecTo method name is already used for the []float64 based kernel.
+func (c *CSR) MulVecInto(dst *mat.VecDense, trans bool, x mat.Vector) {
+	ar, ac := c.Dims()
+	if trans {
+		ar, ac = ac, ar
+	}
+	if ac != x.Len() {
+		panic(mat.ErrShape)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAsVec(ar)
+	} else if ar != dst.Len() {
+		panic(mat.ErrShape)
+	}
+
+	y := dst.RawVector()
+	var xraw []float64
+	incx := 1
+	if rv, ok := x.(mat.RawVectorer); ok {
+		raw := rv.RawVector()
+		xraw, incx = raw.Data, raw.Inc
+	}
+	if xraw == nil || aliasFloats(y.Data, xraw) {
+		xraw = getFloats(ac, false)
+		defer putFloats(xraw)
+		for i := range xraw {
+			xraw[i] = x.AtVec(i)
+		}
+		incx = 1
+	}
+
+	for i := 0; i < ar; i++ {
+		y.Data[i*y.Inc] = 0
+	}
+	blas.Dusmv(trans, 1, c.RawMatrix(), xraw, incx, y.Data, y.Inc)
+}
+
+// MulVecInto performs matrix vector multiplication (dst=A*x or dst=A^T*x), where A is
+// the receiver, overwriting the contents of dst with the result.  See CSR.MulVecInto
+// for details.
+func (c *CSC) MulVecInto(dst *mat.VecDense, trans bool, x mat.Vector) {
+	c.T().(*CSR).MulVecInto(dst, !trans, x)
+}
+
 // temporaryWorkspace returns a new CSR matrix w with the size of r x c with
 // initial capacity allocated for nnz non-zero elements and
 // returns a callback to defer which performs cleanup at the return of the call.
@@ -45,6 +106,9 @@ func (c *CSC) MulVecTo(dst []float64, trans bool, x []float64) {
 func (c *CSR) temporaryWorkspace(row, col, nnz int, clear bool) (w *CSR, restore func()) {
 	w = getWorkspace(row, col, nnz, clear)
 	return w, func() {
+		// the receiver's storage may be shared with an operand so must not be
+		// overwritten with the result
+		c.matrix.Indptr, c.matrix.Ind, c.matrix.Data = nil, nil, nil
 		c.cloneCSR(w)
 		putWorkspace(w)
 	}
@@ -180,6 +244,49 @@ func (c *CSR) Mul(a, b mat.Matrix) {
 	putFloats(row)
 }
 
+// MulDense computes the matrix product of the receiver and b (A * B), where b is typically a
+// dense matrix with many columns e.g. a block of right hand side vectors, storing the result
+// in dst.  Rather than performing a separate sparse matrix vector product for each column of
+// b, the receiver is traversed just once, row by row, and each stored element a(i, j) scales
+// row j of b into row i of dst so that the traversal of the sparse structure is amortised
+// across all the columns of b and both b and dst are accessed contiguously.  If dst is empty
+// it is resized to the correct dimensions, otherwise dst is overwritten allowing it to be
+// reused across calls without allocating.  b is copied before the multiplication if it
+// does not provide access to its underlying storage (i.e. does not implement
+// mat.RawMatrixer) or shares storage with dst.  For sparse b, see Mul.  MulDense will panic
+// with mat.ErrShape if the number of columns of the receiver does not equal the number of
+// rows of b or dst is not empty and is not the same shape as the result.
+func (c *CSR) MulDense(dst *mat.Dense, b mat.Matrix) {
+	ar, ac := c.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(mat.ErrShape)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(ar, bc)
+	} else if !sameDims(dst, ar, bc) {
+		panic(mat.ErrShape)
+	}
+	draw := dst.RawMatrix()
+
+	rm, ok := b.(mat.RawMatrixer)
+	if !ok || aliasFloats(draw.Data, rm.RawMatrix().Data) {
+		rm = mat.DenseCopyOf(b)
+	}
+	braw := rm.RawMatrix()
+
+	for i := 0; i < ar; i++ {
+		row := draw.Data[i*draw.Stride : i*draw.Stride+bc]
+		for j := range row {
+			row[j] = 0
+		}
+		for k := c.matrix.Indptr[i]; k < c.matrix.Indptr[i+1]; k++ {
+			j := c.matrix.Ind[k]
+			floats.AddScaled(row, c.matrix.Data[k], braw.Data[j*braw.Stride:j*braw.Stride+bc])
+		}
+	}
+}
+
 // mulCSRCSR handles CSR = CSR * CSR using Gustavson Algorithm (ACM 1978)
 func (c *CSR) mulCSRCSR(lhs *CSR, rhs *CSR) {
 	ar, _ := lhs.Dims()
@@ -372,6 +479,95 @@ func (c *CSR) addDIADIA(a, b *DIA, alpha, beta float64) {
 	}
 }
 
+// Scale multiplies the elements of a by alpha, placing the result in the receiver
+// following the Gonum mat.Scaler convention.  If a is the receiver, the stored values
+// are scaled in place without copying, otherwise the sparsity pattern of a is copied
+// into the receiver (a is first converted to CSR if it is in a different format) with
+// each stored value scaled by alpha.  If alpha is exactly zero, the result is an empty
+// matrix of the same dimensions as a with no stored elements rather than a matrix of
+// explicitly stored zeros.  Scale will panic if the receiver is not zero-sized and
+// is a different shape to a.
+func (c *CSR) Scale(alpha float64, a mat.Matrix) {
+	if c == a {
+		if alpha == 0 {
+			c.reuseAs(c.matrix.I, c.matrix.J, 0, true)
+			return
+		}
+		for i := range c.matrix.Data {
+			c.matrix.Data[i] *= alpha
+		}
+		return
+	}
+
+	src := csrOf(a)
+	if m, temp, restore := c.spalloc(src, src); temp {
+		defer restore()
+		c = m
+	}
+	if alpha == 0 {
+		return
+	}
+
+	copy(c.matrix.Indptr, src.matrix.Indptr)
+	c.matrix.Ind = append(c.matrix.Ind, src.matrix.Ind...)
+	for _, v := range src.matrix.Data {
+		c.matrix.Data = append(c.matrix.Data, alpha*v)
+	}
+}
+
+// Scale multiplies the elements of a by alpha, placing the result in the receiver
+// following the Gonum mat.Scaler convention.  If a is the receiver, the stored values
+// are scaled in place without copying, otherwise the sparsity pattern of a is copied
+// into the receiver (a is first converted to CSC if it is in a different format) with
+// each stored value scaled by alpha.  If alpha is exactly zero, the result is an empty
+// matrix of the same dimensions as a with no stored elements rather than a matrix of
+// explicitly stored zeros.  Scale will panic if the receiver is not zero-sized and
+// is a different shape to a.
+func (c *CSC) Scale(alpha float64, a mat.Matrix) {
+	// as CSC is the natural transpose of CSR, scale the transposes as CSR matrices
+	t := &CSR{matrix: c.matrix}
+	if c == a {
+		t.Scale(alpha, t)
+	} else {
+		t.Scale(alpha, &CSR{matrix: cscOf(a).matrix})
+		c.sorted = false
+	}
+	c.matrix = t.matrix
+}
+
+// ScaleRows multiplies, in place, the stored values of each row i of the receiver by d[i],
+// equivalent to pre-multiplying the receiver by the diagonal matrix with d along its main
+// diagonal (D * A).  ScaleRows runs in O(NNZ) and does not change the sparsity pattern of the
+// receiver, so scaling a row by zero leaves its elements stored as explicit zeros; these may
+// subsequently be removed with Prune(0).  Combined with ScaleCols, this computes D1 * A * D2
+// as used for diagonal preconditioning and equilibration.  ScaleRows will panic with
+// mat.ErrShape if len(d) does not equal the number of rows of the receiver.
+func (c *CSR) ScaleRows(d []float64) {
+	if len(d) != c.matrix.I {
+		panic(mat.ErrShape)
+	}
+	for i, di := range d {
+		for k := c.matrix.Indptr[i]; k < c.matrix.Indptr[i+1]; k++ {
+			c.matrix.Data[k] *= di
+		}
+	}
+}
+
+// ScaleCols multiplies, in place, each stored value of the receiver in column j by d[j],
+// equivalent to post-multiplying the receiver by the diagonal matrix with d along its main
+// diagonal (A * D).  ScaleCols runs in O(NNZ) and does not change the sparsity pattern of the
+// receiver, so scaling a column by zero leaves its elements stored as explicit zeros; these
+// may subsequently be removed with Prune(0).  ScaleCols will panic with mat.ErrShape if
+// len(d) does not equal the number of columns of the receiver.
+func (c *CSR) ScaleCols(d []float64) {
+	if len(d) != c.matrix.J {
+		panic(mat.ErrShape)
+	}
+	for k, j := range c.matrix.Ind {
+		c.matrix.Data[k] *= d[j]
+	}
+}
+
 // Sub subtracts matrix b from a and stores the result in the receiver.
 // If matrices a and b are not the same shape then the method will panic.
 func (c *CSR) Sub(a, b mat.Matrix) {
@@ -380,10 +576,48 @@ func (c *CSR) Sub(a, b mat.Matrix) {
 
 // Add adds matrices a and b together and stores the result in the receiver.
 // If matrices a and b are not the same shape then the method will panic.
+// Where a and b are both CSR matrices, their structures are merged row by row
+// summing coincident elements so that the result is a compressed CSR matrix with
+// sorted column indices in each row and without any elements that cancelled out
+// to exactly zero.  a and b may be the same matrix and the receiver may alias
+// either operand.
 func (c *CSR) Add(a, b mat.Matrix) {
 	c.addScaled(a, b, 1, 1)
 }
 
+// AddSameStructure adds matrices a and b, which must have exactly the same sparsity structure
+// (see SameStructure), storing the result in the receiver.  As the stored elements of a and b
+// coincide, the sum is computed directly from their Data slices, element by element, in O(NNZ)
+// without the structural merge performed by Add.  This suits iterative updates of matrices
+// whose sparsity pattern is fixed.  The result has the structure of a (and b), including any
+// explicitly stored zeros and elements that sum to zero.  If the receiver is a or b, or already
+// has the same structure as a, only its values are updated, otherwise the structure of a is
+// copied into the receiver reusing its existing storage where possible.  AddSameStructure will
+// panic with ErrUnsortedIndices if the column indices of a or b are not sorted,
+// ErrStructureMismatch if a and b do not have the same structure or mat.ErrShape if the
+// receiver is not zero-sized and is a different shape to a.
+func (c *CSR) AddSameStructure(a, b *CSR) {
+	if !SameStructure(a, b) {
+		panic(ErrStructureMismatch)
+	}
+
+	if c != a && c != b && !(c.matrix.I == a.matrix.I && c.matrix.J == a.matrix.J &&
+		equalInts(c.matrix.Indptr, a.matrix.Indptr) && equalInts(c.matrix.Ind, a.matrix.Ind)) {
+		if c.checkOverlap(a) || c.checkOverlap(b) {
+			// don't reuse storage shared with the operands
+			c.matrix.Indptr, c.matrix.Ind, c.matrix.Data = nil, nil, nil
+		}
+		c.reuseAs(a.matrix.I, a.matrix.J, len(a.matrix.Data), false)
+		copy(c.matrix.Indptr, a.matrix.Indptr)
+		copy(c.matrix.Ind, a.matrix.Ind)
+	}
+
+	for k, v := range a.matrix.Data {
+		c.matrix.Data[k] = v + b.matrix.Data[k]
+	}
+	c.sorted = true
+}
+
 // addScaled adds matrices a and b scaling them by a and b respectively before hand.
 func (c *CSR) addScaled(a mat.Matrix, b mat.Matrix, alpha float64, beta float64) {
 	ar, ac := a.Dims()
@@ -472,7 +706,9 @@ func (c *CSR) addCSR(csr *CSR, other mat.Matrix, alpha float64, beta float64) {
 // addCSRCSR adds 2 CSR matrices together storing the result in the receiver.
 // Matrices a and b are scaled by alpha and beta respectively before addition.
 // This method is specially optimised to take advantage of the sparsity patterns
-// of the 2 CSR matrices.
+// of the 2 CSR matrices, merging the structure of the 2 matrices row by row.
+// Elements that cancel to exactly zero are dropped from the result and the
+// column indices of each row of the result are sorted.
 func (c *CSR) addCSRCSR(lhs *CSR, rhs *CSR, alpha float64, beta float64) {
 	ar, ac := lhs.Dims()
 	a := lhs.RawMatrix()
@@ -487,7 +723,7 @@ func (c *CSR) addCSRCSR(lhs *CSR, rhs *CSR, alpha float64, beta float64) {
 		begin, end = b.Indptr[i], b.Indptr[i+1]
 		spa.Scatter(b.Data[begin:end], b.Ind[begin:end], beta, &c.matrix.Ind)
 
-		spa.GatherAndZero(&c.matrix.Data, &c.matrix.Ind)
+		spa.gatherSortedNonZeroAndZero(&c.matrix.Data, &c.matrix.Ind)
 		c.matrix.Indptr[i+1] = len(c.matrix.Ind)
 	}
 }
@@ -567,6 +803,27 @@ func (s SPA) Gather(data *[]float64, ind *[]int) {
 	}
 }
 
+// gatherSortedNonZeroAndZero gathers the values from the SPA, appending them to the
+// end of the supplied sparse vector in ascending index order and omitting any values
+// that are exactly zero (e.g. where accumulated values cancelled each other out).
+// The SPA is also zeroed ready to start accumulating the next row/column vector.
+func (s *SPA) gatherSortedNonZeroAndZero(data *[]float64, ind *[]int) {
+	accumulated := (*ind)[s.nnz:]
+	sort.Ints(accumulated)
+	k := s.nnz
+	for _, index := range accumulated {
+		if v := s.y[index]; v != 0 {
+			(*ind)[k] = index
+			*data = append(*data, v)
+			k++
+		}
+	}
+	*ind = (*ind)[:k]
+
+	s.nnz = k
+	s.generation++
+}
+
 // GatherAndZero gathers the non-zero values from the SPA and appends them
 // to the end of the supplied sparse vector.  The SPA is also zeroed
 // ready to start accumulating the next row/column vector.
@@ -131,6 +131,95 @@ func TestCompressedMulVecTo(t *testing.T) {
 	}
 }
 
+func TestCompressedMulVecInto(t *testing.T) {
+	// A:
+	// 1, 0, 2, 0,
+	// 0, 0, 0, 0,
+	// 0, 3, 4, 5,
+	csr := NewCSR(3, 4, []int{0, 2, 2, 5}, []int{0, 2, 1, 2, 3}, []float64{1, 2, 3, 4, 5})
+	a := csr.ToDense()
+
+	strided := mat.NewDense(4, 2, []float64{
+		1, -1,
+		2, -1,
+		0, -1,
+		3, -1,
+	})
+
+	tests := []struct {
+		trans bool
+		x     mat.Vector
+		dst   *mat.VecDense
+	}{
+		{trans: false, x: mat.NewVecDense(4, []float64{1, 2, 0, 3}), dst: &mat.VecDense{}},
+		{trans: false, x: mat.NewVecDense(4, []float64{1, 2, 0, 3}), dst: mat.NewVecDense(3, []float64{7, 8, 9})},
+		{trans: false, x: strided.ColView(0), dst: mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}).ColView(1).(*mat.VecDense)},
+		{trans: false, x: NewVector(4, []int{0, 3}, []float64{1, 3}), dst: &mat.VecDense{}},
+		{trans: true, x: mat.NewVecDense(3, []float64{1, 2, 3}), dst: &mat.VecDense{}},
+		{trans: true, x: mat.NewVecDense(3, []float64{1, 2, 3}), dst: mat.NewVecDense(4, []float64{1, 1, 1, 1})},
+		{trans: true, x: NewVector(3, []int{2}, []float64{2}), dst: &mat.VecDense{}},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		var expected mat.VecDense
+		if test.trans {
+			expected.MulVec(a.T(), test.x)
+		} else {
+			expected.MulVec(a, test.x)
+		}
+
+		for _, m := range []interface {
+			MulVecInto(*mat.VecDense, bool, mat.Vector)
+		}{csr, csr.ToCSC()} {
+			var dst mat.VecDense
+			if !test.dst.IsEmpty() {
+				dst.CloneFromVec(test.dst)
+			}
+			m.MulVecInto(&dst, test.trans, test.x)
+			if !mat.Equal(&expected, &dst) {
+				t.Logf("%T: Expected:\n%v\n but received:\n%v\n", m, mat.Formatted(&expected), mat.Formatted(&dst))
+				t.Fail()
+			}
+		}
+		if !test.dst.IsEmpty() {
+			csr.MulVecInto(test.dst, test.trans, test.x)
+			if !mat.Equal(&expected, test.dst) {
+				t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(test.dst))
+				t.Fail()
+			}
+		}
+	}
+
+	// dst aliasing x
+	sq := NewCSR(2, 2, []int{0, 2, 3}, []int{0, 1, 1}, []float64{1, 2, 3})
+	v := mat.NewVecDense(2, []float64{1, 1})
+	sq.MulVecInto(v, false, v)
+	if !mat.Equal(v, mat.NewVecDense(2, []float64{3, 3})) {
+		t.Logf("Expected aliased result [3 3] but received %v\n", mat.Formatted(v.T()))
+		t.Fail()
+	}
+
+	// no allocation when dst is already the correct length
+	x := mat.NewVecDense(4, []float64{1, 2, 0, 3})
+	dst := mat.NewVecDense(3, nil)
+	if allocs := testing.AllocsPerRun(10, func() { csr.MulVecInto(dst, false, x) }); allocs != 0 {
+		t.Logf("Expected no allocations but received %v\n", allocs)
+		t.Fail()
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != mat.ErrShape {
+				t.Logf("Expected panic %v for wrong length dst but received %v\n", mat.ErrShape, r)
+				t.Fail()
+			}
+		}()
+		csr.MulVecInto(mat.NewVecDense(4, nil), false, x)
+	}()
+}
+
 func TestCSRMul(t *testing.T) {
 	var tests = []struct {
 		atype  MatrixCreator
@@ -920,6 +1009,70 @@ func TestCSRAdd(t *testing.T) {
 	}
 }
 
+func TestCSRAddStructuralMerge(t *testing.T) {
+	adata := []float64{
+		1, 0, 2, 0,
+		0, 3, 0, -4,
+		5, 0, 0, 0,
+	}
+	bdata := []float64{
+		0, 6, -2, 0,
+		0, 1, 0, 4,
+		-5, 0, 0, 0,
+	}
+	sum := mat.NewDense(3, 4, []float64{
+		1, 6, 0, 0,
+		0, 4, 0, 0,
+		0, 0, 0, 0,
+	})
+	double := mat.NewDense(3, 4, nil)
+	double.Scale(2, mat.NewDense(3, 4, adata))
+
+	checkCompressed := func(desc string, expected mat.Matrix, m *CSR) {
+		if !mat.Equal(expected, m) {
+			t.Logf("%s: Expected:\n%v\n but received:\n%v\n", desc, mat.Formatted(expected), mat.Formatted(m))
+			t.Fail()
+		}
+		raw := m.RawMatrix()
+		for i := 0; i < raw.I; i++ {
+			for k := raw.Indptr[i]; k < raw.Indptr[i+1]; k++ {
+				if raw.Data[k] == 0 {
+					t.Logf("%s: Unexpected stored zero at (%d, %d)\n", desc, i, raw.Ind[k])
+					t.Fail()
+				}
+				if k > raw.Indptr[i] && raw.Ind[k] <= raw.Ind[k-1] {
+					t.Logf("%s: Column indices of row %d not sorted: %v\n", desc, i, raw.Ind[raw.Indptr[i]:raw.Indptr[i+1]])
+					t.Fail()
+				}
+			}
+		}
+	}
+
+	var c CSR
+	c.Add(CreateCSR(3, 4, adata), CreateCSR(3, 4, bdata))
+	checkCompressed("CSR + CSR", sum, &c)
+	if c.NNZ() != 3 {
+		t.Logf("Expected cancelled elements to be dropped leaving 3 non-zeros but found %d\n", c.NNZ())
+		t.Fail()
+	}
+
+	a := CreateCSR(3, 4, adata)
+	c.Add(a, a)
+	checkCompressed("a + a", double, &c)
+
+	lhs := CreateCSR(3, 4, adata).(*CSR)
+	lhs.Add(lhs, CreateCSR(3, 4, bdata))
+	checkCompressed("receiver aliasing a", sum, lhs)
+
+	rhs := CreateCSR(3, 4, bdata).(*CSR)
+	rhs.Add(CreateCSR(3, 4, adata), rhs)
+	checkCompressed("receiver aliasing b", sum, rhs)
+
+	both := CreateCSR(3, 4, adata).(*CSR)
+	both.Add(both, both)
+	checkCompressed("receiver aliasing a and b", double, both)
+}
+
 func TestCSRSub(t *testing.T) {
 	var tests = []struct {
 		atype  MatrixCreator
@@ -1050,3 +1203,495 @@ func TestCSRSub(t *testing.T) {
 		}
 	}
 }
+
+func TestCompressedScale(t *testing.T) {
+	var tests = []struct {
+		r, c  int
+		data  []float64
+		alpha float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 2, 0,
+				0, 0, 0, -4,
+				5, 0, 0, 6,
+			},
+			alpha: 2.5,
+		},
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 2, 0,
+				0, 0, 0, -4,
+				5, 0, 0, 6,
+			},
+			alpha: 0,
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+			alpha: -1,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		var expected mat.Dense
+		expected.Scale(test.alpha, mat.NewDense(test.r, test.c, test.data))
+		nnz := CreateCSR(test.r, test.c, test.data).(*CSR).NNZ()
+		if test.alpha == 0 {
+			nnz = 0
+		}
+
+		results := []struct {
+			desc string
+			m    func() Sparser
+		}{
+			{"CSR from CSR", func() Sparser {
+				var c CSR
+				c.Scale(test.alpha, CreateCSR(test.r, test.c, test.data))
+				return &c
+			}},
+			{"CSR from Dense", func() Sparser {
+				var c CSR
+				c.Scale(test.alpha, mat.NewDense(test.r, test.c, test.data))
+				return &c
+			}},
+			{"CSR in place", func() Sparser {
+				c := CreateCSR(test.r, test.c, test.data).(*CSR)
+				c.Scale(test.alpha, c)
+				return c
+			}},
+			{"CSR sharing storage", func() Sparser {
+				a := CreateCSR(test.r, test.c, test.data).(*CSR)
+				c := NewCSR(test.r, test.c, a.matrix.Indptr, a.matrix.Ind, a.matrix.Data)
+				c.Scale(test.alpha, a)
+				return c
+			}},
+			{"CSC from CSC", func() Sparser {
+				var c CSC
+				c.Scale(test.alpha, CreateCSC(test.r, test.c, test.data))
+				return &c
+			}},
+			{"CSC from CSR", func() Sparser {
+				var c CSC
+				c.Scale(test.alpha, CreateCSR(test.r, test.c, test.data))
+				return &c
+			}},
+			{"CSC in place", func() Sparser {
+				c := CreateCSC(test.r, test.c, test.data).(*CSC)
+				c.Scale(test.alpha, c)
+				return c
+			}},
+		}
+
+		for _, result := range results {
+			m := result.m()
+			if r, c := m.Dims(); r != test.r || c != test.c {
+				t.Logf("%s: Expected dimensions %dx%d but received %dx%d\n", result.desc, test.r, test.c, r, c)
+				t.Fail()
+			}
+			if !mat.Equal(&expected, m) {
+				t.Logf("%s: Expected:\n%v\n but received:\n%v\n", result.desc, mat.Formatted(&expected), mat.Formatted(m))
+				t.Fail()
+			}
+			if m.NNZ() != nnz {
+				t.Logf("%s: Expected %d stored elements but found %d\n", result.desc, nnz, m.NNZ())
+				t.Fail()
+			}
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != mat.ErrShape {
+			t.Errorf("Expected panic with mat.ErrShape for mismatched receiver but received %v", r)
+		}
+	}()
+	c := CreateCSR(2, 2, []float64{1, 0, 0, 1}).(*CSR)
+	c.Scale(2, CreateCSR(3, 3, nil))
+}
+
+func TestCSRScaleRowsCols(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+		rows []float64
+		cols []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 2, 0,
+				0, 0, 0, -4,
+				5, 0, 0, 6,
+			},
+			rows: []float64{2, -1, 0.5},
+			cols: []float64{1, 3, -2, 0.25},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				1, 2,
+				3, 4,
+			},
+			rows: []float64{0, 1},
+			cols: []float64{1, 0},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := mat.NewDense(test.r, test.c, test.data)
+		d1 := mat.NewDiagDense(test.r, test.rows)
+		d2 := mat.NewDiagDense(test.c, test.cols)
+
+		var expected mat.Dense
+		expected.Mul(d1, a)
+		csr := CreateCSR(test.r, test.c, test.data).(*CSR)
+		nnz := csr.NNZ()
+		csr.ScaleRows(test.rows)
+		if !mat.Equal(&expected, csr) {
+			t.Logf("ScaleRows expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(csr))
+			t.Fail()
+		}
+
+		expected.Mul(a, d2)
+		csr = CreateCSR(test.r, test.c, test.data).(*CSR)
+		csr.ScaleCols(test.cols)
+		if !mat.Equal(&expected, csr) {
+			t.Logf("ScaleCols expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(csr))
+			t.Fail()
+		}
+
+		expected.Mul(d1, a)
+		expected.Mul(&expected, d2)
+		csr = CreateCSR(test.r, test.c, test.data).(*CSR)
+		csr.ScaleRows(test.rows)
+		csr.ScaleCols(test.cols)
+		if !mat.Equal(&expected, csr) {
+			t.Logf("D1 * A * D2 expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(csr))
+			t.Fail()
+		}
+		if csr.NNZ() != nnz {
+			t.Logf("Expected sparsity pattern to be unchanged with %d stored elements but received %d\n", nnz, csr.NNZ())
+			t.Fail()
+		}
+	}
+}
+
+func TestCSRScaleRowsColsPanics(t *testing.T) {
+	csr := CreateCSR(2, 3, []float64{1, 0, 2, 0, 3, 0}).(*CSR)
+
+	var tests = []func(){
+		func() { csr.ScaleRows([]float64{1, 2, 3}) },
+		func() { csr.ScaleRows(nil) },
+		func() { csr.ScaleCols([]float64{1, 2}) },
+		func() { csr.ScaleCols([]float64{1, 2, 3, 4}) },
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		func() {
+			defer func() {
+				if r := recover(); r != mat.ErrShape {
+					t.Logf("Expected panic with %v but received %v\n", mat.ErrShape, r)
+					t.Fail()
+				}
+			}()
+			test()
+		}()
+	}
+}
+
+func TestCSRAddSameStructure(t *testing.T) {
+	newA := func() *CSR {
+		return NewCSR(3, 4, []int{0, 2, 2, 5}, []int{0, 3, 0, 2, 3}, []float64{1, 7, 3, 3, 6})
+	}
+	newB := func() *CSR {
+		return NewCSR(3, 4, []int{0, 2, 2, 5}, []int{0, 3, 0, 2, 3}, []float64{-1, 2, 0, 4, 5})
+	}
+	expected := mat.NewDense(3, 4, []float64{
+		0, 0, 0, 9,
+		0, 0, 0, 0,
+		3, 0, 7, 11,
+	})
+
+	var tests = []struct {
+		desc string
+		fn   func() *CSR
+	}{
+		{"zero receiver", func() *CSR {
+			var c CSR
+			c.AddSameStructure(newA(), newB())
+			return &c
+		}},
+		{"receiver is a", func() *CSR {
+			a := newA()
+			a.AddSameStructure(a, newB())
+			return a
+		}},
+		{"receiver is b", func() *CSR {
+			b := newB()
+			b.AddSameStructure(newA(), b)
+			return b
+		}},
+		{"receiver with same structure", func() *CSR {
+			c := newA()
+			c.AddSameStructure(newA(), newB())
+			return c
+		}},
+		{"receiver with different structure", func() *CSR {
+			c := CreateCSR(3, 4, []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}).(*CSR)
+			c.AddSameStructure(newA(), newB())
+			return c
+		}},
+		{"reset receiver sharing storage with a", func() *CSR {
+			a := newA()
+			c := NewCSR(3, 4, a.matrix.Indptr, a.matrix.Ind, a.matrix.Data)
+			c.Reset()
+			c.AddSameStructure(a, newB())
+			if a.At(2, 3) != 6 {
+				t.Errorf("Expected a to be unmodified but received %v", a.At(2, 3))
+			}
+			return c
+		}},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		c := test.fn()
+		if !mat.Equal(expected, c) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(c))
+			t.Fail()
+		}
+		if !SameStructure(c, newA()) {
+			t.Logf("Expected result to have the same structure as the operands\n")
+			t.Fail()
+		}
+	}
+}
+
+func TestCSRAddSameStructureAllocs(t *testing.T) {
+	a := NewCSR(3, 4, []int{0, 2, 2, 5}, []int{0, 3, 0, 2, 3}, []float64{1, 7, 3, 3, 6})
+	b := NewCSR(3, 4, []int{0, 2, 2, 5}, []int{0, 3, 0, 2, 3}, []float64{-1, 2, 0, 4, 5})
+	var c CSR
+	c.AddSameStructure(a, b)
+
+	if allocs := testing.AllocsPerRun(10, func() { c.AddSameStructure(a, b) }); allocs != 0 {
+		t.Errorf("Expected no allocations reusing the receiver but received %v", allocs)
+	}
+}
+
+func TestCSRAddSameStructurePanics(t *testing.T) {
+	a := NewCSR(2, 3, []int{0, 1, 2}, []int{0, 2}, []float64{1, 2})
+
+	var tests = []struct {
+		desc     string
+		fn       func()
+		expected interface{}
+	}{
+		{"different structure", func() {
+			var c CSR
+			c.AddSameStructure(a, NewCSR(2, 3, []int{0, 1, 2}, []int{1, 2}, []float64{1, 2}))
+		}, ErrStructureMismatch},
+		{"different shape", func() {
+			var c CSR
+			c.AddSameStructure(a, NewCSR(2, 4, []int{0, 1, 2}, []int{0, 2}, []float64{1, 2}))
+		}, ErrStructureMismatch},
+		{"unsorted", func() {
+			var c CSR
+			u := NewCSR(2, 3, []int{0, 2, 2}, []int{2, 0}, []float64{1, 2})
+			c.AddSameStructure(u, u)
+		}, ErrUnsortedIndices},
+		{"receiver shape", func() {
+			c := NewCSR(3, 3, nil, nil, nil)
+			c.AddSameStructure(a, a)
+		}, mat.ErrShape},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		func() {
+			defer func() {
+				if r := recover(); r != test.expected {
+					t.Logf("Expected panic with %v but received %v\n", test.expected, r)
+					t.Fail()
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
+
+func TestCSRMulDense(t *testing.T) {
+	var tests = []struct {
+		ar, ac, bc int
+		a, b       []float64
+	}{
+		{
+			ar: 3, ac: 4, bc: 3,
+			a: []float64{
+				1, 0, 2, 0,
+				0, 0, 0, 0,
+				0, -3, 0, 4,
+			},
+			b: []float64{
+				1, 2, 3,
+				4, 5, 6,
+				7, 8, 9,
+				-1, 0, 1,
+			},
+		},
+		{
+			ar: 2, ac: 2, bc: 1,
+			a: []float64{
+				0, 0,
+				0, 0,
+			},
+			b: []float64{
+				1,
+				2,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := CreateCSR(test.ar, test.ac, test.a).(*CSR)
+		b := mat.NewDense(test.ac, test.bc, test.b)
+		var expected mat.Dense
+		expected.Mul(mat.NewDense(test.ar, test.ac, test.a), b)
+
+		// b as a view with a stride greater than its number of columns
+		wide := mat.NewDense(test.ac, test.bc+2, nil)
+		view := wide.Slice(0, test.ac, 1, test.bc+1).(*mat.Dense)
+		view.Copy(b)
+
+		for _, rhs := range []mat.Matrix{b, view, b.T().T()} {
+			var dst mat.Dense
+			a.MulDense(&dst, rhs)
+			if !mat.Equal(&expected, &dst) {
+				t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(&dst))
+				t.Fail()
+			}
+
+			// reusing dst should overwrite rather than accumulate
+			a.MulDense(&dst, rhs)
+			if !mat.Equal(&expected, &dst) {
+				t.Logf("Reused dst expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(&dst))
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestCSRMulDenseAliased(t *testing.T) {
+	a := CreateCSR(2, 2, []float64{0, 1, 2, 3}).(*CSR)
+	b := mat.NewDense(2, 2, []float64{1, 2, 3, 4})
+	var expected mat.Dense
+	expected.Mul(a.ToDense(), b)
+
+	a.MulDense(b, b)
+	if !mat.Equal(&expected, b) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(b))
+	}
+}
+
+func TestCSRMulDenseAllocs(t *testing.T) {
+	a := CreateCSR(3, 4, []float64{1, 0, 2, 0, 0, 0, 0, 0, 0, -3, 0, 4}).(*CSR)
+	b := mat.NewDense(4, 5, nil)
+	var dst mat.Dense
+	a.MulDense(&dst, b)
+
+	if allocs := testing.AllocsPerRun(10, func() { a.MulDense(&dst, b) }); allocs != 0 {
+		t.Errorf("Expected no allocations reusing dst but received %v", allocs)
+	}
+}
+
+func TestCSRMulDensePanics(t *testing.T) {
+	a := CreateCSR(2, 3, []float64{1, 0, 2, 0, 3, 0}).(*CSR)
+
+	var tests = []func(){
+		func() { a.MulDense(&mat.Dense{}, mat.NewDense(2, 2, nil)) },
+		func() { a.MulDense(mat.NewDense(2, 3, nil), mat.NewDense(3, 2, nil)) },
+		func() { a.MulDense(mat.NewDense(3, 2, nil), mat.NewDense(3, 2, nil)) },
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		func() {
+			defer func() {
+				if r := recover(); r != mat.ErrShape {
+					t.Logf("Expected panic with %v but received %v\n", mat.ErrShape, r)
+					t.Fail()
+				}
+			}()
+			test()
+		}()
+	}
+}
+
+func TestCSRReceiverSharingOperandStorage(t *testing.T) {
+	data := []float64{
+		1, 0,
+		0, 2,
+	}
+	x := CreateCSR(2, 2, []float64{10, 0, 0, 20})
+
+	var tests = []struct {
+		desc     string
+		op       func(b, a *CSR)
+		expected []float64
+	}{
+		{
+			desc:     "Add",
+			op:       func(b, a *CSR) { b.Add(a, x) },
+			expected: []float64{11, 0, 0, 22},
+		},
+		{
+			desc:     "Mul",
+			op:       func(b, a *CSR) { b.Mul(a, x) },
+			expected: []float64{10, 0, 0, 40},
+		},
+		{
+			desc:     "MulParallel",
+			op:       func(b, a *CSR) { MulParallel(b, a, x, 2) },
+			expected: []float64{10, 0, 0, 40},
+		},
+		{
+			desc:     "Clone",
+			op:       func(b, a *CSR) { b.Clone(a.T()) },
+			expected: []float64{1, 0, 0, 2},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		a := CreateCSR(2, 2, data).(*CSR)
+		raw := a.RawMatrix()
+		b := NewCSR(2, 2, raw.Indptr, raw.Ind, raw.Data)
+
+		test.op(b, a)
+
+		if expected := mat.NewDense(2, 2, data); !mat.Equal(expected, a) {
+			t.Logf("Expected operand to be unmodified:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(a))
+			t.Fail()
+		}
+		if expected := mat.NewDense(2, 2, test.expected); !mat.Equal(expected, b) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(b))
+			t.Fail()
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package sparse
 
 import (
+	"reflect"
+	"sort"
 	"testing"
 
+	"github.com/james-bowman/sparse/blas"
 	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
@@ -365,6 +368,199 @@ func TestCSRCSCRowColView(t *testing.T) {
 	}
 }
 
+func TestCSRCSCRawRowCol(t *testing.T) {
+	data := []float64{
+		1, 0, 0, 0,
+		0, 0, 0, 0,
+		0, 2, 3, 6,
+	}
+	csr := CreateCSR(3, 4, data).(*CSR)
+	csc := CreateCSC(3, 4, data).(*CSC)
+
+	for i := 0; i < 3; i++ {
+		ind, vals := csr.RawRow(i)
+		if len(ind) != len(vals) || len(ind) != csr.RowNNZ(i) || cap(ind) != len(ind) || cap(vals) != len(vals) {
+			t.Errorf("ROW %d: Expected %d elements but received ind %v, data %v", i, csr.RowNNZ(i), ind, vals)
+		}
+		for k, j := range ind {
+			if vals[k] != data[i*4+j] {
+				t.Errorf("ROW %d: Expected %v at column %d but received %v", i, data[i*4+j], j, vals[k])
+			}
+		}
+	}
+
+	for j := 0; j < 4; j++ {
+		ind, vals := csc.RawCol(j)
+		if len(ind) != len(vals) || len(ind) != csc.ColNNZ(j) || cap(ind) != len(ind) || cap(vals) != len(vals) {
+			t.Errorf("COL %d: Expected %d elements but received ind %v, data %v", j, csc.ColNNZ(j), ind, vals)
+		}
+		for k, i := range ind {
+			if vals[k] != data[i*4+j] {
+				t.Errorf("COL %d: Expected %v at row %d but received %v", j, data[i*4+j], i, vals[k])
+			}
+		}
+	}
+
+	// returned slices alias the receiver's storage
+	_, vals := csr.RawRow(0)
+	vals[0] = 7
+	if v := csr.At(0, 0); v != 7 {
+		t.Errorf("Expected RawRow to alias storage but At(0, 0) = %v", v)
+	}
+	_, vals = csc.RawCol(3)
+	vals[0] = 8
+	if v := csc.At(2, 3); v != 8 {
+		t.Errorf("Expected RawCol to alias storage but At(2, 3) = %v", v)
+	}
+
+	for _, fn := range []func(){
+		func() { csr.RawRow(3) },
+		func() { csr.RawRow(-1) },
+		func() { csc.RawCol(4) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic for out of range index")
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestCSRCSCToDenseInto(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 7,
+				0, 2, 0, 0,
+				0, 0, 3, 6,
+			},
+		},
+		{
+			r: 3, c: 4,
+			data: []float64{
+				0, 5, 0, 0,
+				0, 0, 0, 0,
+				8, 0, 0, 0,
+			},
+		},
+		{
+			r: 3, c: 4,
+			data: []float64{
+				0, 0, 0, 0,
+				0, 0, 0, 0,
+				0, 0, 0, 0,
+			},
+		},
+		{
+			// different size requiring reallocation
+			r: 2, c: 2,
+			data: []float64{
+				1, 0,
+				0, 4,
+			},
+		},
+	}
+
+	var csrDst, cscDst *mat.Dense
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.data)
+
+		prevCSR, prevCSC := csrDst, cscDst
+		csrDst = CreateCSR(test.r, test.c, test.data).(*CSR).ToDenseInto(csrDst)
+		cscDst = CreateCSC(test.r, test.c, test.data).(*CSC).ToDenseInto(cscDst)
+
+		if !mat.Equal(expected, csrDst) {
+			t.Logf("CSR: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csrDst))
+			t.Fail()
+		}
+		if !mat.Equal(expected, cscDst) {
+			t.Logf("CSC: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(cscDst))
+			t.Fail()
+		}
+
+		if ti > 0 && test.r == 3 && (csrDst != prevCSR || cscDst != prevCSC) {
+			t.Logf("Expected destination matrix to be reused")
+			t.Fail()
+		}
+	}
+}
+
+func TestCSCScatterCols(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+		cols []int
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 0,
+				0, 2, 0, 0,
+				0, 0, 3, 6,
+			},
+			cols: []int{3, 0, 1},
+		},
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 0,
+				0, 0, 0, 0,
+				0, 0, 3, 0,
+			},
+			cols: []int{1, 2, 2, 0},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		csc := CreateCSC(test.r, test.c, test.data).(*CSC)
+
+		expected := mat.NewDense(test.r, len(test.cols), nil)
+		for k, j := range test.cols {
+			expected.SetCol(k, csc.ScatterCol(j, nil))
+		}
+
+		result := csc.ScatterCols(test.cols, nil)
+		if !mat.Equal(expected, result) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+			t.Fail()
+		}
+
+		// reuse a dirty destination matrix
+		dst := mat.NewDense(test.r, len(test.cols), nil)
+		for i := 0; i < test.r; i++ {
+			for k := range test.cols {
+				dst.Set(i, k, -1)
+			}
+		}
+		result = csc.ScatterCols(test.cols, dst)
+		if result != dst || !mat.Equal(expected, result) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+			t.Fail()
+		}
+	}
+
+	csc := CreateCSC(3, 4, nil).(*CSC)
+	if result := csc.ScatterCols(nil, nil); result != nil {
+		t.Errorf("Expected nil scattering no columns into nil dst but received %v", result)
+	}
+	dst := mat.NewDense(3, 1, []float64{1, 2, 3})
+	if result := csc.ScatterCols([]int{}, dst); result != dst || !mat.Equal(dst, mat.NewDense(3, 1, []float64{1, 2, 3})) {
+		t.Errorf("Expected dst to be returned unchanged scattering no columns")
+	}
+}
+
 func TestCSRCSCDoNonZero(t *testing.T) {
 	var tests = []struct {
 		r, c int
@@ -466,6 +662,22 @@ func TestCSTrace(t *testing.T) {
 	}
 }
 
+func TestCSTraceNonSquare(t *testing.T) {
+	for _, m := range []mat.Matrix{
+		CreateCSR(2, 3, []float64{1, 0, 0, 0, 1, 0}),
+		CreateCSC(3, 2, []float64{1, 0, 0, 1, 0, 0}),
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != mat.ErrShape {
+					t.Errorf("Expected panic with mat.ErrShape for %T Trace of non-square matrix but received %v", m, r)
+				}
+			}()
+			m.(interface{ Trace() float64 }).Trace()
+		}()
+	}
+}
+
 func TestCSRCSCCull(t *testing.T) {
 	var tests = []struct {
 		r, c     int
@@ -604,3 +816,903 @@ func TestCSRCSCCull(t *testing.T) {
 		}
 	}
 }
+
+func TestCSRRound(t *testing.T) {
+	var tests = []struct {
+		r, c     int
+		data     []float64
+		decimals int
+		expected []float64
+		nnz      int
+		nnzE     int
+	}{
+		{
+			r: 2, c: 3,
+			data: []float64{
+				1.2345, 0, 0.004,
+				0, -2.675, 3.14159,
+			},
+			decimals: 2,
+			expected: []float64{
+				1.23, 0, 0,
+				0, -2.68, 3.14,
+			},
+			nnz:  4,
+			nnzE: 3,
+		},
+		{
+			r: 2, c: 3,
+			data: []float64{
+				123.4, 0, 4,
+				0, -15, 96,
+			},
+			decimals: -1,
+			expected: []float64{
+				120, 0, 0,
+				0, -20, 100,
+			},
+			nnz:  4,
+			nnzE: 3,
+		},
+		{
+			// values are exact at a precision beyond the range of float64
+			r: 2, c: 3,
+			data: []float64{
+				1.2345, 0, 1e10,
+				0, -2.675, 3.14159,
+			},
+			decimals: 400,
+			expected: []float64{
+				1.2345, 0, 1e10,
+				0, -2.675, 3.14159,
+			},
+			nnz:  4,
+			nnzE: 4,
+		},
+		{
+			r: 2, c: 3,
+			data: []float64{
+				1.2345, 0, 1e10,
+				0, -2.675, 3.14159,
+			},
+			decimals: 300,
+			expected: []float64{
+				1.2345, 0, 1e10,
+				0, -2.675, 3.14159,
+			},
+			nnz:  4,
+			nnzE: 4,
+		},
+		{
+			r: 2, c: 3,
+			data: []float64{
+				123.4, 0, 4,
+				0, -15, 96,
+			},
+			decimals: -400,
+			expected: []float64{
+				0, 0, 0,
+				0, 0, 0,
+			},
+			nnz:  4,
+			nnzE: 0,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		csr := CreateCSR(test.r, test.c, test.data).(*CSR)
+
+		csr.Round(test.decimals)
+
+		if !mat.EqualApprox(csr, expected, 1e-12) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csr))
+			t.Fail()
+		}
+		if csr.NNZ() != test.nnz {
+			t.Logf("Expected NNZ of %d after rounding but received %d", test.nnz, csr.NNZ())
+			t.Fail()
+		}
+
+		pruned := CreateCSR(test.r, test.c, test.data).(*CSR)
+		pruned.RoundPruned(test.decimals)
+
+		if !mat.EqualApprox(pruned, expected, 1e-12) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(pruned))
+			t.Fail()
+		}
+		if pruned.NNZ() != test.nnzE {
+			t.Logf("Expected NNZ of %d after pruning but received %d", test.nnzE, pruned.NNZ())
+			t.Fail()
+		}
+	}
+}
+
+func TestCSRCSCPrune(t *testing.T) {
+	var tests = []struct {
+		r, c     int
+		ia       []int
+		ja       []int
+		data     []float64
+		tol      float64
+		nnzE     int
+		expected []float64
+	}{
+		{
+			r: 3, c: 4,
+			ia:   []int{0, 2, 3, 6},
+			ja:   []int{0, 3, 1, 0, 2, 3},
+			data: []float64{1, 0, 2, 0, 3, 0},
+			tol:  0,
+			nnzE: 3,
+			expected: []float64{
+				1, 0, 0, 0,
+				0, 2, 0, 0,
+				0, 0, 3, 0,
+			},
+		},
+		{
+			r: 3, c: 4,
+			ia:   []int{0, 2, 3, 6},
+			ja:   []int{0, 3, 1, 0, 2, 3},
+			data: []float64{1, 0, -2, 0, 3, 2.5},
+			tol:  2.5,
+			nnzE: 1,
+			expected: []float64{
+				0, 0, 0, 0,
+				0, 0, 0, 0,
+				0, 0, 3, 0,
+			},
+		},
+		{
+			r: 2, c: 2,
+			ia:   []int{0, 1, 2},
+			ja:   []int{0, 1},
+			data: []float64{0, 0},
+			tol:  0,
+			nnzE: 0,
+			expected: []float64{
+				0, 0,
+				0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		csr := NewCSR(test.r, test.c, test.ia, test.ja, test.data)
+		csc := csr.ToCSC()
+
+		csr.Prune(test.tol)
+		csc.Prune(test.tol)
+
+		if !mat.Equal(csr, expected) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csr))
+			t.Fail()
+		}
+		if !mat.Equal(csc, expected) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csc))
+			t.Fail()
+		}
+		if csr.NNZ() != test.nnzE || len(csr.matrix.Data) != test.nnzE || len(csr.matrix.Ind) != test.nnzE {
+			t.Logf("Expected CSR NNZ %d but received %d (data length %d)\n", test.nnzE, csr.NNZ(), len(csr.matrix.Data))
+			t.Fail()
+		}
+		if csc.NNZ() != test.nnzE || len(csc.matrix.Data) != test.nnzE || len(csc.matrix.Ind) != test.nnzE {
+			t.Logf("Expected CSC NNZ %d but received %d (data length %d)\n", test.nnzE, csc.NNZ(), len(csc.matrix.Data))
+			t.Fail()
+		}
+		if errs := Validate(csr); len(errs) != 0 {
+			t.Logf("Pruned CSR is invalid: %v\n", errs)
+			t.Fail()
+		}
+	}
+}
+
+func TestCSRCSCDiagonal(t *testing.T) {
+	var tests = []struct {
+		r, c     int
+		data     []float64
+		diag     []float64
+		set      []float64
+		expected []float64
+	}{
+		{
+			r: 3, c: 3,
+			data: []float64{
+				1, 2, 0,
+				0, 0, 3,
+				4, 0, 5,
+			},
+			diag: []float64{1, 0, 5},
+			set:  []float64{6, 7, 0},
+			expected: []float64{
+				6, 2, 0,
+				0, 7, 3,
+				4, 0, 0,
+			},
+		},
+		{
+			r: 2, c: 4,
+			data: []float64{
+				0, 1, 0, 2,
+				3, 0, 0, 4,
+			},
+			diag: []float64{0, 0},
+			set:  []float64{5, 6},
+			expected: []float64{
+				5, 1, 0, 2,
+				3, 6, 0, 4,
+			},
+		},
+		{
+			r: 4, c: 2,
+			data: []float64{
+				1, 0,
+				0, 0,
+				0, 2,
+				3, 0,
+			},
+			diag: []float64{1, 0},
+			set:  []float64{0, 0},
+			expected: []float64{
+				0, 0,
+				0, 0,
+				0, 2,
+				3, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		d := mat.NewDense(test.r, test.c, test.data)
+		csr := NewCSR(test.r, test.c, nil, nil, nil)
+		csr.Clone(d)
+		csc := csr.ToCSC()
+
+		for _, m := range []interface {
+			mat.Matrix
+			Diagonal() []float64
+			SetDiagonal([]float64)
+		}{csr, csc} {
+			if diag := m.Diagonal(); !floats.Equal(test.diag, diag) {
+				t.Logf("%T: Expected diagonal %v but received %v\n", m, test.diag, diag)
+				t.Fail()
+			}
+			m.SetDiagonal(test.set)
+			if !mat.Equal(expected, m) {
+				t.Logf("%T: Expected:\n%v\n but received:\n%v\n", m, mat.Formatted(expected), mat.Formatted(m))
+				t.Fail()
+			}
+		}
+		if errs := Validate(csr); len(errs) != 0 {
+			t.Logf("CSR invalid after SetDiagonal: %v\n", errs)
+			t.Fail()
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != mat.ErrShape {
+			t.Errorf("Expected panic %v for incorrect diagonal length but received %v", mat.ErrShape, r)
+		}
+	}()
+	CreateCSR(3, 2, nil).(*CSR).SetDiagonal([]float64{1, 2, 3})
+}
+
+func TestCSRCSCNegativeIndices(t *testing.T) {
+	csr := CreateCSR(2, 3, []float64{1, 0, 2, 0, 3, 0}).(*CSR)
+	csc := CreateCSC(2, 3, []float64{1, 0, 2, 0, 3, 0}).(*CSC)
+
+	var tests = []struct {
+		fn       func()
+		expected error
+	}{
+		{fn: func() { csr.RowNNZ(-1) }, expected: mat.ErrRowAccess},
+		{fn: func() { csr.RowNNZ(2) }, expected: mat.ErrRowAccess},
+		{fn: func() { csc.ColNNZ(-1) }, expected: mat.ErrColAccess},
+		{fn: func() { csc.ColNNZ(3) }, expected: mat.ErrColAccess},
+		{fn: func() { NewCSR(-1, 3, nil, nil, nil) }, expected: mat.ErrRowAccess},
+		{fn: func() { NewCSR(2, -1, nil, nil, nil) }, expected: mat.ErrColAccess},
+		{fn: func() { NewCSC(-1, 3, nil, nil, nil) }, expected: mat.ErrRowAccess},
+		{fn: func() { NewCSC(2, -1, nil, nil, nil) }, expected: mat.ErrColAccess},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		func() {
+			defer func() {
+				if r := recover(); r != test.expected {
+					t.Logf("Expected panic %v but received %v\n", test.expected, r)
+					t.Fail()
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
+
+func TestNewCSRCSCChecked(t *testing.T) {
+	var tests = []struct {
+		r, c   int
+		indptr []int
+		ind    []int
+		data   []float64
+		valid  bool
+	}{
+		{r: 3, c: 4, indptr: []int{0, 2, 2, 5}, ind: []int{0, 2, 3, 1, 2}, data: []float64{1, 2, 3, 4, 5}, valid: true},
+		{r: 2, c: 2, indptr: []int{0, 0, 0}, ind: []int{}, data: []float64{}, valid: true},
+		{r: -1, c: 2, indptr: []int{0}, valid: false},
+		{r: 3, c: 4, indptr: []int{0, 2, 5}, ind: []int{0, 2, 3, 1, 2}, data: []float64{1, 2, 3, 4, 5}, valid: false},
+		{r: 3, c: 4, indptr: []int{0, 2, 2, 5}, ind: []int{0, 2, 3, 1}, data: []float64{1, 2, 3, 4, 5}, valid: false},
+		{r: 3, c: 4, indptr: []int{1, 2, 2, 5}, ind: []int{0, 2, 3, 1, 2}, data: []float64{1, 2, 3, 4, 5}, valid: false},
+		{r: 3, c: 4, indptr: []int{0, 2, 2, 4}, ind: []int{0, 2, 3, 1, 2}, data: []float64{1, 2, 3, 4, 5}, valid: false},
+		{r: 3, c: 4, indptr: []int{0, 3, 2, 5}, ind: []int{0, 2, 3, 1, 2}, data: []float64{1, 2, 3, 4, 5}, valid: false},
+		{r: 3, c: 4, indptr: []int{0, 2, 2, 5}, ind: []int{0, 2, 4, 1, 2}, data: []float64{1, 2, 3, 4, 5}, valid: false},
+		{r: 3, c: 4, indptr: []int{0, 2, 2, 5}, ind: []int{0, -1, 3, 1, 2}, data: []float64{1, 2, 3, 4, 5}, valid: false},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		csr, err := NewCSRChecked(test.r, test.c, test.indptr, test.ind, test.data)
+		if test.valid != (err == nil) || test.valid != (csr != nil) {
+			t.Logf("CSR: Expected valid %t but received error: %v\n", test.valid, err)
+			t.Fail()
+		}
+		if test.valid && !mat.Equal(csr, NewCSR(test.r, test.c, test.indptr, test.ind, test.data)) {
+			t.Logf("CSR: Expected checked matrix to equal unchecked matrix\n")
+			t.Fail()
+		}
+
+		// the same slices describe the transposed matrix in CSC format
+		csc, err := NewCSCChecked(test.c, test.r, test.indptr, test.ind, test.data)
+		if test.valid != (err == nil) || test.valid != (csc != nil) {
+			t.Logf("CSC: Expected valid %t but received error: %v\n", test.valid, err)
+			t.Fail()
+		}
+		if test.valid && !mat.Equal(csc, csr.T()) {
+			t.Logf("CSC: Expected checked matrix to equal transpose of CSR\n")
+			t.Fail()
+		}
+	}
+}
+
+func TestCSRCSCSortIndices(t *testing.T) {
+	var tests = []struct {
+		r, c   int
+		indptr []int
+		ind    []int
+		data   []float64
+		sorted bool
+		eInd   []int
+		eData  []float64
+	}{
+		{
+			r: 3, c: 4,
+			indptr: []int{0, 2, 2, 5},
+			ind:    []int{0, 2, 1, 2, 3},
+			data:   []float64{1, 2, 3, 4, 5},
+			sorted: true,
+			eInd:   []int{0, 2, 1, 2, 3},
+			eData:  []float64{1, 2, 3, 4, 5},
+		},
+		{
+			r: 3, c: 4,
+			indptr: []int{0, 2, 2, 5},
+			ind:    []int{2, 0, 3, 1, 2},
+			data:   []float64{2, 1, 5, 3, 4},
+			sorted: false,
+			eInd:   []int{0, 2, 1, 2, 3},
+			eData:  []float64{1, 2, 3, 4, 5},
+		},
+		{
+			r: 2, c: 3,
+			indptr: []int{0, 3, 4},
+			ind:    []int{2, 1, 2, 0},
+			data:   []float64{6, 7, 8, 9},
+			sorted: false,
+			eInd:   []int{1, 2, 2, 0},
+			eData:  []float64{7, 6, 8, 9},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		for _, m := range []interface {
+			mat.Matrix
+			SortIndices()
+			HasSortedIndices() bool
+			RawMatrix() *blas.SparseMatrix
+		}{
+			NewCSR(test.r, test.c, append([]int(nil), test.indptr...), append([]int(nil), test.ind...), append([]float64(nil), test.data...)),
+			NewCSC(test.c, test.r, append([]int(nil), test.indptr...), append([]int(nil), test.ind...), append([]float64(nil), test.data...)),
+		} {
+			expected := mat.DenseCopyOf(m)
+
+			if sorted := m.HasSortedIndices(); sorted != test.sorted {
+				t.Logf("%T: Expected HasSortedIndices() %t but received %t\n", m, test.sorted, sorted)
+				t.Fail()
+			}
+
+			m.SortIndices()
+
+			if !m.HasSortedIndices() {
+				t.Logf("%T: Expected HasSortedIndices() true after SortIndices\n", m)
+				t.Fail()
+			}
+			raw := m.RawMatrix()
+			if !reflect.DeepEqual(raw.Ind, test.eInd) || !floats.Equal(raw.Data, test.eData) {
+				t.Logf("%T: Expected ind %v, data %v but received ind %v, data %v\n", m, test.eInd, test.eData, raw.Ind, raw.Data)
+				t.Fail()
+			}
+			if !mat.Equal(expected, m) {
+				t.Logf("%T: Expected:\n%v\n but received:\n%v\n", m, mat.Formatted(expected), mat.Formatted(m))
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestCSRCSCSortedAt(t *testing.T) {
+	data := []float64{
+		1, 0, 2, 0,
+		0, 0, 0, 0,
+		0, 3, 4, 5,
+	}
+	expected := mat.NewDense(3, 4, data)
+
+	// unsorted column indices
+	csr := NewCSR(3, 4, []int{0, 2, 2, 5}, []int{2, 0, 3, 1, 2}, []float64{2, 1, 5, 3, 4})
+	csc := NewCSC(3, 4, []int{0, 1, 2, 4, 5}, []int{0, 2, 2, 0, 2}, []float64{1, 3, 4, 2, 5})
+
+	if csr.sorted || csc.sorted {
+		t.Errorf("Expected new matrices not to be marked sorted")
+	}
+	if csr.HasSortedIndices() || csc.HasSortedIndices() {
+		t.Errorf("Expected unsorted indices")
+	}
+
+	csr.SortIndices()
+	csc.SortIndices()
+	if !csr.sorted || !csc.sorted {
+		t.Errorf("Expected matrices to be marked sorted after SortIndices")
+	}
+	if !mat.Equal(expected, csr) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csr))
+	}
+	if !mat.Equal(expected, csc) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csc))
+	}
+
+	// updating an existing element or appending beyond the last element of a row
+	// keeps indices sorted
+	csr.Set(0, 2, 6)
+	csr.Set(2, 0, 0)
+	csr.Set(0, 3, 7)
+	expected.Set(0, 2, 6)
+	expected.Set(0, 3, 7)
+	if !csr.sorted || !csr.HasSortedIndices() {
+		t.Errorf("Expected matrix to remain marked sorted after Set")
+	}
+	if !mat.Equal(expected, csr) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csr))
+	}
+
+	// inserting before the last element of a row may unsort indices
+	csr.Set(2, 0, 8)
+	expected.Set(2, 0, 8)
+	if csr.sorted {
+		t.Errorf("Expected matrix not to be marked sorted after unsorted insert")
+	}
+	if !mat.Equal(expected, csr) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csr))
+	}
+
+	// operations writing into the receiver clear the sorted flag
+	csc.Set(0, 1, 9)
+	if csc.sorted {
+		t.Errorf("Expected CSC not to be marked sorted after unsorted insert")
+	}
+	csr.SortIndices()
+	csr.Mul(NewCSR(3, 2, []int{0, 1, 1, 2}, []int{1, 0}, []float64{1, 1}), NewCSR(2, 4, []int{0, 1, 2}, []int{3, 0}, []float64{1, 1}))
+	if csr.sorted {
+		t.Errorf("Expected matrix not to be marked sorted after Mul")
+	}
+}
+
+func TestCSRCSCResetZero(t *testing.T) {
+	data := []float64{
+		1, 0, 2,
+		0, 0, 3,
+	}
+	var tests = []struct {
+		desc   string
+		create MatrixCreator
+	}{
+		{"CSR", CreateCSR},
+		{"CSC", CreateCSC},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		// Zero retains structure
+		m := test.create(2, 3, data).(interface {
+			Sparser
+			RawMatrix() *blas.SparseMatrix
+			Zero()
+			Reset()
+			IsZero() bool
+		})
+		m.Zero()
+		if r, c := m.Dims(); r != 2 || c != 3 {
+			t.Logf("Expected dimensions to be retained after Zero but received %dx%d\n", r, c)
+			t.Fail()
+		}
+		if m.NNZ() != 3 {
+			t.Logf("Expected 3 stored elements after Zero but received %d\n", m.NNZ())
+			t.Fail()
+		}
+		if !mat.Equal(m, mat.NewDense(2, 3, nil)) {
+			t.Logf("Expected all elements to be zero but received:\n%v\n", mat.Formatted(m))
+			t.Fail()
+		}
+
+		// Reset discards structure but retains capacity
+		raw := m.RawMatrix()
+		indCap, dataCap := cap(raw.Ind), cap(raw.Data)
+		m.Reset()
+		if !m.IsZero() {
+			t.Logf("Expected matrix to be zero-sized after Reset\n")
+			t.Fail()
+		}
+		raw = m.RawMatrix()
+		if len(raw.Indptr) != 0 || len(raw.Ind) != 0 || len(raw.Data) != 0 {
+			t.Logf("Expected storage to be truncated after Reset but received %v\n", raw)
+			t.Fail()
+		}
+		if cap(raw.Ind) != indCap || cap(raw.Data) != dataCap {
+			t.Logf("Expected storage capacity to be retained after Reset\n")
+			t.Fail()
+		}
+	}
+
+	// Reset matrix is reusable as a receiver
+	csr := CreateCSR(2, 3, data).(*CSR)
+	csr.Reset()
+	csr.Clone(CreateCSR(3, 2, []float64{1, 0, 0, 2, 3, 0}))
+	if r, c := csr.Dims(); r != 3 || c != 2 || csr.At(2, 0) != 3 {
+		t.Errorf("Expected Reset matrix to be reused as 3x2 matrix but received %dx%d", r, c)
+	}
+}
+
+func TestCSRGrow(t *testing.T) {
+	csr := CreateCSR(2, 3, []float64{
+		1, 0, 2,
+		0, 0, 3,
+	}).(*CSR)
+
+	csr.Grow(100)
+	if cap(csr.matrix.Ind) < 100 || cap(csr.matrix.Data) < 100 {
+		t.Errorf("Expected capacity of at least 100 but received %d and %d", cap(csr.matrix.Ind), cap(csr.matrix.Data))
+	}
+	if !mat.Equal(csr, mat.NewDense(2, 3, []float64{1, 0, 2, 0, 0, 3})) {
+		t.Errorf("Expected stored elements to be retained by Grow but received:\n%v", mat.Formatted(csr))
+	}
+
+	// growing to a smaller capacity has no effect
+	ind := csr.matrix.Ind
+	csr.Grow(10)
+	if !aliasInts(ind, csr.matrix.Ind) {
+		t.Errorf("Expected storage to be retained when growing to a smaller capacity")
+	}
+
+	// preallocated storage is used by subsequent operations
+	var c CSR
+	c.Grow(100)
+	ind, data := c.matrix.Ind, c.matrix.Data
+	a := CreateCSR(3, 3, []float64{1, 0, 2, 0, 3, 0, 4, 0, 5}).(*CSR)
+	c.Mul(a, a)
+	if !aliasInts(ind, c.matrix.Ind) || !aliasFloats(data, c.matrix.Data) {
+		t.Errorf("Expected Mul to use preallocated storage")
+	}
+	var expected mat.Dense
+	expected.Mul(a, a)
+	if !mat.Equal(&expected, &c) {
+		t.Errorf("Expected:\n%v\n but received:\n%v", mat.Formatted(&expected), mat.Formatted(&c))
+	}
+
+	// storage is retained when reset and reused
+	c.Reset()
+	c.Add(a, a)
+	if !aliasInts(ind, c.matrix.Ind) || !aliasFloats(data, c.matrix.Data) {
+		t.Errorf("Expected Add to reuse storage following Reset")
+	}
+}
+
+func TestCSRCSCMinorNNZs(t *testing.T) {
+	data := []float64{
+		1, 0, 2, 0,
+		0, 0, 3, 0,
+		4, 0, 5, 6,
+	}
+	csr := CreateCSR(3, 4, data).(*CSR)
+	csc := CreateCSC(3, 4, data).(*CSC)
+
+	colNNZs := []int{2, 0, 3, 1}
+	rowNNZs := []int{2, 1, 3}
+
+	if result := csr.ColNNZs(); !reflect.DeepEqual(result, colNNZs) {
+		t.Errorf("Expected column NNZs %v but received %v", colNNZs, result)
+	}
+	if result := csc.RowNNZs(); !reflect.DeepEqual(result, rowNNZs) {
+		t.Errorf("Expected row NNZs %v but received %v", rowNNZs, result)
+	}
+
+	// destination slices are reused and returned, overwriting existing values
+	colDst := []int{9, 9, 9, 9}
+	if result := csr.ColNNZsTo(colDst); &result[0] != &colDst[0] || !reflect.DeepEqual(colDst, colNNZs) {
+		t.Errorf("Expected column NNZs %v in supplied slice but received %v", colNNZs, result)
+	}
+	rowDst := []int{9, 9, 9}
+	if result := csc.RowNNZsTo(rowDst); &result[0] != &rowDst[0] || !reflect.DeepEqual(rowDst, rowNNZs) {
+		t.Errorf("Expected row NNZs %v in supplied slice but received %v", rowNNZs, result)
+	}
+
+	defer func() {
+		if r := recover(); r != mat.ErrShape {
+			t.Errorf("Expected panic with mat.ErrShape for wrong length destination but received %v", r)
+		}
+	}()
+	csr.ColNNZsTo(make([]int, 3))
+}
+
+func TestIdentityDiag(t *testing.T) {
+	for ti, n := range []int{0, 1, 4} {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		for _, m := range []*CSR{Identity(n), Eye(n)} {
+			if r, c := m.Dims(); r != n || c != n {
+				t.Errorf("Expected %dx%d identity but received %dx%d", n, n, r, c)
+				continue
+			}
+			if n == 0 {
+				continue
+			}
+			expected := mat.NewDense(n, n, nil)
+			for i := 0; i < n; i++ {
+				expected.Set(i, i, 1)
+			}
+			if !mat.Equal(expected, m) {
+				t.Errorf("Expected:\n%v\n but received:\n%v", mat.Formatted(expected), mat.Formatted(m))
+			}
+			if m.NNZ() != n || cap(m.matrix.Ind) != n || cap(m.matrix.Data) != n {
+				t.Errorf("Expected exactly %d stored elements but received %d", n, m.NNZ())
+			}
+		}
+	}
+
+	values := []float64{3, 0, -1, 2}
+	d := Diag(values)
+	if !mat.Equal(mat.NewDiagDense(4, values), d) {
+		t.Errorf("Expected:\n%v\n but received:\n%v", mat.Formatted(mat.NewDiagDense(4, values)), mat.Formatted(d))
+	}
+	if d.NNZ() != 3 || cap(d.matrix.Data) != 3 {
+		t.Errorf("Expected exactly 3 stored elements but received %d", d.NNZ())
+	}
+	values[0] = 9
+	if d.At(0, 0) != 3 {
+		t.Errorf("Expected Diag not to share storage with values")
+	}
+}
+
+func TestDiags(t *testing.T) {
+	var tests = []struct {
+		data     [][]float64
+		offsets  []int
+		r, c     int
+		expected *mat.Dense
+	}{
+		{ // tridiagonal
+			data:    [][]float64{{1, 2}, {3, 4, 5}, {6, 7}},
+			offsets: []int{-1, 0, 1},
+			r:       3, c: 3,
+			expected: mat.NewDense(3, 3, []float64{
+				3, 6, 0,
+				1, 4, 7,
+				0, 2, 5,
+			}),
+		},
+		{ // offsets out of order with zero values
+			data:    [][]float64{{1, 0}, {2, 3, 4}},
+			offsets: []int{2, 0},
+			r:       3, c: 4,
+			expected: mat.NewDense(3, 4, []float64{
+				2, 0, 1, 0,
+				0, 3, 0, 0,
+				0, 0, 4, 0,
+			}),
+		},
+		{ // tall matrix
+			data:    [][]float64{{1, 2}, {3, 4}},
+			offsets: []int{0, -2},
+			r:       4, c: 2,
+			expected: mat.NewDense(4, 2, []float64{
+				1, 0,
+				0, 2,
+				3, 0,
+				0, 4,
+			}),
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		m := Diags(test.data, test.offsets, test.r, test.c)
+		if !mat.Equal(test.expected, m) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(test.expected), mat.Formatted(m))
+			t.Fail()
+		}
+		nnz := 0
+		test.expected.Apply(func(i, j int, v float64) float64 {
+			if v != 0 {
+				nnz++
+			}
+			return v
+		}, test.expected)
+		if m.NNZ() != nnz {
+			t.Logf("Expected %d stored elements but received %d\n", nnz, m.NNZ())
+			t.Fail()
+		}
+		for i := 0; i < test.r; i++ {
+			if ind, _ := m.RawRow(i); !sort.IntsAreSorted(ind) {
+				t.Logf("Expected sorted column indices for row %d\n", i)
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestFailDiags(t *testing.T) {
+	var tests = []struct {
+		data    [][]float64
+		offsets []int
+		r, c    int
+	}{
+		{data: [][]float64{{1, 2, 3}}, offsets: []int{0, 1}, r: 3, c: 3},
+		{data: [][]float64{{1, 2}}, offsets: []int{0}, r: 3, c: 3},
+		{data: [][]float64{{1, 2}}, offsets: []int{-2}, r: 3, c: 3},
+		{data: [][]float64{{}}, offsets: []int{3}, r: 3, c: 3},
+		{data: [][]float64{{1, 2}, {3, 4}}, offsets: []int{1, 1}, r: 3, c: 3},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		func() {
+			defer func() {
+				if r := recover(); r != mat.ErrShape {
+					t.Logf("Expected panic with %v but received %v\n", mat.ErrShape, r)
+					t.Fail()
+				}
+			}()
+			Diags(test.data, test.offsets, test.r, test.c)
+		}()
+	}
+}
+
+func TestCSRDensity(t *testing.T) {
+	var tests = []struct {
+		m        *CSR
+		expected float64
+	}{
+		{m: CreateCSR(2, 4, []float64{1, 0, 0, 2, 0, 0, 0, 3}).(*CSR), expected: 0.375},
+		{m: CreateCSR(2, 2, []float64{1, 2, 3, 4}).(*CSR), expected: 1},
+		{m: CreateCSR(3, 3, nil).(*CSR), expected: 0},
+		// explicitly stored zeros are counted
+		{m: NewCSR(1, 4, []int{0, 2}, []int{0, 1}, []float64{0, 1}), expected: 0.5},
+		{m: NewCSR(0, 5, []int{0}, nil, nil), expected: 0},
+		{m: NewCSR(5, 0, make([]int, 6), nil, nil), expected: 0},
+		{m: &CSR{}, expected: 0},
+		// duplicate elements
+		{m: NewCSR(1, 1, []int{0, 2}, []int{0, 0}, []float64{1, 1}), expected: 1},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		if d := test.m.Density(); d != test.expected {
+			t.Logf("Expected density %v but received %v\n", test.expected, d)
+			t.Fail()
+		}
+		if s := test.m.Sparsity(); s != 1-test.expected {
+			t.Logf("Expected sparsity %v but received %v\n", 1-test.expected, s)
+			t.Fail()
+		}
+	}
+}
+
+func TestNewCSRFromDense(t *testing.T) {
+	data := []float64{
+		1, 1e-12, 0, -2,
+		-1e-14, 0, 3, 0,
+		0, 0.5, 0, -1e-9,
+	}
+	var tests = []struct {
+		tol      float64
+		expected []float64
+	}{
+		{
+			tol:      0,
+			expected: data,
+		},
+		{
+			tol: 1e-10,
+			expected: []float64{
+				1, 0, 0, -2,
+				0, 0, 3, 0,
+				0, 0.5, 0, -1e-9,
+			},
+		},
+		{
+			tol: 1e-9,
+			expected: []float64{
+				1, 0, 0, -2,
+				0, 0, 3, 0,
+				0, 0.5, 0, 0,
+			},
+		},
+		{
+			tol: 1,
+			expected: []float64{
+				0, 0, 0, -2,
+				0, 0, 3, 0,
+				0, 0, 0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		dense := mat.NewDense(3, 4, data)
+		expected := mat.NewDense(3, 4, test.expected)
+		nnz := CreateCSR(3, 4, test.expected).(*CSR).NNZ()
+
+		// a row view of a larger matrix has a stride greater than the number of columns
+		wide := mat.NewDense(3, 6, nil)
+		wide.Slice(0, 3, 1, 5).(*mat.Dense).Copy(dense)
+
+		for _, d := range []mat.Matrix{dense, wide.Slice(0, 3, 1, 5), dense.T().T()} {
+			csr := NewCSRFromDense(d, test.tol)
+			if !mat.Equal(expected, csr) {
+				t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csr))
+				t.Fail()
+			}
+			if csr.NNZ() != nnz {
+				t.Logf("Expected %d stored elements but received %d\n", nnz, csr.NNZ())
+				t.Fail()
+			}
+			if !hasSortedIndices(&csr.matrix) || !csr.sorted {
+				t.Logf("Expected sorted column indices\n")
+				t.Fail()
+			}
+		}
+	}
+
+	var clone CSR
+	clone.Clone(mat.NewDense(3, 4, data))
+	if csr := NewCSRFromDense(mat.NewDense(3, 4, data), 0); !mat.Equal(&clone, csr) || csr.NNZ() != clone.NNZ() {
+		t.Errorf("Expected tol of 0 to match Clone")
+	}
+}
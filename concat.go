@@ -0,0 +1,158 @@
+package sparse
+
+import (
+	"github.com/james-bowman/sparse/blas"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Concat concatenates the specified matrices along the specified axis returning the
+// result as a new sparse matrix.  Axis 0 concatenates the matrices vertically (stacking
+// the rows of each matrix below those of the previous matrix) and axis 1 concatenates
+// horizontally (placing the columns of each matrix to the right of those of the
+// previous matrix).  The format of the returned matrix is chosen so that the
+// concatenation is simply an append of the compressed storage of each matrix: vertical
+// concatenation returns a *CSR matrix (row major) and horizontal concatenation returns
+// a *CSC matrix (column major).  Operands not already in the chosen format are
+// converted before concatenation.  The returned matrix will not share underlying
+// storage with any of the operands.  Concat will panic if no matrices are specified,
+// if axis is not 0 or 1 or if the dimensions of the matrices along the other axis do
+// not match.
+func Concat(axis int, matrices ...mat.Matrix) mat.Matrix {
+	if len(matrices) == 0 {
+		panic(mat.ErrZeroLength)
+	}
+
+	raw := make([]*blas.SparseMatrix, len(matrices))
+	switch axis {
+	case 0:
+		for i, m := range matrices {
+			raw[i] = csrOf(m).RawMatrix()
+		}
+		r := concatRaw(raw)
+		return NewCSR(r.I, r.J, r.Indptr, r.Ind, r.Data)
+	case 1:
+		for i, m := range matrices {
+			raw[i] = cscOf(m).RawMatrix()
+		}
+		r := concatRaw(raw)
+		return NewCSC(r.J, r.I, r.Indptr, r.Ind, r.Data)
+	default:
+		panic(mat.ErrIndexOutOfRange)
+	}
+}
+
+// VStack stacks the specified matrices vertically, placing the rows of each matrix below
+// those of the previous matrix, and returns the result as a new CSR matrix.  As CSR is
+// row major, this is a cheap append of the row pointers, column indices and values of
+// each matrix.  Operands not already in CSR format (including dense matrices) are
+// converted before stacking and zero value CSR matrices are treated as empty and
+// skipped.  The returned matrix will not share underlying storage with any of the
+// operands.  VStack will panic with mat.ErrZeroLength if no matrices are
+// specified and with mat.ErrShape if the matrices do not all have the same number of
+// columns.
+func VStack(matrices ...mat.Matrix) *CSR {
+	return Concat(0, matrices...).(*CSR)
+}
+
+// HStack stacks the specified matrices horizontally, placing the columns of each matrix
+// to the right of those of the previous matrix, and returns the result as a new CSR
+// matrix.  Unlike VStack, the elements of each row of the result are drawn from every
+// operand with the column indices of each operand offset by the total number of columns
+// of the preceding operands.  Operands not already in CSR format (including dense
+// matrices) are converted before stacking.  If a CSC result is acceptable, Concat with an
+// axis of 1 is cheaper.  The returned matrix will not share underlying storage with any
+// of the operands.  HStack will panic with mat.ErrZeroLength if no matrices are specified
+// and with mat.ErrShape if the matrices do not all have the same number of rows.
+func HStack(matrices ...mat.Matrix) *CSR {
+	if len(matrices) == 0 {
+		panic(mat.ErrZeroLength)
+	}
+
+	rows, _ := matrices[0].Dims()
+	raw := make([]*blas.SparseMatrix, len(matrices))
+	var cols, nnz int
+	for i, m := range matrices {
+		if r, _ := m.Dims(); r != rows {
+			panic(mat.ErrShape)
+		}
+		raw[i] = csrOf(m).RawMatrix()
+		cols += raw[i].J
+		if rows > 0 {
+			nnz += raw[i].Indptr[rows] - raw[i].Indptr[0]
+		}
+	}
+
+	indptr := make([]int, rows+1)
+	ind := make([]int, 0, nnz)
+	data := make([]float64, 0, nnz)
+	for i := 0; i < rows; i++ {
+		offset := 0
+		for _, m := range raw {
+			for k := m.Indptr[i]; k < m.Indptr[i+1]; k++ {
+				ind = append(ind, m.Ind[k]+offset)
+				data = append(data, m.Data[k])
+			}
+			offset += m.J
+		}
+		indptr[i+1] = len(ind)
+	}
+
+	return NewCSR(rows, cols, indptr, ind, data)
+}
+
+// concatRaw concatenates the compressed major axis (rows for CSR and columns for CSC)
+// of the specified sparse matrices returning the result.  Zero value matrices, having no
+// index pointers, are empty and are skipped.  concatRaw will panic if the minor axis
+// dimensions of the remaining matrices do not match.
+func concatRaw(matrices []*blas.SparseMatrix) blas.SparseMatrix {
+	var major, minor, nnz int
+	first := true
+	for _, m := range matrices {
+		if len(m.Indptr) == 0 {
+			continue
+		}
+		if first {
+			minor, first = m.J, false
+		}
+		if m.J != minor {
+			panic(mat.ErrShape)
+		}
+		major += m.I
+		nnz += m.Indptr[m.I] - m.Indptr[0]
+	}
+
+	result := blas.SparseMatrix{
+		I:      major,
+		J:      minor,
+		Indptr: make([]int, 1, major+1),
+		Ind:    make([]int, 0, nnz),
+		Data:   make([]float64, 0, nnz),
+	}
+
+	for _, m := range matrices {
+		if len(m.Indptr) == 0 {
+			continue
+		}
+		offset := len(result.Ind) - m.Indptr[0]
+		for _, p := range m.Indptr[1 : m.I+1] {
+			result.Indptr = append(result.Indptr, p+offset)
+		}
+		result.Ind = append(result.Ind, m.Ind[m.Indptr[0]:m.Indptr[m.I]]...)
+		result.Data = append(result.Data, m.Data[m.Indptr[0]:m.Indptr[m.I]]...)
+	}
+
+	return result
+}
+
+// cscOf returns a CSC format representation of the matrix m.  If m is already a CSC
+// matrix it is returned directly, otherwise m is converted to CSC format either using
+// its ToCSC method (if m is a TypeConverter) or by scanning for non-zero elements.
+func cscOf(m mat.Matrix) *CSC {
+	switch t := m.(type) {
+	case *CSC:
+		return t
+	case TypeConverter:
+		return t.ToCSC()
+	}
+	return csrOf(m).ToCSC()
+}
@@ -0,0 +1,179 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestConcat(t *testing.T) {
+	var tests = []struct {
+		axis     int
+		matrices []mat.Matrix
+		r, c     int
+		expected []float64
+	}{
+		{
+			axis: 0,
+			matrices: []mat.Matrix{
+				CreateCSR(2, 3, []float64{
+					1, 0, 2,
+					0, 0, 0,
+				}),
+				CreateCSC(1, 3, []float64{
+					0, 3, 0,
+				}),
+				mat.NewDense(2, 3, []float64{
+					4, 0, 0,
+					0, 5, 6,
+				}),
+			},
+			r: 5, c: 3,
+			expected: []float64{
+				1, 0, 2,
+				0, 0, 0,
+				0, 3, 0,
+				4, 0, 0,
+				0, 5, 6,
+			},
+		},
+		{
+			axis: 1,
+			matrices: []mat.Matrix{
+				CreateCSC(2, 2, []float64{
+					1, 0,
+					0, 2,
+				}),
+				CreateCOO(2, 1, []float64{
+					0,
+					3,
+				}),
+				CreateCSR(2, 3, []float64{
+					4, 0, 5,
+					0, 0, 6,
+				}),
+			},
+			r: 2, c: 6,
+			expected: []float64{
+				1, 0, 0, 4, 0, 5,
+				0, 2, 3, 0, 0, 6,
+			},
+		},
+		{
+			axis: 0,
+			matrices: []mat.Matrix{
+				CreateCSR(1, 2, []float64{1, 2}),
+			},
+			r: 1, c: 2,
+			expected: []float64{1, 2},
+		},
+		{
+			axis: 0,
+			matrices: []mat.Matrix{
+				CreateCSR(1, 2, []float64{1, 2}),
+				&CSR{},
+				CreateCSR(1, 2, []float64{3, 4}),
+			},
+			r: 2, c: 2,
+			expected: []float64{
+				1, 2,
+				3, 4,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		result := Concat(test.axis, test.matrices...)
+
+		switch test.axis {
+		case 0:
+			if _, ok := result.(*CSR); !ok {
+				t.Errorf("Expected *CSR for vertical concatenation but received %T", result)
+			}
+		case 1:
+			if _, ok := result.(*CSC); !ok {
+				t.Errorf("Expected *CSC for horizontal concatenation but received %T", result)
+			}
+		}
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		if !mat.Equal(expected, result) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+			t.Fail()
+		}
+
+		var stacked *CSR
+		if test.axis == 0 {
+			stacked = VStack(test.matrices...)
+		} else {
+			stacked = HStack(test.matrices...)
+		}
+		if !mat.Equal(expected, stacked) {
+			t.Logf("Expected stacked:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(stacked))
+			t.Fail()
+		}
+	}
+}
+
+func TestVStackZeroValue(t *testing.T) {
+	result := VStack(&CSR{}, &CSR{})
+	if r, c := result.Dims(); r != 0 || c != 0 || result.NNZ() != 0 {
+		t.Errorf("Expected empty 0x0 matrix but received %dx%d with %d elements", r, c, result.NNZ())
+	}
+	if errs := Validate(result); errs != nil {
+		t.Errorf("Expected valid matrix but received errors: %v", errs)
+	}
+}
+
+func TestFailConcat(t *testing.T) {
+	var tests = []struct {
+		axis     int
+		matrices []mat.Matrix
+	}{
+		{axis: 0, matrices: []mat.Matrix{CreateCSR(2, 3, nil), CreateCSR(2, 2, nil)}},
+		{axis: 1, matrices: []mat.Matrix{CreateCSR(2, 3, nil), CreateCSR(3, 3, nil)}},
+		{axis: 2, matrices: []mat.Matrix{CreateCSR(2, 3, nil)}},
+		{axis: 0, matrices: nil},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic but received none")
+				}
+			}()
+			Concat(test.axis, test.matrices...)
+		}()
+	}
+}
+
+func TestFailStack(t *testing.T) {
+	var tests = []struct {
+		stack    func(...mat.Matrix) *CSR
+		matrices []mat.Matrix
+		err      error
+	}{
+		{stack: VStack, matrices: []mat.Matrix{CreateCSR(2, 3, nil), mat.NewDense(2, 2, nil)}, err: mat.ErrShape},
+		{stack: HStack, matrices: []mat.Matrix{CreateCSR(2, 3, nil), mat.NewDense(3, 3, nil)}, err: mat.ErrShape},
+		{stack: VStack, matrices: nil, err: mat.ErrZeroLength},
+		{stack: HStack, matrices: nil, err: mat.ErrZeroLength},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		func() {
+			defer func() {
+				if r := recover(); r != test.err {
+					t.Errorf("Expected panic with %v but received %v", test.err, r)
+				}
+			}()
+			test.stack(test.matrices...)
+		}()
+	}
+}
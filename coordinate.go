@@ -1,7 +1,10 @@
 package sparse
 
 import (
+	"sort"
+
 	"github.com/james-bowman/sparse/blas"
+	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -32,10 +35,10 @@ type COO struct {
 // backing storage to the matrix so changes to values of the slices will be reflected in the created matrix
 // and vice versa.
 func NewCOO(r int, c int, rows []int, cols []int, data []float64) *COO {
-	if uint(r) < 0 {
+	if r < 0 {
 		panic(mat.ErrRowAccess)
 	}
-	if uint(c) < 0 {
+	if c < 0 {
 		panic(mat.ErrColAccess)
 	}
 
@@ -69,6 +72,87 @@ func (c *COO) DoNonZero(fn func(i, j int, v float64)) {
 	}
 }
 
+// DoNonZeroMerged calls the function fn for each of the distinct non-zero coordinates of
+// the receiver.  Unlike DoNonZero, duplicate elements for the same coordinate are merged
+// by summing their values so that fn is called only once per coordinate with the summed
+// value.  The elements are visited in row major order.  To merge the duplicates, the
+// elements are first sorted into temporary storage (an O(nnz + r) bucket sort
+// allocating O(nnz) memory) so the receiver is not modified by this call.  Where the
+// elements will be visited repeatedly, it may be more efficient to convert the matrix
+// to CSR format once using ToCSR.
+func (c *COO) DoNonZeroMerged(fn func(i, j int, v float64)) {
+	ia, ja, data := compress(c.rows, c.cols, c.data, c.r)
+	ja, data = dedupe(ia, ja, data, c.r, c.c)
+	for i := 0; i < c.r; i++ {
+		for k := ia[i]; k < ia[i+1]; k++ {
+			fn(i, ja[k], data[k])
+		}
+	}
+}
+
+// Canonicalize sorts the elements of the receiver into row major order and merges any
+// duplicate elements for the same coordinate by summing their values, shrinking the
+// receiver's backing slices to the resulting number of distinct elements.  The sort is
+// stable and duplicates are summed in insertion order so that the result, including
+// any floating point rounding, is reproducible.  Elements that sum to zero are retained
+// as explicit zeros.  Canonicalize allocates O(nnz) temporary storage and the
+// receiver's row, column and data slices are overwritten in place.
+func (c *COO) Canonicalize() {
+	ia, ja, data := compress(c.rows, c.cols, c.data, c.r)
+	ja, data = dedupe(ia, ja, data, c.r, c.c)
+
+	var pairs []indexPair
+	for i := 0; i < c.r; i++ {
+		pairs = pairs[:0]
+		for k := ia[i]; k < ia[i+1]; k++ {
+			pairs = append(pairs, indexPair{index: ja[k], value: data[k]})
+		}
+		// column indices within a row are now unique so stability is not required
+		sort.Slice(pairs, func(a, b int) bool { return pairs[a].index < pairs[b].index })
+		for k, p := range pairs {
+			c.rows[ia[i]+k] = i
+			c.cols[ia[i]+k] = p.index
+			c.data[ia[i]+k] = p.value
+		}
+	}
+
+	nnz := ia[c.r]
+	c.rows = c.rows[:nnz]
+	c.cols = c.cols[:nnz]
+	c.data = c.data[:nnz]
+}
+
+// Prune removes, in place, all stored entries whose absolute value is less than or
+// equal to tol, shrinking the receiver's backing slices.  A tol of 0 removes only
+// explicitly stored zero values.  As duplicate entries are not merged before pruning,
+// duplicates that would sum to zero are not removed; call Canonicalize first if this
+// is required.
+func (c *COO) Prune(tol float64) {
+	k := 0
+	for i, v := range c.data {
+		if !floats.EqualWithinAbs(v, 0, tol) {
+			c.rows[k] = c.rows[i]
+			c.cols[k] = c.cols[i]
+			c.data[k] = v
+			k++
+		}
+	}
+	c.rows = c.rows[:k]
+	c.cols = c.cols[:k]
+	c.data = c.data[:k]
+}
+
+// IsCanonical returns true if the elements of the receiver are in row major order with
+// no duplicate coordinates, as produced by Canonicalize.
+func (c *COO) IsCanonical() bool {
+	for k := 1; k < len(c.data); k++ {
+		if c.rows[k] < c.rows[k-1] || (c.rows[k] == c.rows[k-1] && c.cols[k] <= c.cols[k-1]) {
+			return false
+		}
+	}
+	return true
+}
+
 // Dims returns the size of the matrix as the number of rows and columns
 func (c *COO) Dims() (int, int) {
 	return c.r, c.c
@@ -78,10 +162,10 @@ func (c *COO) Dims() (int, int) {
 // for i or j fall outside the dimensions of the matrix.  As the COO format allows duplicate elements, any
 // duplicate values will be summed together.
 func (c *COO) At(i, j int) float64 {
-	if uint(i) < 0 || uint(i) >= uint(c.r) {
+	if i < 0 || i >= c.r {
 		panic(mat.ErrRowAccess)
 	}
-	if uint(j) < 0 || uint(j) >= uint(c.c) {
+	if j < 0 || j >= c.c {
 		panic(mat.ErrColAccess)
 	}
 
@@ -113,10 +197,10 @@ func (c *COO) RawMatrix() *blas.SparseMatrix {
 // specified value, v.  Set will panic if specified values for i or j fall outside
 // the dimensions of the matrix.  Duplicate values are allowed and will be added.
 func (c *COO) Set(i, j int, v float64) {
-	if uint(i) < 0 || uint(i) >= uint(c.r) {
+	if i < 0 || i >= c.r {
 		panic(mat.ErrRowAccess)
 	}
-	if uint(j) < 0 || uint(j) >= uint(c.c) {
+	if j < 0 || j >= c.c {
 		panic(mat.ErrColAccess)
 	}
 
@@ -191,13 +275,15 @@ func dedupe(ia []int, ja []int, d []float64, m int, n int) ([]int, []float64) {
 	defer putInts(w)
 	nz := 0
 
+	// w records the position (offset by 1 so zero denotes unvisited) at which each
+	// column index was last stored so duplicates within the current row can be found
 	for i := 0; i < m; i++ {
 		q := nz
 		for j := ia[i]; j < ia[i+1]; j++ {
 			if w[ja[j]] > q {
-				d[w[ja[j]]] += d[j]
+				d[w[ja[j]]-1] += d[j]
 			} else {
-				w[ja[j]] = nz
+				w[ja[j]] = nz + 1
 				ja[nz] = ja[j]
 				d[nz] = d[j]
 				nz++
@@ -273,10 +359,25 @@ func (c *COO) ToCSR() *CSR {
 }
 
 // ToCSRReuseMem returns a CSR (Compressed Sparse Row)(AKA CRS (Compressed Row Storage)) sparse format
-// version of the matrix.  Unlike with ToCSR(), The returned CSR matrix WILL share underlying storage with the
-// receiver and the receiver will be modified by this call.
+// version of the matrix, converting the receiver's storage in place rather than allocating new storage
+// for the result.  This avoids the receiver's storage being doubled during conversion, which matters
+// for very large matrices.  Unlike with ToCSR(), the returned CSR matrix WILL share underlying storage
+// with the receiver and the receiver will be modified by this call.  Specifically, the backing array
+// of the receiver's data slice is reused for the values of the CSR matrix, that of the column index
+// slice for the column indices and that of the row index slice for the row pointers (Indptr).  The row
+// pointers require r+1 elements and so, only if the row index slice has a capacity of less than r+1
+// (i.e. there are fewer stored elements than rows), is a new slice of r+1 elements allocated for them.
+// Elements are permuted into row order in place using O(r) additional workspace and any duplicate
+// elements summed, as for ToCSR.
+//
+// Following this call the receiver no longer owns its former storage and is reset to an empty matrix
+// of the same dimensions with no stored elements.  The receiver's former slices, and any other
+// references to them, are invalid as COO data and must not be used other than through the returned
+// CSR matrix.
 func (c *COO) ToCSRReuseMem() *CSR {
 	ia, ja, data := compressInPlace(c.rows, c.cols, c.data, c.r)
+	ja, data = dedupe(ia, ja, data, c.r, c.c)
+	c.rows, c.cols, c.data = nil, nil, nil
 	return NewCSR(c.r, c.c, ia, ja, data)
 }
 
@@ -290,10 +391,17 @@ func (c *COO) ToCSC() *CSC {
 }
 
 // ToCSCReuseMem returns a CSC (Compressed Sparse Column)(AKA CCS (Compressed Column Storage)) sparse format
-// version of the matrix.  Unlike with ToCSC(), The returned CSC matrix WILL share underlying storage with the
-// receiver and the receiver will be modified by this call.
+// version of the matrix, converting the receiver's storage in place rather than allocating new storage
+// for the result.  Unlike with ToCSC(), the returned CSC matrix WILL share underlying storage with the
+// receiver and the receiver will be modified by this call.  The storage is reused as for ToCSRReuseMem
+// with the roles of rows and columns reversed i.e. the backing array of the receiver's column index
+// slice is reused for the column pointers (allocating new storage only if its capacity is less than
+// c+1) and that of the row index slice for the row indices.  Following this call the receiver is reset
+// to an empty matrix of the same dimensions and its former slices are invalid as COO data.
 func (c *COO) ToCSCReuseMem() *CSC {
 	ja, ia, data := compressInPlace(c.cols, c.rows, c.data, c.c)
+	ia, data = dedupe(ja, ia, data, c.c, c.r)
+	c.rows, c.cols, c.data = nil, nil, nil
 	return NewCSC(c.r, c.c, ja, ia, data)
 }
 
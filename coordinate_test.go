@@ -2,6 +2,7 @@ package sparse
 
 import (
 	"math/rand"
+	"reflect"
 	"testing"
 
 	"gonum.org/v1/gonum/mat"
@@ -142,6 +143,117 @@ func TestCOODoNonZero(t *testing.T) {
 	}
 }
 
+func TestCOODoNonZeroMerged(t *testing.T) {
+	var tests = []struct {
+		r, c     int
+		rows     []int
+		cols     []int
+		data     []float64
+		expected []float64
+	}{
+		{
+			r: 3, c: 3,
+			rows: []int{0, 2, 0, 1, 2, 0},
+			cols: []int{0, 2, 0, 1, 2, 2},
+			data: []float64{1, 3, 4, 2, -1, 5},
+			expected: []float64{
+				5, 0, 5,
+				0, 2, 0,
+				0, 0, 2,
+			},
+		},
+		{
+			r: 2, c: 4,
+			rows: []int{1, 1, 1, 0},
+			cols: []int{3, 3, 3, 1},
+			data: []float64{1, 1, 1, 7},
+			expected: []float64{
+				0, 7, 0, 0,
+				0, 0, 0, 3,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		rows := append([]int(nil), test.rows...)
+		cols := append([]int(nil), test.cols...)
+		data := append([]float64(nil), test.data...)
+		matrix := NewCOO(test.r, test.c, rows, cols, data)
+
+		visited := make(map[key]int)
+		matrix.DoNonZeroMerged(func(i, j int, v float64) {
+			visited[key{i, j}]++
+			if testv := test.expected[i*test.c+j]; testv != v {
+				t.Logf("Expected %f at (%d, %d) but received %f\n", testv, i, j, v)
+				t.Fail()
+			}
+		})
+
+		for k, n := range visited {
+			if n != 1 {
+				t.Logf("Expected (%d, %d) to be visited once but was visited %d times\n", k.i, k.j, n)
+				t.Fail()
+			}
+		}
+		for i, v := range test.expected {
+			if v != 0 && visited[key{i / test.c, i % test.c}] == 0 {
+				t.Logf("Expected (%d, %d) to be visited but it was not\n", i/test.c, i%test.c)
+				t.Fail()
+			}
+		}
+
+		if !reflect.DeepEqual(rows, test.rows) || !reflect.DeepEqual(cols, test.cols) || !reflect.DeepEqual(data, test.data) {
+			t.Logf("Expected COO to be unmodified\n")
+			t.Fail()
+		}
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	var tests = []struct {
+		desc       string
+		m, n       int
+		ia         []int
+		ja         []int
+		d          []float64
+		expectedIa []int
+		expectedJa []int
+		expectedD  []float64
+	}{
+		{
+			desc: "no duplicates",
+			m:    2, n: 3,
+			ia: []int{0, 2, 3}, ja: []int{0, 2, 1}, d: []float64{1, 2, 3},
+			expectedIa: []int{0, 2, 3}, expectedJa: []int{0, 2, 1}, expectedD: []float64{1, 2, 3},
+		},
+		{
+			desc: "duplicates of first element of each row",
+			m:    2, n: 3,
+			ia: []int{0, 3, 6}, ja: []int{1, 2, 1, 0, 0, 2}, d: []float64{1, 2, 3, 4, 5, 6},
+			expectedIa: []int{0, 2, 4}, expectedJa: []int{1, 2, 0, 2}, expectedD: []float64{4, 2, 9, 6},
+		},
+		{
+			desc: "duplicates after empty row",
+			m:    3, n: 2,
+			ia: []int{0, 0, 2, 4}, ja: []int{1, 1, 0, 0}, d: []float64{1, 2, 3, 4},
+			expectedIa: []int{0, 0, 1, 2}, expectedJa: []int{1, 0}, expectedD: []float64{3, 7},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		ja, d := dedupe(test.ia, test.ja, test.d, test.m, test.n)
+		if !reflect.DeepEqual(test.ia, test.expectedIa) || !reflect.DeepEqual(ja, test.expectedJa) || !reflect.DeepEqual(d, test.expectedD) {
+			t.Logf("Expected ia %v, ja %v, d %v but received ia %v, ja %v, d %v\n",
+				test.expectedIa, test.expectedJa, test.expectedD, test.ia, ja, d)
+			t.Fail()
+		}
+	}
+}
+
 func TestCOOTranspose(t *testing.T) {
 	tests := []struct {
 		m     *COO
@@ -200,3 +312,172 @@ func TestCOOTranspose(t *testing.T) {
 		}
 	}
 }
+
+func TestCOOCanonicalize(t *testing.T) {
+	var tests = []struct {
+		r, c      int
+		rows      []int
+		cols      []int
+		data      []float64
+		eRows     []int
+		eCols     []int
+		eData     []float64
+		canonical bool
+	}{
+		{
+			r: 3, c: 3,
+			rows: []int{0, 1, 2}, cols: []int{0, 1, 2}, data: []float64{1, 2, 3},
+			eRows: []int{0, 1, 2}, eCols: []int{0, 1, 2}, eData: []float64{1, 2, 3},
+			canonical: true,
+		},
+		{
+			r: 3, c: 3,
+			rows: []int{2, 0, 1, 0}, cols: []int{0, 2, 1, 0}, data: []float64{1, 2, 3, 4},
+			eRows: []int{0, 0, 1, 2}, eCols: []int{0, 2, 1, 0}, eData: []float64{4, 2, 3, 1},
+			canonical: false,
+		},
+		{
+			r: 3, c: 4,
+			rows: []int{1, 0, 1, 1, 2, 0}, cols: []int{3, 1, 0, 3, 2, 1}, data: []float64{1, 2, 3, 4, 5, -2},
+			eRows: []int{0, 1, 1, 2}, eCols: []int{1, 0, 3, 2}, eData: []float64{0, 3, 5, 5},
+			canonical: false,
+		},
+		{
+			r: 2, c: 2,
+			rows: []int{}, cols: []int{}, data: []float64{},
+			eRows: []int{}, eCols: []int{}, eData: []float64{},
+			canonical: true,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		coo := NewCOO(test.r, test.c, test.rows, test.cols, test.data)
+		expected := mat.DenseCopyOf(coo)
+
+		if coo.IsCanonical() != test.canonical {
+			t.Logf("Expected IsCanonical() %t before Canonicalize but received %t\n", test.canonical, !test.canonical)
+			t.Fail()
+		}
+
+		coo.Canonicalize()
+
+		if !coo.IsCanonical() {
+			t.Logf("Expected IsCanonical() true after Canonicalize\n")
+			t.Fail()
+		}
+		if !reflect.DeepEqual(coo.rows, test.eRows) || !reflect.DeepEqual(coo.cols, test.eCols) || !reflect.DeepEqual(coo.data, test.eData) {
+			t.Logf("Expected rows %v, cols %v, data %v but received rows %v, cols %v, data %v\n",
+				test.eRows, test.eCols, test.eData, coo.rows, coo.cols, coo.data)
+			t.Fail()
+		}
+		if !mat.Equal(expected, coo) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(coo))
+			t.Fail()
+		}
+	}
+}
+
+func TestCOOPrune(t *testing.T) {
+	var tests = []struct {
+		r, c  int
+		rows  []int
+		cols  []int
+		data  []float64
+		tol   float64
+		eRows []int
+		eCols []int
+		eData []float64
+	}{
+		{
+			r: 3, c: 3,
+			rows: []int{0, 1, 2, 2}, cols: []int{0, 1, 2, 0}, data: []float64{1, 0, 3, 0},
+			tol:   0,
+			eRows: []int{0, 2}, eCols: []int{0, 2}, eData: []float64{1, 3},
+		},
+		{
+			r: 3, c: 3,
+			rows: []int{2, 0, 1, 0}, cols: []int{0, 2, 1, 0}, data: []float64{-1, 2, 0.5, 4},
+			tol:   1,
+			eRows: []int{0, 0}, eCols: []int{2, 0}, eData: []float64{2, 4},
+		},
+		{
+			r: 2, c: 2,
+			rows: []int{0, 1}, cols: []int{0, 1}, data: []float64{0, 0},
+			tol:   0,
+			eRows: []int{}, eCols: []int{}, eData: []float64{},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		coo := NewCOO(test.r, test.c, test.rows, test.cols, test.data)
+		coo.Prune(test.tol)
+
+		if !reflect.DeepEqual(coo.rows, test.eRows) || !reflect.DeepEqual(coo.cols, test.eCols) || !reflect.DeepEqual(coo.data, test.eData) {
+			t.Logf("Expected rows %v, cols %v, data %v but received rows %v, cols %v, data %v\n",
+				test.eRows, test.eCols, test.eData, coo.rows, coo.cols, coo.data)
+			t.Fail()
+		}
+	}
+}
+
+func TestCOOReuseMem(t *testing.T) {
+	r, c := 3, 4
+	rows := []int{2, 0, 1, 2, 0, 1, 2, 2}
+	cols := []int{3, 0, 1, 0, 3, 2, 2, 3}
+	data := []float64{4, 1, 2, 3, 7, 4, 3, 2}
+	expected := mat.NewDense(r, c, []float64{
+		1, 0, 0, 7,
+		0, 2, 4, 0,
+		3, 0, 3, 6,
+	})
+
+	var tests = []struct {
+		desc    string
+		convert func(*COO) (Sparser, []int, []int, []float64)
+	}{
+		{
+			"COO -> CSR",
+			func(a *COO) (Sparser, []int, []int, []float64) {
+				csr := a.ToCSRReuseMem()
+				return csr, csr.matrix.Indptr, csr.matrix.Ind, csr.matrix.Data
+			},
+		},
+		{
+			"COO -> CSC",
+			func(a *COO) (Sparser, []int, []int, []float64) {
+				csc := a.ToCSCReuseMem()
+				return csc, csc.matrix.Ind, csc.matrix.Indptr, csc.matrix.Data
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		coo := NewCOO(r, c, append([]int(nil), rows...), append([]int(nil), cols...), append([]float64(nil), data...))
+		origRows, origCols, origData := coo.rows, coo.cols, coo.data
+
+		b, bRows, bCols, bData := test.convert(coo)
+
+		if !mat.Equal(expected, b) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(b))
+			t.Fail()
+		}
+		if b.NNZ() != 7 {
+			t.Logf("Expected duplicates to be summed leaving 7 stored elements but received %d\n", b.NNZ())
+			t.Fail()
+		}
+		if &bRows[0] != &origRows[0] || &bCols[0] != &origCols[0] || &bData[0] != &origData[0] {
+			t.Logf("Expected converted matrix to reuse the storage of the COO matrix\n")
+			t.Fail()
+		}
+		if cr, cc := coo.Dims(); cr != r || cc != c || coo.NNZ() != 0 {
+			t.Logf("Expected COO to be reset to empty %dx%d matrix but was %dx%d with %d elements\n", r, c, cr, cc, coo.NNZ())
+			t.Fail()
+		}
+	}
+}
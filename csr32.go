@@ -0,0 +1,201 @@
+package sparse
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Sparser     = (*CSR32)(nil)
+	_ mat.Mutable = (*CSR32)(nil)
+	_ mulVecToer  = (*CSR32)(nil)
+)
+
+// CSR32 is a Compressed Sparse Row format sparse matrix storing its values with single (float32)
+// rather than double (float64) precision and implements the Matrix interface from gonum/matrix.
+// CSR32 uses the same storage layout as CSR (indptr, ind and data slices) but, as each stored
+// value occupies 4 rather than 8 bytes, halves the memory occupied by the values of the matrix.
+// This is useful for very large matrices where single precision is adequate.
+//
+// Values are converted to float64 when read (e.g. with At) and rounded to the nearest float32
+// when stored (e.g. with Set or when converting from CSR) and so values stored in a CSR32 matrix
+// retain only around 7 significant decimal digits, values with a magnitude greater than
+// math.MaxFloat32 become infinite and very small values lose precision or become zero.  Values
+// read back from a CSR32 matrix will therefore generally not be equal to the float64 values
+// originally stored.  To limit further loss of precision, arithmetic such as matrix vector
+// multiplication accumulates in float64 with only the stored matrix values being single precision.
+type CSR32 struct {
+	i, j   int
+	indptr []int
+	ind    []int
+	data   []float32
+}
+
+// NewCSR32 creates a new single precision Compressed Sparse Row format sparse matrix.
+// The matrix is initialised to the size of the specified r * c dimensions (rows * columns)
+// with the specified slices containing row pointers and cols indexes of non-zero elements
+// and the non-zero data values themselves respectively.  If ia, ja and data are all nil, an
+// empty matrix is created.  Otherwise the supplied slices will be used as the backing storage to
+// the matrix so changes to values of the slices will be reflected in the created matrix
+// and vice versa.
+func NewCSR32(r int, c int, ia []int, ja []int, data []float32) *CSR32 {
+	if r < 0 {
+		panic(mat.ErrRowAccess)
+	}
+	if c < 0 {
+		panic(mat.ErrColAccess)
+	}
+	if ia == nil && ja == nil && data == nil {
+		ia = make([]int, r+1)
+	}
+
+	return &CSR32{i: r, j: c, indptr: ia, ind: ja, data: data}
+}
+
+// Dims returns the size of the matrix as the number of rows and columns
+func (c *CSR32) Dims() (int, int) {
+	return c.i, c.j
+}
+
+// At returns the element of the matrix located at row i and column j, converted to float64.
+// At will panic if specified values for i or j fall outside the dimensions of the matrix.
+func (c *CSR32) At(i, j int) float64 {
+	if i < 0 || i >= c.i {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= c.j {
+		panic(mat.ErrColAccess)
+	}
+
+	for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+		if c.ind[k] == j {
+			return float64(c.data[k])
+		}
+	}
+	return 0
+}
+
+// Set sets the element of the matrix located at row i and column j to value v, rounded to the
+// nearest float32.  If the element is not already stored, it is inserted at the end of row i,
+// updating the sparsity pattern.  Set will panic if specified values for i or j fall outside the
+// dimensions of the matrix.
+func (c *CSR32) Set(i, j int, v float64) {
+	if i < 0 || i >= c.i {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= c.j {
+		panic(mat.ErrColAccess)
+	}
+
+	for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+		if c.ind[k] == j {
+			c.data[k] = float32(v)
+			return
+		}
+	}
+
+	if v == 0 {
+		// don't bother storing new zero values
+		return
+	}
+
+	p := c.indptr[i+1]
+	c.ind = append(c.ind, 0)
+	copy(c.ind[p+1:], c.ind[p:])
+	c.ind[p] = j
+
+	c.data = append(c.data, 0)
+	copy(c.data[p+1:], c.data[p:])
+	c.data[p] = float32(v)
+
+	for n := i + 1; n <= c.i; n++ {
+		c.indptr[n]++
+	}
+}
+
+// T transposes the matrix.  This is an implicit transpose, wrapping the matrix in a mat.Transpose type.
+func (c *CSR32) T() mat.Matrix {
+	return mat.Transpose{Matrix: c}
+}
+
+// NNZ returns the Number of Non Zero elements in the sparse matrix.
+func (c *CSR32) NNZ() int {
+	return len(c.data)
+}
+
+// DoNonZero calls the function fn for each of the stored non-zero elements of the receiver.
+// The function fn takes a row/column index and the element value, converted to float64, of the
+// receiver at (i, j).  The order of visiting to each non-zero element is row major.
+func (c *CSR32) DoNonZero(fn func(i, j int, v float64)) {
+	for i := 0; i < c.i; i++ {
+		for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+			fn(i, c.ind[k], float64(c.data[k]))
+		}
+	}
+}
+
+// Raw returns the row pointers, column indices and values backing the receiver.  The returned
+// slices share storage with the receiver.
+func (c *CSR32) Raw() (indptr, ind []int, data []float32) {
+	return c.indptr, c.ind, c.data
+}
+
+// MulVecTo performs matrix vector multiplication (dst+=A*x or dst+=A^T*x), where A is
+// the receiver, and stores the result in dst.  The stored values of the receiver are
+// converted to float64 and the products accumulated in float64 so only the precision of
+// the matrix values themselves is reduced.  MulVecTo panics if ac != len(x) or
+// ar != len(dst)
+func (c *CSR32) MulVecTo(dst []float64, trans bool, x []float64) {
+	ar, ac := c.Dims()
+	if trans {
+		ar, ac = ac, ar
+	}
+	if ac != len(x) || ar != len(dst) {
+		panic(mat.ErrShape)
+	}
+
+	for i := 0; i < c.i; i++ {
+		if trans {
+			xi := x[i]
+			for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+				dst[c.ind[k]] += float64(c.data[k]) * xi
+			}
+			continue
+		}
+		var sum float64
+		for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+			sum += float64(c.data[k]) * x[c.ind[k]]
+		}
+		dst[i] += sum
+	}
+}
+
+// ToCSR returns a double precision CSR (Compressed Sparse Row) sparse format version of the
+// matrix.  Values are converted exactly from float32 to float64.  The returned CSR matrix will
+// not share underlying storage with the receiver nor is the receiver modified by this call.
+func (c *CSR32) ToCSR() *CSR {
+	indptr := make([]int, len(c.indptr))
+	copy(indptr, c.indptr)
+	ind := make([]int, len(c.ind))
+	copy(ind, c.ind)
+	data := make([]float64, len(c.data))
+	for k, v := range c.data {
+		data[k] = float64(v)
+	}
+	return NewCSR(c.i, c.j, indptr, ind, data)
+}
+
+// ToCSR32 returns a single precision CSR32 sparse format version of the matrix.  Each stored
+// value is rounded to the nearest float32 and so precision will generally be lost (see CSR32).
+// The returned CSR32 matrix will not share underlying storage with the receiver nor is the
+// receiver modified by this call.
+func (c *CSR) ToCSR32() *CSR32 {
+	indptr := make([]int, len(c.matrix.Indptr))
+	copy(indptr, c.matrix.Indptr)
+	ind := make([]int, len(c.matrix.Ind))
+	copy(ind, c.matrix.Ind)
+	data := make([]float32, len(c.matrix.Data))
+	for k, v := range c.matrix.Data {
+		data[k] = float32(v)
+	}
+	return NewCSR32(c.matrix.I, c.matrix.J, indptr, ind, data)
+}
@@ -0,0 +1,94 @@
+package sparse
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCSR32(t *testing.T) {
+	data := []float64{
+		1, 0, 2.5,
+		0, 0, 0,
+		0, -3, 4,
+	}
+	csr := CreateCSR(3, 3, data).(*CSR)
+	csr32 := csr.ToCSR32()
+
+	if !mat.Equal(csr, csr32) {
+		t.Errorf("Expected:\n%v\n but received:\n%v", mat.Formatted(csr), mat.Formatted(csr32))
+	}
+	if csr32.NNZ() != csr.NNZ() {
+		t.Errorf("Expected %d stored elements but received %d", csr.NNZ(), csr32.NNZ())
+	}
+	if back := csr32.ToCSR(); !mat.Equal(csr, back) {
+		t.Errorf("Expected round trip:\n%v\n but received:\n%v", mat.Formatted(csr), mat.Formatted(back))
+	}
+
+	// values are rounded to float32
+	csr32.Set(1, 1, 0.1)
+	if v := csr32.At(1, 1); v != float64(float32(0.1)) || v == 0.1 {
+		t.Errorf("Expected value rounded to float32 %v but received %v", float64(float32(0.1)), v)
+	}
+	csr32.Set(0, 0, 7)
+	if v := csr32.At(0, 0); v != 7 {
+		t.Errorf("Expected updated value 7 but received %v", v)
+	}
+	if csr32.NNZ() != 5 {
+		t.Errorf("Expected 5 stored elements after Set but received %d", csr32.NNZ())
+	}
+
+	empty := NewCSR32(2, 2, nil, nil, nil)
+	empty.Set(1, 0, 3)
+	if empty.At(1, 0) != 3 || empty.NNZ() != 1 {
+		t.Errorf("Expected element set in empty matrix but received %v", empty.At(1, 0))
+	}
+}
+
+func TestCSR32MulVecTo(t *testing.T) {
+	data := []float64{
+		1, 0, 2.5,
+		0, 0, 0,
+		0, -3, 4,
+		5, 0, 0,
+	}
+	csr := CreateCSR(4, 3, data).(*CSR)
+	csr32 := csr.ToCSR32()
+
+	for ti, trans := range []bool{false, true} {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		ar, ac := csr.Dims()
+		if trans {
+			ar, ac = ac, ar
+		}
+		x := make([]float64, ac)
+		for i := range x {
+			x[i] = float64(i + 1)
+		}
+		expected := make([]float64, ar)
+		result := make([]float64, ar)
+		for i := range expected {
+			expected[i], result[i] = 1, 1
+		}
+		csr.MulVecTo(expected, trans, x)
+		csr32.MulVecTo(result, trans, x)
+
+		for i := range expected {
+			if math.Abs(expected[i]-result[i]) > 1e-12 {
+				t.Logf("Expected %v but received %v\n", expected, result)
+				t.Fail()
+				break
+			}
+		}
+	}
+
+	// accumulation is performed in float64
+	wide := NewCSR32(1, 2, []int{0, 2}, []int{0, 1}, []float32{1, 1})
+	dst := make([]float64, 1)
+	wide.MulVecTo(dst, false, []float64{1, 1e-10})
+	if dst[0] != 1+1e-10 {
+		t.Errorf("Expected accumulation in float64 of %v but received %v", 1+1e-10, dst[0])
+	}
+}
@@ -1,6 +1,7 @@
 package sparse
 
 import (
+	"gonum.org/v1/gonum/blas/blas64"
 	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
@@ -9,6 +10,8 @@ var (
 	_ Sparser       = (*DIA)(nil)
 	_ mat.ColViewer = (*DIA)(nil)
 	_ mat.RowViewer = (*DIA)(nil)
+	_ mat.Banded    = (*DIA)(nil)
+	_ mat.RawBander = (*DIA)(nil)
 )
 
 // DIA matrix type is a specialised matrix designed to store DIAgonal values of square symmetrical
@@ -25,10 +28,10 @@ type DIA struct {
 // will be used as the backing slice to the matrix so changes to values of the slice will be reflected
 // in the matrix.
 func NewDIA(m int, n int, diagonal []float64) *DIA {
-	if uint(m) < 0 || m < len(diagonal) {
+	if m < 0 || m < len(diagonal) {
 		panic(mat.ErrRowAccess)
 	}
-	if uint(n) < 0 || n < len(diagonal) {
+	if n < 0 || n < len(diagonal) {
 		panic(mat.ErrColAccess)
 	}
 
@@ -43,14 +46,14 @@ func (d *DIA) Dims() (int, int) {
 // At returns the element of the matrix located at row i and column j.  At will panic if specified values
 // for i or j fall outside the dimensions of the matrix.
 func (d *DIA) At(i, j int) float64 {
-	if uint(i) < 0 || uint(i) >= uint(d.m) {
+	if i < 0 || i >= d.m {
 		panic(mat.ErrRowAccess)
 	}
-	if uint(j) < 0 || uint(j) >= uint(d.n) {
+	if j < 0 || j >= d.n {
 		panic(mat.ErrColAccess)
 	}
 
-	if i == j {
+	if i == j && i < len(d.data) {
 		return d.data[i]
 	}
 	return 0
@@ -62,6 +65,41 @@ func (d *DIA) T() mat.Matrix {
 	return &DIA{m: d.n, n: d.m, data: d.data}
 }
 
+// Bandwidth returns the lower and upper bandwidth values for the matrix, implementing the
+// Gonum mat.Banded interface.  As DIA matrices only store the main diagonal, the lower
+// and upper bandwidths are always 0.
+func (d *DIA) Bandwidth() (kl, ku int) {
+	return 0, 0
+}
+
+// TBand returns the matrix transposed as a mat.Banded, implementing the Gonum mat.Banded
+// interface.  The returned DIA matrix shares the same backing storage as the receiver.
+func (d *DIA) TBand() mat.Banded {
+	return &DIA{m: d.n, n: d.m, data: d.data}
+}
+
+// RawBand returns the diagonal values of the receiver represented as a blas64.Band with
+// lower and upper bandwidth of 0, implementing the Gonum mat.RawBander interface.  This
+// allows Gonum to use its banded code paths when operating on the receiver.  The
+// returned blas64.Band shares the same backing storage as the receiver unless fewer
+// than min(rows, cols) diagonal values are stored, in which case the values are copied
+// into new storage padded with zeros.
+func (d *DIA) RawBand() blas64.Band {
+	data := d.data
+	if n := min(d.m, d.n); len(data) < n {
+		data = make([]float64, n)
+		copy(data, d.data)
+	}
+	return blas64.Band{
+		Rows:   d.m,
+		Cols:   d.n,
+		KL:     0,
+		KU:     0,
+		Stride: 1,
+		Data:   data,
+	}
+}
+
 // DoNonZero calls the function fn for each of the non-zero elements of the receiver.
 // The function fn takes a row/column index and the element value of the receiver at
 // (i, j).  The order of visiting to each non-zero element is from top left to bottom right.
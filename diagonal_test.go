@@ -155,3 +155,65 @@ func TestDIATrace(t *testing.T) {
 
 	}
 }
+
+func TestDIABanded(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{r: 3, c: 3, data: []float64{1, 2, 3}},
+		{r: 3, c: 4, data: []float64{1, 2, 3}},
+		{r: 4, c: 2, data: []float64{-1, 5}},
+		{r: 3, c: 3, data: []float64{4, 5}},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		dia := NewDIA(test.r, test.c, test.data)
+		expected := mat.NewDense(test.r, test.c, nil)
+		for i, v := range test.data {
+			expected.Set(i, i, v)
+		}
+
+		if kl, ku := dia.Bandwidth(); kl != 0 || ku != 0 {
+			t.Logf("Expected bandwidth (0, 0) but received (%d, %d)\n", kl, ku)
+			t.Fail()
+		}
+
+		var b mat.BandDense
+		b.SetRawBand(dia.RawBand())
+		if !mat.Equal(expected, &b) {
+			t.Logf("Expected RawBand:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(&b))
+			t.Fail()
+		}
+
+		tb := mat.TransposeBand{Banded: dia.TBand()}
+		if !mat.Equal(expected, tb) {
+			t.Logf("Expected TBand transposed:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(tb))
+			t.Fail()
+		}
+
+		// Gonum matrix vector multiplication with the banded (Dgbmv) code path
+		x := make([]float64, test.c)
+		for i := range x {
+			x[i] = float64(i + 1)
+		}
+		var got, want mat.VecDense
+		got.MulVec(dia, mat.NewVecDense(test.c, x))
+		want.MulVec(expected, mat.NewVecDense(test.c, x))
+		if !mat.Equal(&want, &got) {
+			t.Logf("Expected MulVec:\n%v\n but received:\n%v\n", mat.Formatted(&want), mat.Formatted(&got))
+			t.Fail()
+		}
+
+		var diag mat.DiagDense
+		diag.DiagFrom(dia)
+		for i := 0; i < diag.Diag(); i++ {
+			if diag.At(i, i) != expected.At(i, i) {
+				t.Logf("Expected DiagFrom value %v at %d but received %v\n", expected.At(i, i), i, diag.At(i, i))
+				t.Fail()
+			}
+		}
+	}
+}
@@ -158,3 +158,54 @@ func (d *DOK) ToType(matType MatrixType) mat.Matrix {
 func (d *DOK) MulVecTo(dst []float64, trans bool, x []float64) {
 	d.ToCSR().MulVecTo(dst, trans, x)
 }
+
+// SetSubMatrix writes the elements of the matrix src into the receiver with the top left
+// element of src written at row i0, column j0 of the receiver.  All existing elements of
+// the receiver within the region covered by src are overwritten, including those
+// corresponding to zero elements of src which are removed from the receiver, whilst
+// elements outside of the region are untouched.  SetSubMatrix will panic if i0 or j0 are
+// out of range or if src does not fit within the receiver at the specified offset.
+func (d *DOK) SetSubMatrix(i0, j0 int, src mat.Matrix) {
+	if i0 < 0 || i0 > d.r {
+		panic(mat.ErrRowAccess)
+	}
+	if j0 < 0 || j0 > d.c {
+		panic(mat.ErrColAccess)
+	}
+	r, c := src.Dims()
+	if i0+r > d.r || j0+c > d.c {
+		panic(mat.ErrShape)
+	}
+
+	// clear existing elements within the region iterating over whichever is smaller
+	if len(d.elements) < r*c {
+		for k := range d.elements {
+			if k.i >= i0 && k.i < i0+r && k.j >= j0 && k.j < j0+c {
+				delete(d.elements, k)
+			}
+		}
+	} else {
+		for i := i0; i < i0+r; i++ {
+			for j := j0; j < j0+c; j++ {
+				delete(d.elements, key{i, j})
+			}
+		}
+	}
+
+	if s, isSparse := src.(mat.NonZeroDoer); isSparse {
+		s.DoNonZero(func(i, j int, v float64) {
+			if v != 0 {
+				d.elements[key{i0 + i, j0 + j}] += v
+			}
+		})
+		return
+	}
+
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if v := src.At(i, j); v != 0 {
+				d.elements[key{i0 + i, j0 + j}] = v
+			}
+		}
+	}
+}
@@ -282,3 +282,92 @@ func TestFailDokIndexes(t *testing.T) {
 		})
 	}
 }
+
+func TestDOKSetSubMatrix(t *testing.T) {
+	var tests = []struct {
+		r, c     int
+		data     []float64
+		i0, j0   int
+		src      mat.Matrix
+		expected []float64
+	}{
+		{
+			r: 4, c: 4,
+			data: []float64{
+				1, 2, 3, 4,
+				5, 6, 7, 8,
+				9, 10, 11, 12,
+				13, 14, 15, 16,
+			},
+			i0: 1, j0: 1,
+			src: mat.NewDense(2, 2, []float64{
+				-1, 0,
+				0, -4,
+			}),
+			expected: []float64{
+				1, 2, 3, 4,
+				5, -1, 0, 8,
+				9, 0, -4, 12,
+				13, 14, 15, 16,
+			},
+		},
+		{
+			r: 4, c: 4,
+			data: []float64{
+				1, 0, 0, 4,
+				0, 6, 0, 0,
+				0, 0, 0, 0,
+				13, 0, 0, 16,
+			},
+			i0: 1, j0: 1,
+			src: CreateCOOWithDupes(2, 2, []float64{
+				0, 2,
+				3, 0,
+			}),
+			expected: []float64{
+				1, 0, 0, 4,
+				0, 0, 2, 0,
+				0, 3, 0, 0,
+				13, 0, 0, 16,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		dok := CreateDOK(test.r, test.c, test.data).(*DOK)
+
+		dok.SetSubMatrix(test.i0, test.j0, test.src)
+
+		if !mat.Equal(expected, dok) {
+			t.Logf("Expected:\n %v\n but received:\n %v\n", mat.Formatted(expected), mat.Formatted(dok))
+			t.Fail()
+		}
+	}
+}
+
+func TestFailDOKSetSubMatrix(t *testing.T) {
+	tcs := []struct {
+		i0, j0 int
+		r, c   int
+	}{
+		{-1, 0, 1, 1},
+		{0, -1, 1, 1},
+		{3, 0, 2, 2},
+		{0, 3, 2, 2},
+		{0, 0, 5, 1},
+	}
+
+	for _, tc := range tcs {
+		t.Run(fmt.Sprintf("%v", tc), func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Haven`t panic for submatrix out of bounds: %v", tc)
+				}
+			}()
+			NewDOK(4, 4).SetSubMatrix(tc.i0, tc.j0, mat.NewDense(tc.r, tc.c, nil))
+		})
+	}
+}
@@ -5,7 +5,7 @@ Sparse matrix formats take advantage of this fact to optimise memory usage and p
 
 Sparse matrix formats can broadly be divided into 3 main categories:
 
-1. Creational - Sparse matrix formats suited to construction and building of matrices.  Matrix formats in this category include DOK (Dictionary Of Keys) and COO (COOrdinate aka triplet).
+1. Creational - Sparse matrix formats suited to construction and building of matrices.  Matrix formats in this category include DOK (Dictionary Of Keys), LIL (List of Lists) and COO (COOrdinate aka triplet).
 
 2. Operational - Sparse matrix formats suited to arithmetic operations e.g. multiplication.  Matrix formats in this category include CSR (Compressed Sparse Row aka CRS - Compressed Row Storage) and CSC (Compressed Sparse Column aka CCS - Compressed Column Storage)
 
@@ -0,0 +1,92 @@
+package sparse
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// DefaultDOTMaxNodes is the maximum number of nodes WriteDOT will render when
+// DOTOptions.MaxNodes is not set.  GraphViz layouts become unreadable (and slow to
+// compute) well before this size.
+const DefaultDOTMaxNodes = 500
+
+// ErrTooLargeForDOT is returned by WriteDOT when the matrix has more nodes than the
+// configured maximum.
+var ErrTooLargeForDOT = errors.New("sparse: matrix too large to render as DOT")
+
+// DOTOptions configures the output of WriteDOT.
+type DOTOptions struct {
+	// Directed emits a digraph with an edge i -> j for every non-zero a(i, j).  If
+	// false, an undirected graph is emitted with a single edge i -- j for each pair of
+	// non-zeros a(i, j) and a(j, i), labelled with the weight of the upper triangular
+	// element where both are stored.
+	Directed bool
+
+	// Name is the name given to the graph.  If empty, the graph is named "G".
+	Name string
+
+	// MaxNodes is the largest matrix dimension WriteDOT will render.  If zero,
+	// DefaultDOTMaxNodes is used.  A negative value removes the limit.
+	MaxNodes int
+}
+
+// WriteDOT writes a GraphViz DOT description of the adjacency matrix a to w.  Each row
+// (and column) of a is a node and each non-zero element a(i, j) is an edge from node i to
+// node j labelled with its weight.  Explicitly stored zero values are not treated as
+// edges.  All nodes are emitted, including isolated ones, so the node count of the
+// rendered graph matches the dimension of a.  WriteDOT will panic if a is not square and
+// returns ErrTooLargeForDOT, without writing anything, if a has more rows than the
+// maximum permitted by opts.
+func WriteDOT(w io.Writer, a *CSR, opts DOTOptions) error {
+	n, c := a.Dims()
+	if n != c {
+		panic(mat.ErrShape)
+	}
+	limit := opts.MaxNodes
+	if limit == 0 {
+		limit = DefaultDOTMaxNodes
+	}
+	if limit > 0 && n > limit {
+		return ErrTooLargeForDOT
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = "G"
+	}
+	kind, op := "graph", "--"
+	if opts.Directed {
+		kind, op = "digraph", "->"
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s %s {\n", kind, strconv.Quote(name))
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(bw, "\t%d;\n", i)
+	}
+	for i := 0; i < n; i++ {
+		for k := a.matrix.Indptr[i]; k < a.matrix.Indptr[i+1]; k++ {
+			j, v := a.matrix.Ind[k], a.matrix.Data[k]
+			if v == 0 {
+				continue
+			}
+			if !opts.Directed && j < i && a.At(j, i) != 0 {
+				// already emitted from the upper triangle
+				continue
+			}
+			from, to := i, j
+			if !opts.Directed && j < i {
+				from, to = j, i
+			}
+			fmt.Fprintf(bw, "\t%d %s %d [label=%q];\n", from, op, to, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	}
+	fmt.Fprint(bw, "}\n")
+
+	return bw.Flush()
+}
@@ -0,0 +1,79 @@
+package sparse
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	var tests = []struct {
+		r, c     int
+		data     []float64
+		opts     DOTOptions
+		header   string
+		expected []string
+		absent   []string
+	}{
+		{
+			r: 3, c: 3,
+			data: []float64{
+				0, 1.5, 0,
+				0, 0, 2,
+				3, 0, 0,
+			},
+			opts:     DOTOptions{Directed: true},
+			header:   "digraph \"G\" {",
+			expected: []string{"\t0 -> 1 [label=\"1.5\"];", "\t1 -> 2 [label=\"2\"];", "\t2 -> 0 [label=\"3\"];"},
+		},
+		{
+			r: 4, c: 4,
+			data: []float64{
+				0, 1, 0, 0,
+				1, 0, 0, 4,
+				0, 0, 0, 0,
+				0, 5, 0, 0,
+			},
+			opts:     DOTOptions{Name: "adj"},
+			header:   "graph \"adj\" {",
+			expected: []string{"\t0 -- 1 [label=\"1\"];", "\t1 -- 3 [label=\"4\"];", "\t2;"},
+			absent:   []string{"\t1 -- 0", "\t3 -- 1", "label=\"5\""},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		var buf bytes.Buffer
+		if err := WriteDOT(&buf, CreateCSR(test.r, test.c, test.data).(*CSR), test.opts); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		out := buf.String()
+		lines := strings.Split(out, "\n")
+		if lines[0] != test.header {
+			t.Logf("Expected header %q but received %q\n", test.header, lines[0])
+			t.Fail()
+		}
+		for _, e := range test.expected {
+			if !strings.Contains(out, e+"\n") {
+				t.Logf("Expected line %q in output:\n%s", e, out)
+				t.Fail()
+			}
+		}
+		for _, e := range test.absent {
+			if strings.Contains(out, e) {
+				t.Logf("Unexpected %q in output:\n%s", e, out)
+				t.Fail()
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, CreateCSR(3, 3, make([]float64, 9)).(*CSR), DOTOptions{MaxNodes: 2}); err != ErrTooLargeForDOT {
+		t.Errorf("Expected ErrTooLargeForDOT but received %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written for oversized matrix but received %q", buf.String())
+	}
+}
@@ -0,0 +1,320 @@
+package sparse
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrNotConverged is returned by iterative algorithms that fail to converge to within the
+// requested tolerance in the maximum number of iterations permitted.
+var ErrNotConverged = errors.New("sparse: failed to converge within maximum iterations")
+
+// Lanczos performs k steps of the Lanczos algorithm on the symmetric matrix a, reducing
+// it to a k x k symmetric tridiagonal matrix T whose eigenvalues (Ritz values)
+// approximate the eigenvalues of a.  The extreme (largest and smallest) eigenvalues of a
+// are typically well approximated after relatively few steps making this the foundation
+// for sparse symmetric eigensolvers.  The diagonal of T is returned in alpha and the
+// off-diagonal in beta (len(beta) == len(alpha)-1).  Each step requires a single matrix
+// vector product using the format specific sparse kernel of a where available.
+//
+// The algorithm may terminate early, returning fewer than k values in alpha, if an
+// invariant subspace of a is found in which case the eigenvalues of T are exact
+// eigenvalues of a.  In finite precision arithmetic, the Lanczos vectors gradually lose
+// orthogonality resulting in spurious duplicate Ritz values - see LanczosReorth for a
+// variant performing full reorthogonalisation to counter this.  The matrix a must be
+// symmetric, Lanczos will panic if a is not square and return an error if k is not
+// in the range 1 <= k <= n where a is n x n.
+func Lanczos(a mat.Matrix, k int) (alpha, beta []float64, err error) {
+	return lanczos(a, k, false)
+}
+
+// LanczosReorth performs k steps of the Lanczos algorithm on the symmetric matrix a as
+// per Lanczos but with full reorthogonalisation of each new Lanczos vector against all
+// previous Lanczos vectors.  This preserves the orthogonality of the Lanczos vectors
+// and so avoids spurious Ritz values at the cost of storing all k Lanczos vectors and
+// O(n*k) additional work per step.
+func LanczosReorth(a mat.Matrix, k int) (alpha, beta []float64, err error) {
+	return lanczos(a, k, true)
+}
+
+// lanczos implements the Lanczos algorithm, optionally performing full
+// reorthogonalisation against all previously computed Lanczos vectors.
+func lanczos(a mat.Matrix, k int, reorth bool) (alpha, beta []float64, err error) {
+	n, c := a.Dims()
+	if n != c {
+		panic(mat.ErrShape)
+	}
+	if k < 1 || k > n {
+		return nil, nil, errors.New("sparse: number of Lanczos steps out of range")
+	}
+
+	alpha = make([]float64, 0, k)
+	beta = make([]float64, 0, k-1)
+
+	var basis [][]float64
+	prev := make([]float64, n)
+	v := make([]float64, n)
+	w := make([]float64, n)
+
+	// start from a normalised pseudo-random vector, seeded for reproducibility,
+	// to avoid starting orthogonal to the sought eigenvectors
+	rnd := rand.New(rand.NewSource(1))
+	for i := range v {
+		v[i] = rnd.Float64() - 0.5
+	}
+	floats.Scale(1/floats.Norm(v, 2), v)
+
+	for j := 0; j < k; j++ {
+		if reorth {
+			basis = append(basis, append([]float64(nil), v...))
+		}
+
+		spmv(w, a, v)
+		wnorm := floats.Norm(w, 2)
+
+		alpha = append(alpha, floats.Dot(w, v))
+		floats.AddScaled(w, -alpha[j], v)
+		if j > 0 {
+			floats.AddScaled(w, -beta[j-1], prev)
+		}
+
+		if reorth {
+			orthogonalise(w, basis)
+		}
+
+		if j == k-1 {
+			break
+		}
+
+		b := floats.Norm(w, 2)
+		if b <= 1e-12*wnorm {
+			// invariant subspace found so the Ritz values are exact eigenvalues
+			break
+		}
+		beta = append(beta, b)
+
+		prev, v, w = v, w, prev
+		floats.Scale(1/b, v)
+	}
+
+	return alpha, beta, nil
+}
+
+// EigenTopK computes the k dominant (largest magnitude) eigenvalues and corresponding
+// eigenvectors of the matrix a using power iteration with deflation.  Each eigenpair is
+// found in turn by power iteration with the iterates orthogonalised (Gram-Schmidt)
+// against the eigenvectors already found, so that subsequent iterations converge to the
+// next most dominant eigenpair.  Each iteration requires a single matrix vector product
+// using the format specific sparse kernel of a where available.
+//
+// The eigenvalues are returned in values, in order of decreasing magnitude, with the
+// corresponding orthonormal eigenvectors returned as the columns of vectors.  An
+// eigenpair is considered converged once the norm of the residual ||Av - lambda*v||
+// is less than or equal to tol.  ErrNotConverged is returned if any eigenpair fails
+// to converge within maxIter iterations.
+//
+// The matrix a should be symmetric to guarantee real eigenvalues and orthogonal
+// eigenvectors.  As with all power iteration methods, convergence is slow where
+// eigenvalues are close in magnitude.  EigenTopK will panic if a is not square and
+// return an error if k is not in the range 1 <= k <= n where a is n x n.
+func EigenTopK(a mat.Matrix, k int, tol float64, maxIter int) (values []float64, vectors *mat.Dense, err error) {
+	n, c := a.Dims()
+	if n != c {
+		panic(mat.ErrShape)
+	}
+	if k < 1 || k > n {
+		return nil, nil, errors.New("sparse: number of eigenpairs out of range")
+	}
+
+	values = make([]float64, k)
+	found := make([][]float64, 0, k)
+	rnd := rand.New(rand.NewSource(1))
+
+	for p := 0; p < k; p++ {
+		v := make([]float64, n)
+		for i := range v {
+			v[i] = rnd.Float64() - 0.5
+		}
+		orthogonalise(v, found)
+		floats.Scale(1/floats.Norm(v, 2), v)
+
+		lambda, _, err := powerIterate(a, v, tol, maxIter, found)
+		if err != nil {
+			return nil, nil, err
+		}
+		values[p] = lambda
+		found = append(found, v)
+	}
+
+	vectors = mat.NewDense(n, k, nil)
+	for j, v := range found {
+		vectors.SetCol(j, v)
+	}
+
+	return values, vectors, nil
+}
+
+// PowerIteration computes the dominant eigenvalue (the eigenvalue of largest magnitude),
+// lambda, and corresponding normalised eigenvector, v, of the square matrix a using power
+// iteration, starting from a fixed pseudo-random vector so that results are reproducible.
+// See PowerIterationFrom to specify the starting vector.  Each iteration applies a to the
+// current iterate, using the format specific sparse kernel of a where available, and
+// normalises the result.  Iteration stops once the norm of the residual ||Av - lambda*v||,
+// where lambda is the Rayleigh quotient of the current iterate, is less than or equal to
+// tol, returning the eigenpair and the number of iterations performed.  If this does not
+// happen within maxIter iterations, the current estimate is returned along with
+// ErrNotConverged.
+//
+// Power iteration only converges where there is a single dominant eigenvalue and does so
+// at a rate determined by the ratio of the magnitudes of the two largest eigenvalues.
+// This makes it well suited to stochastic matrices such as those used for PageRank where
+// the dominant eigenvalue is 1 and well separated.  PowerIteration will panic if a is not
+// square.
+func PowerIteration(a mat.Matrix, tol float64, maxIter int) (lambda float64, v []float64, iters int, err error) {
+	n, _ := a.Dims()
+	v0 := make([]float64, n)
+	rnd := rand.New(rand.NewSource(1))
+	for i := range v0 {
+		v0[i] = rnd.Float64() - 0.5
+	}
+	return PowerIterationFrom(a, v0, tol, maxIter)
+}
+
+// PowerIterationFrom computes the dominant eigenvalue and corresponding normalised
+// eigenvector of the square matrix a using power iteration as per PowerIteration but
+// starting from the vector v0, which need not be normalised.  Starting from a good
+// estimate of the eigenvector, for example the result of a previous computation on a
+// slightly modified matrix, can greatly reduce the number of iterations required.  v0 is
+// not modified by this call.  An error is returned if v0 is the zero vector.
+// PowerIterationFrom will panic if a is not square or if len(v0) does not match the
+// dimensions of a.
+func PowerIterationFrom(a mat.Matrix, v0 []float64, tol float64, maxIter int) (lambda float64, v []float64, iters int, err error) {
+	n, c := a.Dims()
+	if n != c || len(v0) != n {
+		panic(mat.ErrShape)
+	}
+	norm := floats.Norm(v0, 2)
+	if norm == 0 {
+		return 0, nil, 0, errors.New("sparse: zero starting vector")
+	}
+
+	v = make([]float64, n)
+	floats.ScaleTo(v, 1/norm, v0)
+	lambda, iters, err = powerIterate(a, v, tol, maxIter, nil)
+	return lambda, v, iters, err
+}
+
+// powerIterate performs power iteration on the matrix a starting from the normalised
+// vector v, which is updated in place to hold the resulting eigenvector.  Iterates are
+// orthogonalised against the orthonormal vectors in deflate after each matrix vector
+// product.  Iteration continues until the norm of the residual ||Av - lambda*v|| is less
+// than or equal to tol returning the Rayleigh quotient, lambda, and the number of
+// iterations performed or ErrNotConverged if this does not happen within maxIter
+// iterations.
+func powerIterate(a mat.Matrix, v []float64, tol float64, maxIter int, deflate [][]float64) (lambda float64, iters int, err error) {
+	w := make([]float64, len(v))
+	r := make([]float64, len(v))
+
+	for iters = 1; iters <= maxIter; iters++ {
+		spmv(w, a, v)
+		orthogonalise(w, deflate)
+
+		lambda = floats.Dot(w, v)
+		floats.AddScaledTo(r, w, -lambda, v)
+		if floats.Norm(r, 2) <= tol {
+			return lambda, iters, nil
+		}
+
+		floats.Scale(1/floats.Norm(w, 2), w)
+		copy(v, w)
+	}
+
+	return lambda, maxIter, ErrNotConverged
+}
+
+// orthogonalise orthogonalises the vector v against each of the orthonormal vectors in
+// basis using (modified) Gram-Schmidt.
+func orthogonalise(v []float64, basis [][]float64) {
+	for _, q := range basis {
+		floats.AddScaled(v, -floats.Dot(v, q), q)
+	}
+}
+
+// CountEigenvaluesBelow returns the number of eigenvalues of the symmetric matrix a
+// that are less than sigma without computing the eigenvalues themselves.  By
+// Sylvester's law of inertia, the number of eigenvalues of a below sigma equals the
+// number of negative pivots (elements of D) in the LDL^T factorisation of the shifted
+// matrix a - sigma*I.  Counting eigenvalues in this way allows the spectrum to be
+// sliced into intervals, for example to localise eigenvalues by bisection, as the
+// number of eigenvalues in the interval [lo, hi) is simply the difference of the
+// counts below hi and lo.
+//
+// Only the lower triangle of a is referenced.  The factorisation is performed in
+// sparse form (with fill-in) without pivoting and so mat.ErrSingular is returned if a zero
+// or numerically negligible pivot is encountered, as will be the case if sigma is
+// (numerically) an eigenvalue of a.  Without pivoting, a zero pivot may also be
+// encountered for some non-singular indefinite matrices in which case shifting sigma
+// slightly will usually resolve the problem.  CountEigenvaluesBelow will panic if a is
+// not square.
+func CountEigenvaluesBelow(a *CSR, sigma float64) (int, error) {
+	n, c := a.Dims()
+	if n != c {
+		panic(mat.ErrShape)
+	}
+
+	// cols[k] holds the elements of column k of the lower triangle of a - sigma*I
+	cols := make([]map[int]float64, n)
+	for k := range cols {
+		cols[k] = map[int]float64{k: -sigma}
+	}
+	var anorm float64
+	a.DoNonZero(func(i, j int, v float64) {
+		if i >= j {
+			cols[j][i] += v
+		}
+	})
+	for _, col := range cols {
+		for _, v := range col {
+			anorm = math.Max(anorm, math.Abs(v))
+		}
+	}
+	tol := float64(n) * machEpsilon * anorm
+
+	var count int
+	var below []int
+	for k := 0; k < n; k++ {
+		d := cols[k][k]
+		if math.Abs(d) <= tol {
+			return 0, mat.ErrSingular
+		}
+		if d < 0 {
+			count++
+		}
+
+		below = below[:0]
+		for i := range cols[k] {
+			if i > k {
+				below = append(below, i)
+			}
+		}
+		// a(i, j) -= a(i, k) * a(j, k) / d for all k < j <= i
+		for _, j := range below {
+			ljk := cols[k][j] / d
+			for _, i := range below {
+				if i >= j {
+					cols[j][i] -= ljk * cols[k][i]
+				}
+			}
+		}
+		cols[k] = nil
+	}
+
+	return count, nil
+}
+
+// machEpsilon is the machine epsilon (unit roundoff) for float64.
+const machEpsilon = 1.0 / (1 << 53)
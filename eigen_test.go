@@ -0,0 +1,324 @@
+package sparse
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// laplacian1D returns the n x n tridiagonal matrix with 2 along the diagonal and -1
+// along the sub and super diagonals.  The eigenvalues of the matrix are known to be
+// 2 - 2cos(k*pi/(n+1)) for k = 1..n
+func laplacian1D(n int) *CSR {
+	dok := NewDOK(n, n)
+	for i := 0; i < n; i++ {
+		dok.Set(i, i, 2)
+		if i+1 < n {
+			dok.Set(i, i+1, -1)
+			dok.Set(i+1, i, -1)
+		}
+	}
+	return dok.ToCSR()
+}
+
+// tridiagonalEigenvalues returns the eigenvalues, in ascending order, of the symmetric
+// tridiagonal matrix with diagonal alpha and off diagonal beta.
+func tridiagonalEigenvalues(alpha, beta []float64) []float64 {
+	t := mat.NewSymDense(len(alpha), nil)
+	for i, v := range alpha {
+		t.SetSym(i, i, v)
+	}
+	for i, v := range beta {
+		t.SetSym(i, i+1, v)
+	}
+	var eig mat.EigenSym
+	if !eig.Factorize(t, false) {
+		panic("eigen decomposition failed")
+	}
+	return eig.Values(nil)
+}
+
+func TestLanczos(t *testing.T) {
+	var tests = []struct {
+		a         mat.Matrix
+		k         int
+		reorth    bool
+		min, max  float64
+		tolerance float64
+	}{
+		{
+			a:         NewDIA(10, 10, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}),
+			k:         10,
+			reorth:    true,
+			min:       1,
+			max:       10,
+			tolerance: 1e-10,
+		},
+		{
+			a:         NewDIA(10, 10, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}),
+			k:         10,
+			reorth:    false,
+			min:       1,
+			max:       10,
+			tolerance: 1e-8,
+		},
+		{
+			a:         laplacian1D(100),
+			k:         70,
+			reorth:    true,
+			min:       2 - 2*math.Cos(math.Pi/101),
+			max:       2 - 2*math.Cos(100*math.Pi/101),
+			tolerance: 1e-3,
+		},
+		{
+			a:         laplacian1D(100),
+			k:         70,
+			reorth:    false,
+			min:       2 - 2*math.Cos(math.Pi/101),
+			max:       2 - 2*math.Cos(100*math.Pi/101),
+			tolerance: 1e-3,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		var alpha, beta []float64
+		var err error
+		if test.reorth {
+			alpha, beta, err = LanczosReorth(test.a, test.k)
+		} else {
+			alpha, beta, err = Lanczos(test.a, test.k)
+		}
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		if len(beta) != len(alpha)-1 {
+			t.Errorf("Expected %d off diagonal values but received %d", len(alpha)-1, len(beta))
+			continue
+		}
+
+		values := tridiagonalEigenvalues(alpha, beta)
+		if math.Abs(values[0]-test.min) > test.tolerance {
+			t.Errorf("Expected smallest eigenvalue %v but received %v", test.min, values[0])
+		}
+		if math.Abs(values[len(values)-1]-test.max) > test.tolerance {
+			t.Errorf("Expected largest eigenvalue %v but received %v", test.max, values[len(values)-1])
+		}
+	}
+}
+
+func TestLanczosInvalidSteps(t *testing.T) {
+	a := laplacian1D(5)
+	for _, k := range []int{0, 6} {
+		if _, _, err := Lanczos(a, k); err == nil {
+			t.Errorf("Expected error for %d Lanczos steps but received none", k)
+		}
+	}
+}
+
+func TestEigenTopK(t *testing.T) {
+	var tests = []struct {
+		a        mat.Matrix
+		k        int
+		expected []float64
+	}{
+		{
+			a:        NewDIA(5, 5, []float64{5, -9, 1, 7, 3}),
+			k:        3,
+			expected: []float64{-9, 7, 5},
+		},
+		{
+			a: CreateCSR(5, 5, []float64{
+				2, 0, 0, 0, 0,
+				0, 8, 0, 0, 0,
+				0, 0, 4, 0, 0,
+				0, 0, 0, 1, 0,
+				0, 0, 0, 0, 6,
+			}),
+			k:        5,
+			expected: []float64{8, 6, 4, 2, 1},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		values, vectors, err := EigenTopK(test.a, test.k, 1e-10, 10000)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+
+		for i, v := range test.expected {
+			if math.Abs(values[i]-v) > 1e-8 {
+				t.Errorf("Expected eigenvalue %d to be %v but received %v", i, v, values[i])
+			}
+		}
+
+		// eigenvectors should be orthonormal
+		var vtv mat.Dense
+		vtv.Mul(vectors.T(), vectors)
+		if !mat.EqualApprox(&vtv, eye(test.k), 1e-8) {
+			t.Errorf("Expected orthonormal eigenvectors but V^T*V was:\n%v\n", mat.Formatted(&vtv))
+		}
+
+		// and satisfy A*v = lambda*v
+		var av, lv mat.Dense
+		av.Mul(test.a, vectors)
+		lv.Mul(vectors, mat.NewDiagDense(test.k, values))
+		if !mat.EqualApprox(&av, &lv, 1e-8) {
+			t.Errorf("Expected A*V = V*L but received:\n%v\nand\n%v\n", mat.Formatted(&av), mat.Formatted(&lv))
+		}
+	}
+}
+
+func TestEigenTopKNotConverged(t *testing.T) {
+	_, _, err := EigenTopK(NewDIA(3, 3, []float64{1, 1.000001, 0.5}), 1, 1e-14, 2)
+	if err != ErrNotConverged {
+		t.Errorf("Expected ErrNotConverged but received %v", err)
+	}
+}
+
+func TestPowerIteration(t *testing.T) {
+	var tests = []struct {
+		a        mat.Matrix
+		expected float64
+	}{
+		{
+			a:        NewDIA(5, 5, []float64{5, -9, 1, 7, 3}),
+			expected: -9,
+		},
+		{
+			// column stochastic transition matrix (PageRank style) with dominant eigenvalue 1
+			a: CreateCSR(3, 3, []float64{
+				0, 0.5, 1,
+				0.5, 0, 0,
+				0.5, 0.5, 0,
+			}),
+			expected: 1,
+		},
+		{
+			a:        laplacian1D(20),
+			expected: 2 - 2*math.Cos(20*math.Pi/21),
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		lambda, v, iters, err := PowerIteration(test.a, 1e-10, 10000)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		if math.Abs(lambda-test.expected) > 1e-8 {
+			t.Errorf("Expected eigenvalue %v but received %v", test.expected, lambda)
+		}
+		if iters < 1 {
+			t.Errorf("Expected at least 1 iteration but received %d", iters)
+		}
+		if math.Abs(floats.Norm(v, 2)-1) > 1e-12 {
+			t.Errorf("Expected normalised eigenvector but norm was %v", floats.Norm(v, 2))
+		}
+		av := make([]float64, len(v))
+		spmv(av, test.a, v)
+		floats.AddScaled(av, -lambda, v)
+		if floats.Norm(av, 2) > 1e-8 {
+			t.Errorf("Expected A*v = lambda*v but residual was %v", floats.Norm(av, 2))
+		}
+
+		// reproducible
+		lambda2, v2, iters2, _ := PowerIteration(test.a, 1e-10, 10000)
+		if lambda2 != lambda || iters2 != iters || !floats.Equal(v, v2) {
+			t.Errorf("Expected repeated power iteration to produce identical results")
+		}
+	}
+}
+
+func TestPowerIterationFrom(t *testing.T) {
+	a := NewDIA(3, 3, []float64{1, 4, 2})
+
+	v0 := []float64{1, 1, 1}
+	_, coldV, coldIters, err := PowerIterationFrom(a, v0, 1e-10, 1000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !floats.Equal(v0, []float64{1, 1, 1}) {
+		t.Errorf("Expected v0 to be unmodified but received %v", v0)
+	}
+
+	lambda, _, warmIters, err := PowerIterationFrom(a, []float64{0.01, 1, 0.01}, 1e-10, 1000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(lambda-4) > 1e-8 {
+		t.Errorf("Expected eigenvalue 4 but received %v", lambda)
+	}
+	if warmIters >= coldIters {
+		t.Errorf("Expected warm start to converge in fewer iterations than %d but took %d", coldIters, warmIters)
+	}
+	if math.Abs(math.Abs(coldV[1])-1) > 1e-8 {
+		t.Errorf("Expected eigenvector e_1 but received %v", coldV)
+	}
+
+	if _, _, _, err := PowerIterationFrom(a, []float64{0, 0, 0}, 1e-10, 1000); err == nil {
+		t.Errorf("Expected error for zero starting vector")
+	}
+	if _, _, _, err := PowerIterationFrom(NewDIA(3, 3, []float64{1, 1.000001, 0.5}), v0, 1e-14, 2); err != ErrNotConverged {
+		t.Errorf("Expected ErrNotConverged but received %v", err)
+	}
+}
+
+func TestCountEigenvaluesBelow(t *testing.T) {
+	diag := CreateCSR(6, 6, []float64{
+		5, 0, 0, 0, 0, 0,
+		0, -2, 0, 0, 0, 0,
+		0, 0, 3, 0, 0, 0,
+		0, 0, 0, 0.5, 0, 0,
+		0, 0, 0, 0, -7, 0,
+		0, 0, 0, 0, 0, 9,
+	}).(*CSR)
+
+	var tests = []struct {
+		a        *CSR
+		sigma    float64
+		expected int
+		err      error
+	}{
+		{a: diag, sigma: -10, expected: 0},
+		{a: diag, sigma: -3, expected: 1},
+		{a: diag, sigma: 0, expected: 2},
+		{a: diag, sigma: 4, expected: 4},
+		{a: diag, sigma: 100, expected: 6},
+		{a: diag, sigma: 3, err: mat.ErrSingular},
+		// eigenvalues of the 1D Laplacian are 2 - 2cos(k*pi/(n+1)) for k = 1..n
+		{a: laplacian1D(20), sigma: 2 - 2*math.Cos(5.5*math.Pi/21), expected: 5},
+		{a: laplacian1D(20), sigma: 2.1, expected: 10},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		count, err := CountEigenvaluesBelow(test.a, test.sigma)
+		if err != test.err {
+			t.Errorf("Expected error %v but received %v", test.err, err)
+			continue
+		}
+		if err == nil && count != test.expected {
+			t.Errorf("Expected %d eigenvalues below %v but received %d", test.expected, test.sigma, count)
+		}
+	}
+}
+
+// eye returns a new n x n identity matrix.
+func eye(n int) *mat.Dense {
+	d := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		d.Set(i, i, 1)
+	}
+	return d
+}
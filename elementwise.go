@@ -0,0 +1,305 @@
+package sparse
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// DivElem performs element-wise division of matrix a by matrix b (a ./ b), storing the
+// result in the receiver.  The division is only performed at the positions of the
+// non-zero elements of a and so the sparsity pattern of the result matches that of a
+// (no fill-in is introduced where a is zero as 0 / b is 0 for all non-zero b).  Only
+// the structure of a is walked with the corresponding elements of b probed at each
+// position.
+//
+// Division by a zero (or absent) element of b follows IEEE 754 semantics i.e. x / 0
+// produces +Inf or -Inf according to the sign of x (and NaN for explicitly stored zero
+// elements of a).  Callers wishing to treat this as an error should check the result
+// with math.IsInf/math.IsNaN or ensure b has no zeros in the sparsity pattern of a.
+// DivElem will panic if a and b are not the same shape.
+func (c *CSR) DivElem(a, b mat.Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(mat.ErrShape)
+	}
+
+	lhs := csrOf(a)
+
+	if m, temp, restore := c.spalloc(lhs, b); temp {
+		defer restore()
+		c = m
+	}
+
+	rhs, rhsIsCSR := b.(*CSR)
+	var row []float64
+	if rhsIsCSR {
+		row = getFloats(ac, true)
+		defer putFloats(row)
+	}
+
+	for i := 0; i < ar; i++ {
+		if rhsIsCSR {
+			// scatter row i of b into a dense workspace for constant time probing, summing
+			// any duplicate elements
+			for k := rhs.matrix.Indptr[i]; k < rhs.matrix.Indptr[i+1]; k++ {
+				row[rhs.matrix.Ind[k]] += rhs.matrix.Data[k]
+			}
+		}
+
+		for k := lhs.matrix.Indptr[i]; k < lhs.matrix.Indptr[i+1]; k++ {
+			j := lhs.matrix.Ind[k]
+			var v float64
+			if rhsIsCSR {
+				v = row[j]
+			} else {
+				v = b.At(i, j)
+			}
+			c.matrix.Ind = append(c.matrix.Ind, j)
+			c.matrix.Data = append(c.matrix.Data, lhs.matrix.Data[k]/v)
+		}
+		c.matrix.Indptr[i+1] = len(c.matrix.Ind)
+
+		if rhsIsCSR {
+			for k := rhs.matrix.Indptr[i]; k < rhs.matrix.Indptr[i+1]; k++ {
+				row[rhs.matrix.Ind[k]] = 0
+			}
+		}
+	}
+}
+
+// MulElem performs element-wise (Hadamard) multiplication of matrices a and b, storing
+// the result in the receiver.  Non-zero elements are only produced in the result where
+// both a and b have non-zero elements so the sparsity pattern of the result is the
+// intersection of the sparsity patterns of a and b.  Each row of b is scattered into a
+// workspace indexed by column and the corresponding row of a walked, probing the
+// workspace, so the cost is proportional to NNZ(a) + NNZ(b) rather than the dense size
+// of the matrices.  Operands that are not CSR matrices are first converted to CSR.
+// MulElem will panic if a and b are not the same shape.
+func (c *CSR) MulElem(a, b mat.Matrix) {
+	ar, ac := a.Dims()
+	if !sameDims(b, ar, ac) {
+		panic(mat.ErrShape)
+	}
+
+	lhs := csrOf(a)
+	rhs := csrOf(b)
+
+	if m, temp, restore := c.spalloc(lhs, rhs); temp {
+		defer restore()
+		c = m
+	}
+
+	row := getFloats(ac, true)
+	defer putFloats(row)
+
+	for i := 0; i < ar; i++ {
+		for k := rhs.matrix.Indptr[i]; k < rhs.matrix.Indptr[i+1]; k++ {
+			row[rhs.matrix.Ind[k]] += rhs.matrix.Data[k]
+		}
+
+		for k := lhs.matrix.Indptr[i]; k < lhs.matrix.Indptr[i+1]; k++ {
+			j := lhs.matrix.Ind[k]
+			if row[j] == 0 || lhs.matrix.Data[k] == 0 {
+				continue
+			}
+			c.matrix.Ind = append(c.matrix.Ind, j)
+			c.matrix.Data = append(c.matrix.Data, lhs.matrix.Data[k]*row[j])
+		}
+		c.matrix.Indptr[i+1] = len(c.matrix.Ind)
+
+		for k := rhs.matrix.Indptr[i]; k < rhs.matrix.Indptr[i+1]; k++ {
+			row[rhs.matrix.Ind[k]] = 0
+		}
+	}
+}
+
+// CombineElem returns a new CSR matrix containing the result of applying fn element-wise
+// to the receiver and b.  fn is called once for each coordinate where either the
+// receiver or b has a stored element with the value of the absent side passed as 0 and
+// is never called for coordinates where neither matrix has a stored element, so fn
+// should satisfy fn(0, 0) == 0 for the result to be the true element-wise combination.
+// Zero results are not stored in the returned matrix and the column indices of each
+// row of the result are sorted.  Element-wise operations such as addition, subtraction
+// or element-wise maximum can all be expressed in terms of CombineElem e.g.
+//
+//	sum := a.CombineElem(b, func(x, y float64) float64 { return x + y })
+//
+// The returned matrix will not share underlying storage with the receiver or b nor are
+// they modified by this call.  CombineElem will panic if the receiver and b are not the
+// same shape.
+func (c *CSR) CombineElem(b mat.Matrix, fn func(aVal, bVal float64) float64) *CSR {
+	ar, ac := c.Dims()
+	if !sameDims(b, ar, ac) {
+		panic(mat.ErrShape)
+	}
+	rhs := csrOf(b)
+
+	aRow := getFloats(ac, true)
+	defer putFloats(aRow)
+	bRow := getFloats(ac, true)
+	defer putFloats(bRow)
+	seen := make([]bool, ac)
+	var cols []int
+
+	result := NewCSR(ar, ac, make([]int, ar+1), nil, nil)
+	for i := 0; i < ar; i++ {
+		cols = cols[:0]
+		for k := c.matrix.Indptr[i]; k < c.matrix.Indptr[i+1]; k++ {
+			j := c.matrix.Ind[k]
+			aRow[j] += c.matrix.Data[k]
+			if !seen[j] {
+				seen[j] = true
+				cols = append(cols, j)
+			}
+		}
+		for k := rhs.matrix.Indptr[i]; k < rhs.matrix.Indptr[i+1]; k++ {
+			j := rhs.matrix.Ind[k]
+			bRow[j] += rhs.matrix.Data[k]
+			if !seen[j] {
+				seen[j] = true
+				cols = append(cols, j)
+			}
+		}
+		sort.Ints(cols)
+
+		for _, j := range cols {
+			if v := fn(aRow[j], bRow[j]); v != 0 {
+				result.matrix.Ind = append(result.matrix.Ind, j)
+				result.matrix.Data = append(result.matrix.Data, v)
+			}
+			aRow[j], bRow[j], seen[j] = 0, 0, false
+		}
+		result.matrix.Indptr[i+1] = len(result.matrix.Ind)
+	}
+	return result
+}
+
+// Apply applies the function fn to each of the stored elements of a, storing the
+// result in the receiver.  fn takes a row/column index and the value of the stored
+// element of a at (i, j) and returns the new value for that element.  NOTE: unlike
+// mat.Dense.Apply, which visits every element of the matrix, Apply only visits the
+// stored elements of a and so runs in O(NNZ) time.  fn is never called for the
+// implicit zero elements of a, which remain zero in the result, so fn must not rely
+// on being called for every element e.g. Apply cannot be used to add a constant to
+// every element of a matrix.  The sparsity pattern of the result matches that of a;
+// stored elements for which fn returns zero are kept as explicitly stored zeros (see
+// ApplyPruned to remove them).  Operands that are not CSR matrices are first converted
+// to CSR.  Apply will panic if the receiver is not empty and is not the same shape as a.
+func (c *CSR) Apply(fn func(i, j int, v float64) float64, a mat.Matrix) {
+	c.apply(fn, a, false)
+}
+
+// ApplyPruned behaves as Apply but omits from the result any stored elements for which
+// fn returns zero so the sparsity pattern of the result is a subset of that of a.
+// ApplyPruned will panic if the receiver is not empty and is not the same shape as a.
+func (c *CSR) ApplyPruned(fn func(i, j int, v float64) float64, a mat.Matrix) {
+	c.apply(fn, a, true)
+}
+
+// apply applies fn to each of the stored elements of a, storing the result in the
+// receiver and, if prune is true, omitting elements for which fn returns zero.
+func (c *CSR) apply(fn func(i, j int, v float64) float64, a mat.Matrix, prune bool) {
+	src := csrOf(a)
+
+	if m, temp, restore := c.spalloc(src, src); temp {
+		defer restore()
+		c = m
+	}
+
+	for i := 0; i < src.matrix.I; i++ {
+		for k := src.matrix.Indptr[i]; k < src.matrix.Indptr[i+1]; k++ {
+			j := src.matrix.Ind[k]
+			v := fn(i, j, src.matrix.Data[k])
+			if prune && v == 0 {
+				continue
+			}
+			c.matrix.Ind = append(c.matrix.Ind, j)
+			c.matrix.Data = append(c.matrix.Data, v)
+		}
+		c.matrix.Indptr[i+1] = len(c.matrix.Ind)
+	}
+}
+
+// AddScalarToNonZeros returns a new CSR matrix with the same sparsity pattern as a and
+// with s added to each of the stored elements of a.  Only stored elements are affected,
+// the implicit zero elements of a remain zero, and so the result remains exactly as
+// sparse as a.  Note that this is NOT the same as element-wise addition of a scalar to
+// the matrix: adding a non-zero scalar to every element, including the implicit zeros,
+// would produce a fully dense matrix for which a dense format such as mat.Dense is a
+// better fit.  The returned matrix will not share underlying storage with a nor is a
+// modified by this call.
+func AddScalarToNonZeros(a *CSR, s float64) *CSR {
+	return a.mapStored(func(v float64) float64 { return v + s })
+}
+
+// Log returns a new CSR matrix with the same sparsity pattern as the receiver containing
+// the natural logarithm of each of the stored elements of the receiver.  Log operates
+// only on the stored elements: the implicit zero elements of the receiver remain zero in
+// the result rather than mapping to -Inf (log(0)) as they would for a true element-wise
+// logarithm.  Stored elements that are negative will produce NaN and stored zeros -Inf.
+// The returned matrix will not share underlying storage with the receiver nor is the
+// receiver modified by this call.
+func (c *CSR) Log() *CSR {
+	return c.mapStored(math.Log)
+}
+
+// Exp returns a new CSR matrix with the same sparsity pattern as the receiver containing
+// the exponential (e**x) of each of the stored elements of the receiver.  WARNING: Exp
+// operates only on the stored elements: the implicit zero elements of the receiver
+// remain zero in the result rather than mapping to 1 (e**0) as they would for a true
+// element-wise exponential (which would produce a fully dense matrix).  The result is
+// therefore NOT equal to the element-wise exponential of the matrix unless every
+// element is stored.  The returned matrix will not share underlying storage with the
+// receiver nor is the receiver modified by this call.
+func (c *CSR) Exp() *CSR {
+	return c.mapStored(math.Exp)
+}
+
+// mapStored returns a new CSR matrix with the same sparsity pattern as the receiver and
+// with each stored element set to the result of applying fn to the corresponding stored
+// element of the receiver.
+func (c *CSR) mapStored(fn func(v float64) float64) *CSR {
+	indptr := make([]int, len(c.matrix.Indptr))
+	copy(indptr, c.matrix.Indptr)
+	ind := make([]int, len(c.matrix.Ind))
+	copy(ind, c.matrix.Ind)
+	data := make([]float64, len(c.matrix.Data))
+	for i, v := range c.matrix.Data {
+		data[i] = fn(v)
+	}
+	return NewCSR(c.matrix.I, c.matrix.J, indptr, ind, data)
+}
+
+// csrOf returns a CSR format representation of the matrix m.  If m is already a CSR
+// matrix it is returned directly, otherwise m is converted to CSR format either using
+// its ToCSR method (if m is a TypeConverter), by iterating over its non-zero elements
+// (if m is a mat.NonZeroDoer) or by scanning for non-zero elements.
+func csrOf(m mat.Matrix) *CSR {
+	switch t := m.(type) {
+	case *CSR:
+		return t
+	case TypeConverter:
+		return t.ToCSR()
+	case mat.NonZeroDoer:
+		r, c := m.Dims()
+		coo := NewCOO(r, c, nil, nil, nil)
+		t.DoNonZero(coo.Set)
+		return coo.ToCSR()
+	}
+
+	r, c := m.Dims()
+	csr := NewCSR(r, c, make([]int, r+1), nil, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if v := m.At(i, j); v != 0 {
+				csr.matrix.Ind = append(csr.matrix.Ind, j)
+				csr.matrix.Data = append(csr.matrix.Data, v)
+			}
+		}
+		csr.matrix.Indptr[i+1] = len(csr.matrix.Ind)
+	}
+	return csr
+}
@@ -0,0 +1,536 @@
+package sparse
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCSRDivElem(t *testing.T) {
+	inf := math.Inf(1)
+
+	var tests = []struct {
+		r, c     int
+		a        []float64
+		b        []float64
+		expected []float64
+	}{
+		{
+			r: 3, c: 4,
+			a: []float64{
+				2, 0, 0, 8,
+				0, 0, 0, 0,
+				3, 0, 6, -4,
+			},
+			b: []float64{
+				2, 1, 0, 4,
+				0, 3, 0, 0,
+				1, 5, 2, 2,
+			},
+			expected: []float64{
+				1, 0, 0, 2,
+				0, 0, 0, 0,
+				3, 0, 3, -2,
+			},
+		},
+		{
+			// b has zeros at a's non-zero positions
+			r: 2, c: 3,
+			a: []float64{
+				1, 0, -2,
+				0, 4, 0,
+			},
+			b: []float64{
+				0, 7, 0,
+				1, 2, 3,
+			},
+			expected: []float64{
+				inf, 0, -inf,
+				0, 2, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+
+		operands := []struct {
+			desc string
+			a, b mat.Matrix
+		}{
+			{"CSR ./ CSR", CreateCSR(test.r, test.c, test.a), CreateCSR(test.r, test.c, test.b)},
+			{"CSR ./ Dense", CreateCSR(test.r, test.c, test.a), mat.NewDense(test.r, test.c, test.b)},
+			{"COO ./ CSC", CreateCOO(test.r, test.c, test.a), CreateCSC(test.r, test.c, test.b)},
+			{"Dense ./ CSR", mat.NewDense(test.r, test.c, test.a), CreateCSR(test.r, test.c, test.b)},
+		}
+
+		for _, op := range operands {
+			var result CSR
+			result.DivElem(op.a, op.b)
+
+			if !mat.Equal(expected, &result) {
+				t.Logf("%s: Expected:\n%v\n but received:\n%v\n", op.desc, mat.Formatted(expected), mat.Formatted(&result))
+				t.Fail()
+			}
+			if result.NNZ() != csrOf(op.a).NNZ() {
+				t.Logf("%s: Expected result to have the same sparsity pattern as a (%d non-zeros) but had %d", op.desc, csrOf(op.a).NNZ(), result.NNZ())
+				t.Fail()
+			}
+		}
+
+		// receiver aliasing operand a
+		a := CreateCSR(test.r, test.c, test.a).(*CSR)
+		a.DivElem(a, CreateCSR(test.r, test.c, test.b))
+		if !mat.Equal(expected, a) {
+			t.Logf("Aliased: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(a))
+			t.Fail()
+		}
+	}
+}
+
+func TestCSRDivElemDuplicates(t *testing.T) {
+	a := CreateCSR(1, 3, []float64{4, 0, 6})
+	// b stores duplicate elements at column 0 which are summed to 2
+	b := NewCSR(1, 3, []int{0, 3}, []int{0, 2, 0}, []float64{1, 3, 1})
+
+	var quotient, product CSR
+	quotient.DivElem(a, b)
+	product.MulElem(a, b)
+
+	expected := mat.NewDense(1, 3, []float64{2, 0, 2})
+	if !mat.Equal(expected, &quotient) {
+		t.Logf("Expected quotient:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(&quotient))
+		t.Fail()
+	}
+	expected = mat.NewDense(1, 3, []float64{8, 0, 18})
+	if !mat.Equal(expected, &product) {
+		t.Logf("Expected product:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(&product))
+		t.Fail()
+	}
+}
+
+func TestFailCSRDivElem(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected panic for operands of different shapes")
+		}
+	}()
+	var result CSR
+	result.DivElem(CreateCSR(2, 3, nil), CreateCSR(3, 2, nil))
+}
+
+func TestCSRMulElem(t *testing.T) {
+	var tests = []struct {
+		r, c     int
+		a        []float64
+		b        []float64
+		expected []float64
+	}{
+		{ // same sparsity pattern
+			r: 2, c: 3,
+			a: []float64{
+				1, 0, 2,
+				0, 3, 0,
+			},
+			b: []float64{
+				4, 0, -1,
+				0, 0.5, 0,
+			},
+			expected: []float64{
+				4, 0, -2,
+				0, 1.5, 0,
+			},
+		},
+		{ // partially overlapping patterns
+			r: 3, c: 4,
+			a: []float64{
+				1, 2, 0, 0,
+				0, 0, 3, 4,
+				5, 0, 0, 6,
+			},
+			b: []float64{
+				0, 2, 7, 0,
+				1, 0, 0, -1,
+				0, 0, 0, 0,
+			},
+			expected: []float64{
+				0, 4, 0, 0,
+				0, 0, 0, -4,
+				0, 0, 0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		var expectedNNZ int
+		for _, v := range test.expected {
+			if v != 0 {
+				expectedNNZ++
+			}
+		}
+
+		operands := []struct {
+			desc string
+			a, b mat.Matrix
+		}{
+			{"CSR .* CSR", CreateCSR(test.r, test.c, test.a), CreateCSR(test.r, test.c, test.b)},
+			{"CSR .* Dense", CreateCSR(test.r, test.c, test.a), mat.NewDense(test.r, test.c, test.b)},
+			{"COO .* CSC", CreateCOO(test.r, test.c, test.a), CreateCSC(test.r, test.c, test.b)},
+			{"Dense .* DOK", mat.NewDense(test.r, test.c, test.a), CreateDOK(test.r, test.c, test.b)},
+		}
+
+		for _, op := range operands {
+			var result CSR
+			result.MulElem(op.a, op.b)
+
+			if !mat.Equal(expected, &result) {
+				t.Logf("%s: Expected:\n%v\n but received:\n%v\n", op.desc, mat.Formatted(expected), mat.Formatted(&result))
+				t.Fail()
+			}
+			if result.NNZ() != expectedNNZ {
+				t.Logf("%s: Expected %d non-zeros but found %d\n", op.desc, expectedNNZ, result.NNZ())
+				t.Fail()
+			}
+		}
+
+		// receiver aliasing operand b
+		b := CreateCSR(test.r, test.c, test.b).(*CSR)
+		b.MulElem(CreateCSR(test.r, test.c, test.a), b)
+		if !mat.Equal(expected, b) {
+			t.Logf("Aliased: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(b))
+			t.Fail()
+		}
+	}
+}
+
+func TestFailCSRMulElem(t *testing.T) {
+	defer func() {
+		if r := recover(); r != mat.ErrShape {
+			t.Errorf("Expected panic with mat.ErrShape for operands of different shapes but received %v", r)
+		}
+	}()
+	var result CSR
+	result.MulElem(CreateCSR(2, 3, nil), CreateCSR(3, 2, nil))
+}
+
+func TestCSRCombineElem(t *testing.T) {
+	add := func(x, y float64) float64 { return x + y }
+	elemMax := func(x, y float64) float64 { return math.Max(x, y) }
+
+	var tests = []struct {
+		r, c     int
+		a        []float64
+		b        []float64
+		fn       func(x, y float64) float64
+		expected []float64
+	}{
+		{ // Add
+			r: 3, c: 4,
+			a: []float64{
+				1, 0, 2, 0,
+				0, 0, 3, 0,
+				4, 0, 0, -5,
+			},
+			b: []float64{
+				0, 6, -2, 0,
+				0, 0, 0, 0,
+				1, 0, 0, 5,
+			},
+			fn: add,
+			expected: []float64{
+				1, 6, 0, 0,
+				0, 0, 3, 0,
+				5, 0, 0, 0,
+			},
+		},
+		{ // ElemMax
+			r: 3, c: 3,
+			a: []float64{
+				1, -2, 0,
+				0, 4, -1,
+				-3, 0, 0,
+			},
+			b: []float64{
+				2, -1, -5,
+				0, 3, 0,
+				0, 0, 0,
+			},
+			fn: elemMax,
+			expected: []float64{
+				2, -1, 0,
+				0, 4, 0,
+				0, 0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		var expectedNNZ int
+		for _, v := range test.expected {
+			if v != 0 {
+				expectedNNZ++
+			}
+		}
+
+		a := CreateCSR(test.r, test.c, test.a).(*CSR)
+		for _, b := range []mat.Matrix{
+			CreateCSR(test.r, test.c, test.b),
+			CreateCSC(test.r, test.c, test.b),
+			mat.NewDense(test.r, test.c, test.b),
+		} {
+			result := a.CombineElem(b, test.fn)
+
+			if !mat.Equal(expected, result) {
+				t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+				t.Fail()
+			}
+			if result.NNZ() != expectedNNZ {
+				t.Logf("Expected zero results to be dropped leaving %d non-zeros but found %d\n", expectedNNZ, result.NNZ())
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestCSRApply(t *testing.T) {
+	var tests = []struct {
+		r, c     int
+		data     []float64
+		fn       func(i, j int, v float64) float64
+		inPlace  bool
+		expected []float64
+		nnz      int
+		pruned   int
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 7,
+				0, 0, 0, 0,
+				3, 0, -2, 6,
+			},
+			fn: func(i, j int, v float64) float64 { return v * 2 },
+			expected: []float64{
+				2, 0, 0, 14,
+				0, 0, 0, 0,
+				6, 0, -4, 12,
+			},
+			nnz: 5, pruned: 5,
+		},
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 7,
+				0, 0, 0, 0,
+				3, 0, -2, 6,
+			},
+			fn: func(i, j int, v float64) float64 { return float64(i*10+j) + v },
+			expected: []float64{
+				1, 0, 0, 10,
+				0, 0, 0, 0,
+				23, 0, 20, 29,
+			},
+			nnz: 5, pruned: 5,
+		},
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 7,
+				0, 0, 0, 0,
+				3, 0, -2, 6,
+			},
+			fn: func(i, j int, v float64) float64 {
+				if v < 0 {
+					return 0
+				}
+				return v
+			},
+			inPlace: true,
+			expected: []float64{
+				1, 0, 0, 7,
+				0, 0, 0, 0,
+				3, 0, 0, 6,
+			},
+			nnz: 5, pruned: 4,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := CreateCSR(test.r, test.c, test.data).(*CSR)
+		var visited int
+		fn := func(i, j int, v float64) float64 {
+			visited++
+			if v != a.At(i, j) && !test.inPlace {
+				t.Logf("Expected fn called with %v for (%d, %d) but received %v", a.At(i, j), i, j, v)
+				t.Fail()
+			}
+			return test.fn(i, j, v)
+		}
+
+		result := &CSR{}
+		if test.inPlace {
+			result = a
+		}
+		result.Apply(fn, a)
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		if !mat.Equal(expected, result) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+			t.Fail()
+		}
+		if visited != test.nnz {
+			t.Logf("Expected fn to be called %d times but was called %d times", test.nnz, visited)
+			t.Fail()
+		}
+		if result.NNZ() != test.nnz {
+			t.Logf("Expected %d stored elements but received %d", test.nnz, result.NNZ())
+			t.Fail()
+		}
+		if !test.inPlace && !mat.Equal(mat.NewDense(test.r, test.c, test.data), a) {
+			t.Logf("Expected a to be unmodified but was:\n%v\n", mat.Formatted(a))
+			t.Fail()
+		}
+
+		src := CreateCSR(test.r, test.c, test.data).(*CSR)
+		pruned := &CSR{}
+		if test.inPlace {
+			pruned = src
+		}
+		pruned.ApplyPruned(test.fn, src)
+		if !mat.Equal(expected, pruned) {
+			t.Logf("Expected pruned:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(pruned))
+			t.Fail()
+		}
+		if pruned.NNZ() != test.pruned {
+			t.Logf("Expected %d stored elements after pruning but received %d", test.pruned, pruned.NNZ())
+			t.Fail()
+		}
+	}
+}
+
+func TestAddScalarToNonZeros(t *testing.T) {
+	var tests = []struct {
+		r, c     int
+		data     []float64
+		s        float64
+		expected []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 7,
+				0, 0, 0, 0,
+				3, 0, -2, 6,
+			},
+			s: 2,
+			expected: []float64{
+				3, 0, 0, 9,
+				0, 0, 0, 0,
+				5, 0, 0, 8,
+			},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+			s: 5,
+			expected: []float64{
+				0, 0,
+				0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := CreateCSR(test.r, test.c, test.data).(*CSR)
+		result := AddScalarToNonZeros(a, test.s)
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		if !mat.Equal(expected, result) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+			t.Fail()
+		}
+		if result.NNZ() != a.NNZ() {
+			t.Logf("Expected %d stored elements but received %d", a.NNZ(), result.NNZ())
+			t.Fail()
+		}
+		if !mat.Equal(mat.NewDense(test.r, test.c, test.data), a) {
+			t.Logf("Expected a to be unmodified but was:\n%v\n", mat.Formatted(a))
+			t.Fail()
+		}
+	}
+}
+
+func TestCSRLogExp(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 7,
+				0, 0, 0, 0,
+				3, 0, 0.5, 6,
+			},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				math.E, 2,
+				0, 10,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := CreateCSR(test.r, test.c, test.data).(*CSR)
+
+		for _, op := range []struct {
+			desc   string
+			fn     func(float64) float64
+			result *CSR
+		}{
+			{"Log", math.Log, a.Log()},
+			{"Exp", math.Exp, a.Exp()},
+		} {
+			expected := make([]float64, len(test.data))
+			for i, v := range test.data {
+				if v != 0 {
+					expected[i] = op.fn(v)
+				}
+			}
+			e := mat.NewDense(test.r, test.c, expected)
+			if !mat.EqualApprox(e, op.result, 1e-15) {
+				t.Logf("%s: Expected:\n%v\n but received:\n%v\n", op.desc, mat.Formatted(e), mat.Formatted(op.result))
+				t.Fail()
+			}
+			if op.result.NNZ() != a.NNZ() {
+				t.Logf("%s: Expected %d stored elements but received %d", op.desc, a.NNZ(), op.result.NNZ())
+				t.Fail()
+			}
+		}
+
+		if !mat.Equal(mat.NewDense(test.r, test.c, test.data), a) {
+			t.Logf("Expected receiver to be unmodified but was:\n%v\n", mat.Formatted(a))
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package sparse
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// ELLPadding is the sentinel column index used to pad rows of an ELL matrix containing
+// fewer than the maximum number of non-zero elements per row.  Padded entries have a
+// column index of ELLPadding and a value of 0.
+const ELLPadding = -1
+
+var (
+	_ Sparser = (*ELL)(nil)
+)
+
+// ELL is an ELLPACK format sparse matrix implementation and implements the Matrix interface from
+// gonum/matrix.  For a matrix with at most k non-zero elements in any row, ELL stores a dense
+// rows * k array of column indices and a corresponding rows * k array of values, both in row major
+// order, with rows containing fewer than k non-zero elements padded with ELLPadding column indices
+// and zero values.  The regular structure of ELL suits vectorised and GPU hardware where each row
+// (thread) processes the same number of elements with coalesced memory access.
+//
+// The memory required by ELL is proportional to rows * k rather than NNZ and so the format is only
+// efficient where the number of non-zero elements per row is similar across rows.  Where row NNZ
+// varies widely, e.g. a matrix with a single dense row, the padding can dominate - a 10,000 x
+// 10,000 matrix with 10 non-zeros in every row except one with 10,000 would require 10^8 entries
+// compared to roughly 10^5 for CSR.  Such matrices are better stored in CSR format.
+type ELL struct {
+	r, c int
+	k    int
+	ind  []int
+	data []float64
+}
+
+// NewELL creates a new ELLPACK format sparse matrix with r rows and c columns and at most k
+// non-zero elements per row.  ind and data are the row major rows * k arrays of column indices
+// and values, padded with ELLPadding and 0 respectively, and are used as the backing storage of
+// the matrix.  If ind and data are nil, new storage is allocated and initialised with padding
+// i.e. an empty matrix.  NewELL will panic if the dimensions are negative, ind and data are not
+// of length r * k or any column index is out of range.
+func NewELL(r, c, k int, ind []int, data []float64) *ELL {
+	if r < 0 {
+		panic(mat.ErrRowAccess)
+	}
+	if c < 0 {
+		panic(mat.ErrColAccess)
+	}
+	if k < 0 {
+		panic(mat.ErrShape)
+	}
+	if ind == nil && data == nil {
+		ind = make([]int, r*k)
+		for i := range ind {
+			ind[i] = ELLPadding
+		}
+		data = make([]float64, r*k)
+	}
+	if len(ind) != r*k || len(data) != r*k {
+		panic(mat.ErrShape)
+	}
+	for _, j := range ind {
+		if j != ELLPadding && (j < 0 || j >= c) {
+			panic(mat.ErrColAccess)
+		}
+	}
+
+	return &ELL{r: r, c: c, k: k, ind: ind, data: data}
+}
+
+// Dims returns the size of the matrix as the number of rows and columns
+func (e *ELL) Dims() (r, c int) {
+	return e.r, e.c
+}
+
+// At returns the element of the matrix located at row i and column j.  At will panic if specified values
+// for i or j fall outside the dimensions of the matrix.
+func (e *ELL) At(i, j int) float64 {
+	if i < 0 || i >= e.r {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= e.c {
+		panic(mat.ErrColAccess)
+	}
+
+	for p := i * e.k; p < (i+1)*e.k; p++ {
+		if e.ind[p] == j {
+			return e.data[p]
+		}
+	}
+	return 0
+}
+
+// T transposes the matrix.  This is an implicit transpose, wrapping the matrix in a mat.Transpose type.
+func (e *ELL) T() mat.Matrix {
+	return mat.Transpose{Matrix: e}
+}
+
+// DoNonZero calls the function fn for each of the stored (non-padding) elements of the receiver.
+// The function fn takes a row/column index and the element value of the receiver at (i, j).
+func (e *ELL) DoNonZero(fn func(i, j int, v float64)) {
+	for p, j := range e.ind {
+		if j != ELLPadding {
+			fn(p/e.k, j, e.data[p])
+		}
+	}
+}
+
+// NNZ returns the Number of Non Zero (non-padding) elements in the sparse matrix.
+func (e *ELL) NNZ() int {
+	var nnz int
+	for _, j := range e.ind {
+		if j != ELLPadding {
+			nnz++
+		}
+	}
+	return nnz
+}
+
+// Raw returns the maximum number of non-zero elements per row, k, along with the row major
+// rows * k arrays of column indices and values backing the receiver.  The returned slices share
+// storage with the receiver.
+func (e *ELL) Raw() (k int, ind []int, data []float64) {
+	return e.k, e.ind, e.data
+}
+
+// FromCSR sets the receiver to an ELLPACK format copy of the CSR matrix a.  k is set to the
+// maximum number of non-zero elements in any row of a with shorter rows padded with ELLPadding
+// column indices and zero values.  The receiver will not share underlying storage with a.
+func (e *ELL) FromCSR(a *CSR) {
+	r, c := a.Dims()
+	var k int
+	for i := 0; i < r; i++ {
+		if nnz := a.matrix.Indptr[i+1] - a.matrix.Indptr[i]; nnz > k {
+			k = nnz
+		}
+	}
+
+	*e = *NewELL(r, c, k, nil, nil)
+	for i := 0; i < r; i++ {
+		begin, end := a.matrix.Indptr[i], a.matrix.Indptr[i+1]
+		copy(e.ind[i*k:], a.matrix.Ind[begin:end])
+		copy(e.data[i*k:], a.matrix.Data[begin:end])
+	}
+}
+
+// Clone returns a copy of the receiver that does not share underlying storage with it.
+func (e *ELL) Clone() *ELL {
+	ind := make([]int, len(e.ind))
+	copy(ind, e.ind)
+	data := make([]float64, len(e.data))
+	copy(data, e.data)
+	return &ELL{r: e.r, c: e.c, k: e.k, ind: ind, data: data}
+}
+
+// ToCSR returns a CSR (Compressed Sparse Row)(AKA CRS (Compressed Row Storage)) sparse format
+// version of the matrix with padding entries removed.  The returned CSR matrix will not share
+// underlying storage with the receiver nor is the receiver modified by this call.
+func (e *ELL) ToCSR() *CSR {
+	nnz := e.NNZ()
+	indptr := make([]int, e.r+1)
+	ind := make([]int, 0, nnz)
+	data := make([]float64, 0, nnz)
+
+	for i := 0; i < e.r; i++ {
+		for p := i * e.k; p < (i+1)*e.k; p++ {
+			if e.ind[p] != ELLPadding {
+				ind = append(ind, e.ind[p])
+				data = append(data, e.data[p])
+			}
+		}
+		indptr[i+1] = len(ind)
+	}
+
+	return NewCSR(e.r, e.c, indptr, ind, data)
+}
@@ -0,0 +1,120 @@
+package sparse
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestELL(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+		k    int
+		ind  []int
+		vals []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 2, 0,
+				0, 0, 0, 0,
+				3, 4, 0, 5,
+			},
+			k:    3,
+			ind:  []int{0, 2, -1, -1, -1, -1, 0, 1, 3},
+			vals: []float64{1, 2, 0, 0, 0, 0, 3, 4, 5},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+			k:    0,
+			ind:  []int{},
+			vals: []float64{},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.data)
+		// scanning the dense matrix produces sorted column indices
+		csr := csrOf(expected)
+
+		var ell ELL
+		ell.FromCSR(csr)
+
+		if r, c := ell.Dims(); r != test.r || c != test.c {
+			t.Logf("Expected dimensions %dx%d but received %dx%d\n", test.r, test.c, r, c)
+			t.Fail()
+		}
+		k, ind, vals := ell.Raw()
+		if k != test.k || !reflect.DeepEqual(ind, test.ind) || !reflect.DeepEqual(vals, test.vals) {
+			t.Logf("Expected k=%d, ind=%v, data=%v but received k=%d, ind=%v, data=%v\n", test.k, test.ind, test.vals, k, ind, vals)
+			t.Fail()
+		}
+		if !mat.Equal(expected, &ell) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(&ell))
+			t.Fail()
+		}
+		if ell.NNZ() != csr.NNZ() {
+			t.Logf("Expected %d non-zeros but found %d\n", csr.NNZ(), ell.NNZ())
+			t.Fail()
+		}
+
+		back := ell.ToCSR()
+		if !mat.Equal(expected, back) || back.NNZ() != csr.NNZ() {
+			t.Logf("ToCSR: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(back))
+			t.Fail()
+		}
+
+		clone := ell.Clone()
+		if !mat.Equal(expected, clone) {
+			t.Logf("Clone: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(clone))
+			t.Fail()
+		}
+		if len(clone.data) > 0 {
+			clone.data[0] = -100
+			if ell.data[0] == -100 {
+				t.Logf("Clone shares storage with the original\n")
+				t.Fail()
+			}
+		}
+
+		fromRaw := NewELL(test.r, test.c, test.k, test.ind, test.vals)
+		if !mat.Equal(expected, fromRaw) {
+			t.Logf("NewELL: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(fromRaw))
+			t.Fail()
+		}
+	}
+}
+
+func TestNewELLInvalid(t *testing.T) {
+	var tests = []struct {
+		desc    string
+		r, c, k int
+		ind     []int
+		data    []float64
+	}{
+		{"negative rows", -1, 2, 1, nil, nil},
+		{"short ind", 2, 2, 1, []int{0}, []float64{1, 2}},
+		{"column out of range", 2, 2, 1, []int{0, 2}, []float64{1, 2}},
+		{"negative column", 2, 2, 1, []int{0, -2}, []float64{1, 2}},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic for %s", test.desc)
+				}
+			}()
+			NewELL(test.r, test.c, test.k, test.ind, test.data)
+		}()
+	}
+}
@@ -0,0 +1,170 @@
+package sparse
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// EqualSparse returns true if the matrices a and b have the same dimensions and element
+// values.  Unlike mat.Equal, which compares every element via At, EqualSparse only visits
+// the stored elements of sparse matrices and so runs in O(NNZ) rather than O(rows*cols).
+// Where both operands are CSR matrices in canonical form (sorted indices with no
+// duplicates) and share the same sparsity pattern, their Indptr, Ind and Data slices are
+// compared directly.  Otherwise, where rows of both matrices have sorted indices, they are compared by merging the
+// stored elements of each row, otherwise the row of b is scattered into a dense
+// workspace against which the row of a is compared.  Explicitly stored zero values are
+// treated as equal to structural (absent) zeros so two matrices differing only in stored
+// zeros compare equal and duplicate elements within a row are summed.  Operands that are
+// not CSR matrices are first converted to CSR format and, if either operand is not
+// sparse (i.e. does not implement Sparser), the comparison is delegated to mat.Equal.
+func EqualSparse(a, b mat.Matrix) bool {
+	return equalSparse(a, b, func(x, y float64) bool { return x == y }, mat.Equal)
+}
+
+// EqualApprox returns true if the matrices a and b have the same dimensions and each of
+// their element values are equal within tol.  Values are compared in the same way as
+// mat.EqualApprox, using tol as both an absolute and relative tolerance, and otherwise
+// EqualApprox behaves as EqualSparse.
+func EqualApprox(a, b mat.Matrix, tol float64) bool {
+	return equalSparse(a, b,
+		func(x, y float64) bool { return floats.EqualWithinAbsOrRel(x, y, tol, tol) },
+		func(a, b mat.Matrix) bool { return mat.EqualApprox(a, b, tol) },
+	)
+}
+
+// equalSparse compares the matrices a and b element by element using eq to compare values,
+// falling back to dense for comparison of non-sparse operands.
+func equalSparse(a, b mat.Matrix, eq func(x, y float64) bool, dense func(a, b mat.Matrix) bool) bool {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		return false
+	}
+	_, aIsSparse := a.(Sparser)
+	_, bIsSparse := b.(Sparser)
+	if !aIsSparse || !bIsSparse {
+		return dense(a, b)
+	}
+
+	lhs, rhs := csrOf(a), csrOf(b)
+	if ar > 0 && isCanonical(lhs) && isCanonical(rhs) && equalInts(lhs.matrix.Indptr, rhs.matrix.Indptr) {
+		nnz := lhs.matrix.Indptr[ar]
+		if equalInts(lhs.matrix.Ind[:nnz], rhs.matrix.Ind[:nnz]) {
+			bdata := rhs.matrix.Data[:nnz]
+			for k, v := range lhs.matrix.Data[:nnz] {
+				if !eq(v, bdata[k]) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	var wa, wb []float64
+
+	for i := 0; i < ar; i++ {
+		abegin, aend := lhs.matrix.Indptr[i], lhs.matrix.Indptr[i+1]
+		bbegin, bend := rhs.matrix.Indptr[i], rhs.matrix.Indptr[i+1]
+		aind, adata := lhs.matrix.Ind[abegin:aend], lhs.matrix.Data[abegin:aend]
+		bind, bdata := rhs.matrix.Ind[bbegin:bend], rhs.matrix.Data[bbegin:bend]
+
+		if sort.IntsAreSorted(aind) && sort.IntsAreSorted(bind) {
+			if !equalSortedRows(aind, adata, bind, bdata, eq) {
+				return false
+			}
+			continue
+		}
+
+		if wa == nil {
+			wa = getFloats(ac, true)
+			defer putFloats(wa)
+			wb = getFloats(ac, true)
+			defer putFloats(wb)
+		}
+		if !equalScatteredRows(aind, adata, bind, bdata, wa, wb, eq) {
+			return false
+		}
+	}
+	return true
+}
+
+// isCanonical returns true if the indices of every row of the CSR matrix m are sorted and
+// free of duplicates.
+func isCanonical(m *CSR) bool {
+	for i := 0; i < m.matrix.I; i++ {
+		ind := m.matrix.Ind[m.matrix.Indptr[i]:m.matrix.Indptr[i+1]]
+		for k := 1; k < len(ind); k++ {
+			if ind[k] <= ind[k-1] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// equalSortedRows compares the values of two sparse rows with sorted indices by merging
+// their stored elements, summing runs of duplicate indices.
+func equalSortedRows(aind []int, adata []float64, bind []int, bdata []float64, eq func(x, y float64) bool) bool {
+	run := func(ind []int, data []float64, p int) (int, float64, int) {
+		j, sum := ind[p], data[p]
+		for p++; p < len(ind) && ind[p] == j; p++ {
+			sum += data[p]
+		}
+		return j, sum, p
+	}
+
+	pa, pb := 0, 0
+	for pa < len(aind) || pb < len(bind) {
+		switch {
+		case pb == len(bind) || (pa < len(aind) && aind[pa] < bind[pb]):
+			var va float64
+			_, va, pa = run(aind, adata, pa)
+			if !eq(va, 0) {
+				return false
+			}
+		case pa == len(aind) || bind[pb] < aind[pa]:
+			var vb float64
+			_, vb, pb = run(bind, bdata, pb)
+			if !eq(0, vb) {
+				return false
+			}
+		default:
+			var va, vb float64
+			_, va, pa = run(aind, adata, pa)
+			_, vb, pb = run(bind, bdata, pb)
+			if !eq(va, vb) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// equalScatteredRows compares the values of two sparse rows by scattering them into the
+// zeroed dense workspaces wa and wb.  The workspaces are zeroed again before returning.
+func equalScatteredRows(aind []int, adata []float64, bind []int, bdata []float64, wa, wb []float64, eq func(x, y float64) bool) bool {
+	for k, j := range aind {
+		wa[j] += adata[k]
+	}
+	for k, j := range bind {
+		wb[j] += bdata[k]
+	}
+
+	equal := true
+	for _, j := range aind {
+		equal = equal && eq(wa[j], wb[j])
+	}
+	for _, j := range bind {
+		equal = equal && eq(wa[j], wb[j])
+	}
+
+	for _, j := range aind {
+		wa[j], wb[j] = 0, 0
+	}
+	for _, j := range bind {
+		wa[j], wb[j] = 0, 0
+	}
+	return equal
+}
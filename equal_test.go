@@ -0,0 +1,140 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestEqualSparse(t *testing.T) {
+	// 1, 0, 2,
+	// 0, 0, 0,
+	// 0, 3, 4,
+	base := NewCSR(3, 3, []int{0, 2, 2, 4}, []int{0, 2, 1, 2}, []float64{1, 2, 3, 4})
+
+	var tests = []struct {
+		desc        string
+		a, b        mat.Matrix
+		equal       bool
+		approxEqual bool
+	}{
+		{
+			desc:  "identical",
+			a:     base,
+			b:     NewCSR(3, 3, []int{0, 2, 2, 4}, []int{0, 2, 1, 2}, []float64{1, 2, 3, 4}),
+			equal: true, approxEqual: true,
+		},
+		{
+			desc:  "explicit zeros",
+			a:     base,
+			b:     NewCSR(3, 3, []int{0, 3, 4, 6}, []int{0, 1, 2, 1, 1, 2}, []float64{1, 0, 2, 0, 3, 4}),
+			equal: true, approxEqual: true,
+		},
+		{
+			desc:  "unsorted with explicit zeros",
+			a:     base,
+			b:     NewCSR(3, 3, []int{0, 3, 3, 5}, []int{2, 1, 0, 2, 1}, []float64{2, 0, 1, 4, 3}),
+			equal: true, approxEqual: true,
+		},
+		{
+			desc:  "duplicates summed",
+			a:     base,
+			b:     NewCSR(3, 3, []int{0, 3, 3, 5}, []int{0, 0, 2, 1, 2}, []float64{0.5, 0.5, 2, 3, 4}),
+			equal: true, approxEqual: true,
+		},
+		{
+			desc:  "different value",
+			a:     base,
+			b:     NewCSR(3, 3, []int{0, 2, 2, 4}, []int{0, 2, 1, 2}, []float64{1, 2, 3, 5}),
+			equal: false, approxEqual: false,
+		},
+		{
+			desc:  "within tolerance",
+			a:     base,
+			b:     NewCSR(3, 3, []int{0, 2, 2, 4}, []int{0, 2, 1, 2}, []float64{1, 2, 3, 4 + 1e-12}),
+			equal: false, approxEqual: true,
+		},
+		{
+			desc:  "missing element",
+			a:     base,
+			b:     NewCSR(3, 3, []int{0, 1, 1, 3}, []int{0, 1, 2}, []float64{1, 3, 4}),
+			equal: false, approxEqual: false,
+		},
+		{
+			desc:  "additional element",
+			a:     NewCSR(3, 3, []int{0, 1, 1, 3}, []int{0, 1, 2}, []float64{1, 3, 4}),
+			b:     base,
+			equal: false, approxEqual: false,
+		},
+		{
+			desc:  "same row pointers different columns",
+			a:     base,
+			b:     NewCSR(3, 3, []int{0, 2, 2, 4}, []int{0, 2, 0, 2}, []float64{1, 2, 3, 4}),
+			equal: false, approxEqual: false,
+		},
+		{
+			desc:  "same row pointers different columns explicit zeros",
+			a:     NewCSR(1, 3, []int{0, 2}, []int{0, 1}, []float64{1, 0}),
+			b:     NewCSR(1, 3, []int{0, 2}, []int{0, 2}, []float64{1, 0}),
+			equal: true, approxEqual: true,
+		},
+		{
+			desc:  "different shape",
+			a:     base,
+			b:     NewCSR(3, 4, []int{0, 2, 2, 4}, []int{0, 2, 1, 2}, []float64{1, 2, 3, 4}),
+			equal: false, approxEqual: false,
+		},
+		{
+			desc:  "other sparse format",
+			a:     base,
+			b:     NewCOO(3, 3, []int{2, 0, 2, 0}, []int{2, 0, 1, 2}, []float64{4, 1, 3, 2}),
+			equal: true, approxEqual: true,
+		},
+		{
+			desc:  "dense",
+			a:     base,
+			b:     mat.NewDense(3, 3, []float64{1, 0, 2, 0, 0, 0, 0, 3, 4}),
+			equal: true, approxEqual: true,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		if equal := EqualSparse(test.a, test.b); equal != test.equal {
+			t.Logf("Expected EqualSparse %t but received %t\n", test.equal, equal)
+			t.Fail()
+		}
+		if equal := EqualSparse(test.b, test.a); equal != test.equal {
+			t.Logf("Expected EqualSparse (swapped) %t but received %t\n", test.equal, equal)
+			t.Fail()
+		}
+		if equal := EqualApprox(test.a, test.b, 1e-10); equal != test.approxEqual {
+			t.Logf("Expected EqualApprox %t but received %t\n", test.approxEqual, equal)
+			t.Fail()
+		}
+	}
+}
+
+func TestIsCanonical(t *testing.T) {
+	var tests = []struct {
+		indptr    []int
+		ind       []int
+		canonical bool
+	}{
+		{indptr: []int{0, 2, 2, 4}, ind: []int{0, 2, 1, 2}, canonical: true},
+		{indptr: []int{0, 2, 2, 4}, ind: []int{2, 0, 1, 2}, canonical: false},
+		{indptr: []int{0, 2, 2, 4}, ind: []int{0, 0, 1, 2}, canonical: false},
+		{indptr: []int{0, 0, 0, 0}, ind: []int{}, canonical: true},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		m := NewCSR(3, 3, test.indptr, test.ind, make([]float64, len(test.ind)))
+		if canonical := isCanonical(m); canonical != test.canonical {
+			t.Logf("Expected isCanonical %t but received %t\n", test.canonical, canonical)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package sparse
+
+import (
+	"sort"
+)
+
+// Gram returns a new CSR matrix containing the Gram matrix A*A^T of the matrix a e.g. for
+// forming the normal equations of least squares problems.  Each element (i, k) of the result
+// is the dot product of rows i and k of a so, rather than transposing a and performing a
+// general sparse matrix multiplication, Gram uses an index of the rows containing each column
+// to find, for each row i, only the rows k sharing at least one column with it.  As the
+// result is symmetric, only the upper triangle (k >= i) is computed with the lower triangle
+// then mirrored from it.  Elements of the result that sum to exactly zero are not stored and
+// the column indices of each row of the result are sorted.
+func Gram(a *CSR) *CSR {
+	r, _ := a.Dims()
+	t := a.ToCSC()
+
+	acc := getFloats(r, true)
+	defer putFloats(acc)
+	seen := getInts(r, true)
+	defer putInts(seen)
+	var ks []int
+
+	// compute the upper triangle, row by row
+	upptr := make([]int, r+1)
+	var upind []int
+	var updata []float64
+	for i := 0; i < r; i++ {
+		ks = ks[:0]
+		for p := a.matrix.Indptr[i]; p < a.matrix.Indptr[i+1]; p++ {
+			j, v := a.matrix.Ind[p], a.matrix.Data[p]
+			for q := t.matrix.Indptr[j]; q < t.matrix.Indptr[j+1]; q++ {
+				if k := t.matrix.Ind[q]; k >= i {
+					if seen[k] == 0 {
+						seen[k] = 1
+						ks = append(ks, k)
+					}
+					acc[k] += v * t.matrix.Data[q]
+				}
+			}
+		}
+		sort.Ints(ks)
+		for _, k := range ks {
+			if acc[k] != 0 {
+				upind = append(upind, k)
+				updata = append(updata, acc[k])
+			}
+			acc[k] = 0
+			seen[k] = 0
+		}
+		upptr[i+1] = len(upind)
+	}
+
+	// mirror the strictly upper triangle into the lower triangle.  As the rows of the
+	// upper triangle are visited in ascending order, the mirrored elements of each row
+	// are appended in ascending column order ahead of the row's upper elements.
+	indptr := make([]int, r+1)
+	for i := 0; i < r; i++ {
+		indptr[i+1] += upptr[i+1] - upptr[i]
+		for p := upptr[i]; p < upptr[i+1]; p++ {
+			if k := upind[p]; k > i {
+				indptr[k+1]++
+			}
+		}
+	}
+	for i := 0; i < r; i++ {
+		indptr[i+1] += indptr[i]
+	}
+
+	nnz := indptr[r]
+	ind := make([]int, nnz)
+	data := make([]float64, nnz)
+	next := getInts(r, false)
+	defer putInts(next)
+	copy(next, indptr[:r])
+	for i := 0; i < r; i++ {
+		for p := upptr[i]; p < upptr[i+1]; p++ {
+			if k := upind[p]; k > i {
+				ind[next[k]] = i
+				data[next[k]] = updata[p]
+				next[k]++
+			}
+		}
+		n := copy(ind[next[i]:], upind[upptr[i]:upptr[i+1]])
+		copy(data[next[i]:], updata[upptr[i]:upptr[i+1]])
+		next[i] += n
+	}
+
+	return NewCSR(r, r, indptr, ind, data)
+}
@@ -0,0 +1,95 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestGram(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 2, 0,
+				0, 0, 0, 0,
+				0, 3, 4, 5,
+			},
+		},
+		{
+			r: 4, c: 3,
+			data: []float64{
+				1, 0, 0,
+				0, 2, 0,
+				1, -1, 3,
+				0, 0, 4,
+			},
+		},
+		{
+			// rows 0 and 1 are orthogonal so (0, 1) sums to zero
+			r: 2, c: 2,
+			data: []float64{
+				1, 1,
+				1, -1,
+			},
+		},
+		{
+			r: 2, c: 3,
+			data: []float64{
+				0, 0, 0,
+				0, 0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := CreateCSR(test.r, test.c, test.data).(*CSR)
+		d := mat.NewDense(test.r, test.c, test.data)
+		var expected mat.Dense
+		expected.Mul(d, d.T())
+
+		gram := Gram(a)
+
+		if !mat.Equal(&expected, gram) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(gram))
+			t.Fail()
+		}
+		if errs := Validate(gram); len(errs) != 0 {
+			t.Logf("Gram matrix is invalid: %v\n", errs)
+			t.Fail()
+		}
+		if !gram.IsSymmetric() {
+			t.Logf("Expected symmetric result\n")
+			t.Fail()
+		}
+		var nnz int
+		gram.DoNonZero(func(i, j int, v float64) {
+			if v == 0 {
+				t.Logf("Unexpected stored zero at (%d, %d)\n", i, j)
+				t.Fail()
+			}
+			nnz++
+		})
+		if nnz != gram.NNZ() {
+			t.Logf("Expected %d stored elements but visited %d\n", gram.NNZ(), nnz)
+			t.Fail()
+		}
+	}
+}
+
+func TestGramRandom(t *testing.T) {
+	a := Random(CSRFormat, 40, 25, 0.1).(*CSR)
+
+	var expected CSR
+	expected.Mul(a, a.T())
+
+	gram := Gram(a)
+	if !mat.EqualApprox(&expected, gram, 1e-12) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(gram))
+	}
+}
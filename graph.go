@@ -0,0 +1,309 @@
+package sparse
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrNegativeCycle is returned by shortest path algorithms when a cycle of negative
+// total weight is reachable from the source vertex such that shortest paths are undefined.
+var ErrNegativeCycle = errors.New("sparse: negative cycle reachable from source")
+
+// ShortestPaths computes the shortest path distances from the source vertex to every other
+// vertex of the weighted directed graph represented by the adjacency matrix a, using the
+// Bellman-Ford algorithm.  Each stored element a(i, j) of the matrix represents an edge from
+// vertex i to vertex j with weight equal to the element's value.  As the CSR format stores
+// the outgoing edges of each vertex contiguously, the edges are relaxed directly from the
+// compressed structure.  Negative edge weights are permitted but ErrNegativeCycle will be
+// returned if a cycle of negative total weight is reachable from source.  Vertices that are
+// unreachable from source are assigned a distance of +Inf.  ShortestPaths will panic if a
+// is not square or if source is out of range.
+func ShortestPaths(a *CSR, source int) ([]float64, error) {
+	n, c := a.Dims()
+	if n != c {
+		panic(mat.ErrShape)
+	}
+	if source < 0 || source >= n {
+		panic(mat.ErrRowAccess)
+	}
+
+	dist := make([]float64, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	dist[source] = 0
+
+	relax := func() bool {
+		changed := false
+		for i := 0; i < n; i++ {
+			if math.IsInf(dist[i], 1) {
+				continue
+			}
+			for k := a.matrix.Indptr[i]; k < a.matrix.Indptr[i+1]; k++ {
+				if d := dist[i] + a.matrix.Data[k]; d < dist[a.matrix.Ind[k]] {
+					dist[a.matrix.Ind[k]] = d
+					changed = true
+				}
+			}
+		}
+		return changed
+	}
+
+	for iter := 0; iter < n-1; iter++ {
+		if !relax() {
+			return dist, nil
+		}
+	}
+
+	if relax() {
+		return nil, ErrNegativeCycle
+	}
+	return dist, nil
+}
+
+// StronglyConnectedComponents computes the strongly connected components of the directed
+// graph represented by the adjacency matrix a using Tarjan's algorithm.  Each stored
+// element a(i, j) of the matrix represents an edge from vertex i to vertex j.  The
+// returned slice contains a component label for each vertex such that two vertices share
+// the same label if, and only if, each is reachable from the other.  Labels are numbered
+// consecutively from 0 in the order the components are completed which is a reverse
+// topological order of the condensation of the graph i.e. edges between components only
+// lead from higher to lower labels.  A matrix is irreducible if all vertices belong to a
+// single component.  The algorithm is implemented iteratively so is not limited by the
+// depth of the goroutine stack.  StronglyConnectedComponents will panic if a is not square.
+func StronglyConnectedComponents(a *CSR) []int {
+	n, c := a.Dims()
+	if n != c {
+		panic(mat.ErrShape)
+	}
+
+	indptr, ind := a.matrix.Indptr, a.matrix.Ind
+
+	const unvisited = -1
+	index := make([]int, n)
+	lowlink := make([]int, n)
+	labels := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = unvisited
+	}
+
+	var stack []int
+	// call stack of vertices being explored along with the position of the next edge
+	// to explore for each
+	var calls []int
+	next := make([]int, n)
+	counter, label := 0, 0
+
+	for root := 0; root < n; root++ {
+		if index[root] != unvisited {
+			continue
+		}
+
+		calls = append(calls[:0], root)
+		index[root], lowlink[root] = counter, counter
+		counter++
+		next[root] = indptr[root]
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(calls) > 0 {
+			v := calls[len(calls)-1]
+
+			if k := next[v]; k < indptr[v+1] {
+				next[v]++
+				w := ind[k]
+				if index[w] == unvisited {
+					index[w], lowlink[w] = counter, counter
+					counter++
+					next[w] = indptr[w]
+					stack = append(stack, w)
+					onStack[w] = true
+					calls = append(calls, w)
+				} else if onStack[w] && index[w] < lowlink[v] {
+					lowlink[v] = index[w]
+				}
+				continue
+			}
+
+			// all edges of v explored
+			calls = calls[:len(calls)-1]
+			if len(calls) > 0 {
+				if u := calls[len(calls)-1]; lowlink[v] < lowlink[u] {
+					lowlink[u] = lowlink[v]
+				}
+			}
+
+			if lowlink[v] == index[v] {
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					labels[w] = label
+					if w == v {
+						break
+					}
+				}
+				label++
+			}
+		}
+	}
+
+	return labels
+}
+
+// KCore computes the coreness of each vertex of the undirected graph represented by the
+// adjacency matrix a.  The coreness of a vertex is the largest k for which the vertex
+// belongs to the k-core of the graph i.e. the maximal subgraph in which every vertex
+// has degree of at least k.  The coreness is computed for all vertices in O(n + nnz)
+// time using the bucket based peeling algorithm of Batagelj and Zaversnik which
+// repeatedly removes the vertex of minimum remaining degree.  Each stored off diagonal
+// element a(i, j) represents an edge between vertices i and j and self loops (diagonal
+// elements) are ignored.  The matrix a is assumed to be symmetric (i.e. a(i, j) is
+// stored if, and only if, a(j, i) is stored) - if it is not, the results are undefined.
+// KCore will panic if a is not square.
+func KCore(a *CSR) []int {
+	n, c := a.Dims()
+	if n != c {
+		panic(mat.ErrShape)
+	}
+
+	indptr, ind := a.matrix.Indptr, a.matrix.Ind
+
+	deg := make([]int, n)
+	maxDeg := 0
+	for i := 0; i < n; i++ {
+		for k := indptr[i]; k < indptr[i+1]; k++ {
+			if ind[k] != i {
+				deg[i]++
+			}
+		}
+		if deg[i] > maxDeg {
+			maxDeg = deg[i]
+		}
+	}
+
+	// bucket sort the vertices by degree: vert holds the vertices in order of
+	// degree, pos the position of each vertex in vert and bin the starting
+	// position of each degree in vert
+	bin := make([]int, maxDeg+1)
+	for _, d := range deg {
+		bin[d]++
+	}
+	start := 0
+	for d, count := range bin {
+		bin[d] = start
+		start += count
+	}
+	vert := make([]int, n)
+	pos := make([]int, n)
+	for v, d := range deg {
+		pos[v] = bin[d]
+		vert[pos[v]] = v
+		bin[d]++
+	}
+	for d := maxDeg; d > 0; d-- {
+		bin[d] = bin[d-1]
+	}
+	bin[0] = 0
+
+	for i := 0; i < n; i++ {
+		v := vert[i]
+		for k := indptr[v]; k < indptr[v+1]; k++ {
+			u := ind[k]
+			if u == v || deg[u] <= deg[v] {
+				continue
+			}
+			// move u to the start of its degree bucket and decrement its degree
+			du := deg[u]
+			pu := pos[u]
+			pw := bin[du]
+			w := vert[pw]
+			if u != w {
+				pos[u], pos[w] = pw, pu
+				vert[pu], vert[pw] = w, u
+			}
+			bin[du]++
+			deg[u]--
+		}
+	}
+
+	return deg
+}
+
+// RCM computes a Reverse Cuthill-McKee ordering of the vertices of the undirected graph
+// represented by the sparsity pattern of the square matrix a, returning it as a
+// permutation suitable for use with Permute i.e. perm[i] is the index of the row/column of
+// a to be placed at row/column i of the reordered matrix.  Symmetrically reordering a with
+// the returned permutation typically reduces its bandwidth, clustering the stored elements
+// close to the diagonal, which reduces fill-in during subsequent factorisation.
+//
+// Each stored off diagonal element a(i, j) represents an edge between vertices i and j and
+// self loops (diagonal elements) are ignored.  Each connected component of the graph is
+// ordered in turn by a breadth first search starting from the unvisited vertex of lowest
+// degree, visiting the neighbours of each vertex in order of increasing degree (with ties
+// resolved by vertex index), and the resulting Cuthill-McKee ordering is then reversed.
+// The matrix a is assumed to have a symmetric sparsity pattern - for unsymmetric matrices
+// the ordering should be computed from the pattern of a + a^T (see PatternOr).  RCM will
+// panic with mat.ErrShape if a is not square.
+func RCM(a *CSR) []int {
+	n, c := a.Dims()
+	if n != c {
+		panic(mat.ErrShape)
+	}
+
+	indptr, ind := a.matrix.Indptr, a.matrix.Ind
+
+	deg := make([]int, n)
+	for i := 0; i < n; i++ {
+		for k := indptr[i]; k < indptr[i+1]; k++ {
+			if ind[k] != i {
+				deg[i]++
+			}
+		}
+	}
+
+	// candidate starting vertices for each component in order of increasing degree
+	starts := make([]int, n)
+	for i := range starts {
+		starts[i] = i
+	}
+	sort.SliceStable(starts, func(x, y int) bool { return deg[starts[x]] < deg[starts[y]] })
+
+	visited := make([]bool, n)
+	order := make([]int, 0, n)
+	for _, start := range starts {
+		if visited[start] {
+			continue
+		}
+		visited[start] = true
+		order = append(order, start)
+
+		// order doubles as the breadth first search queue for the component
+		for head := len(order) - 1; head < len(order); head++ {
+			v := order[head]
+			begin := len(order)
+			for k := indptr[v]; k < indptr[v+1]; k++ {
+				if u := ind[k]; !visited[u] {
+					visited[u] = true
+					order = append(order, u)
+				}
+			}
+			adj := order[begin:]
+			sort.Slice(adj, func(x, y int) bool {
+				if deg[adj[x]] != deg[adj[y]] {
+					return deg[adj[x]] < deg[adj[y]]
+				}
+				return adj[x] < adj[y]
+			})
+		}
+	}
+
+	for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+		order[i], order[j] = order[j], order[i]
+	}
+	return order
+}
@@ -0,0 +1,259 @@
+package sparse
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestShortestPaths(t *testing.T) {
+	inf := math.Inf(1)
+
+	var tests = []struct {
+		n        int
+		edges    [][3]float64
+		source   int
+		expected []float64
+		err      error
+	}{
+		{
+			// 0 -> 1 (4), 0 -> 2 (1), 2 -> 1 (2), 1 -> 3 (1), 2 -> 3 (5), 4 isolated
+			n:        5,
+			edges:    [][3]float64{{0, 1, 4}, {0, 2, 1}, {2, 1, 2}, {1, 3, 1}, {2, 3, 5}},
+			source:   0,
+			expected: []float64{0, 3, 1, 4, inf},
+		},
+		{
+			// negative weight edge but no negative cycle
+			n:        4,
+			edges:    [][3]float64{{0, 1, 5}, {0, 2, 2}, {1, 2, -4}, {2, 3, 1}},
+			source:   0,
+			expected: []float64{0, 5, 1, 2},
+		},
+		{
+			// negative cycle 1 -> 2 -> 1 reachable from 0
+			n:      3,
+			edges:  [][3]float64{{0, 1, 1}, {1, 2, -2}, {2, 1, 1}},
+			source: 0,
+			err:    ErrNegativeCycle,
+		},
+		{
+			// negative cycle 1 -> 2 -> 1 not reachable from 0
+			n:        3,
+			edges:    [][3]float64{{1, 2, -2}, {2, 1, 1}},
+			source:   0,
+			expected: []float64{0, inf, inf},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		dok := NewDOK(test.n, test.n)
+		for _, e := range test.edges {
+			dok.Set(int(e[0]), int(e[1]), e[2])
+		}
+
+		dist, err := ShortestPaths(dok.ToCSR(), test.source)
+		if err != test.err {
+			t.Errorf("Expected error %v but received %v", test.err, err)
+			continue
+		}
+		if test.err == nil && !floats.Equal(test.expected, dist) {
+			t.Errorf("Expected distances %v but received %v", test.expected, dist)
+		}
+	}
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	var tests = []struct {
+		n          int
+		edges      [][2]int
+		components [][]int
+	}{
+		{
+			// cycle 0 -> 1 -> 2 -> 0 linked one way to cycle 3 -> 4 -> 3
+			n:          5,
+			edges:      [][2]int{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {3, 4}, {4, 3}},
+			components: [][]int{{0, 1, 2}, {3, 4}},
+		},
+		{
+			// single component (irreducible)
+			n:          4,
+			edges:      [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}},
+			components: [][]int{{0, 1, 2, 3}},
+		},
+		{
+			// directed path with isolated vertex
+			n:          4,
+			edges:      [][2]int{{0, 1}, {1, 2}},
+			components: [][]int{{0}, {1}, {2}, {3}},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		dok := NewDOK(test.n, test.n)
+		for _, e := range test.edges {
+			dok.Set(e[0], e[1], 1)
+		}
+
+		labels := StronglyConnectedComponents(dok.ToCSR())
+
+		seen := make(map[int]bool)
+		for _, comp := range test.components {
+			l := labels[comp[0]]
+			if seen[l] {
+				t.Errorf("Expected distinct label for component %v but label %d already used: %v", comp, l, labels)
+			}
+			seen[l] = true
+			for _, v := range comp {
+				if labels[v] != l {
+					t.Errorf("Expected vertices %v to share a label but received %v", comp, labels)
+				}
+			}
+		}
+
+		// edges between components must lead from higher to lower labels
+		for _, e := range test.edges {
+			if labels[e[0]] < labels[e[1]] {
+				t.Errorf("Expected reverse topological labels but edge %v leads from %d to %d", e, labels[e[0]], labels[e[1]])
+			}
+		}
+	}
+}
+
+func TestKCore(t *testing.T) {
+	var tests = []struct {
+		n        int
+		edges    [][2]int
+		expected []int
+	}{
+		{
+			// 4-clique (0-3) forming a 3-core, with vertex 4 attached to 2 clique
+			// vertices (2-core), a tail 5 attached to 4 (1-core) and isolated 6
+			n: 7,
+			edges: [][2]int{
+				{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3},
+				{4, 0}, {4, 1},
+				{5, 4},
+			},
+			expected: []int{3, 3, 3, 3, 2, 1, 0},
+		},
+		{
+			// cycle with a self loop which should be ignored
+			n:        4,
+			edges:    [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 0}, {2, 2}},
+			expected: []int{2, 2, 2, 2},
+		},
+		{
+			// star graph
+			n:        5,
+			edges:    [][2]int{{0, 1}, {0, 2}, {0, 3}, {0, 4}},
+			expected: []int{1, 1, 1, 1, 1},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		dok := NewDOK(test.n, test.n)
+		for _, e := range test.edges {
+			dok.Set(e[0], e[1], 1)
+			dok.Set(e[1], e[0], 1)
+		}
+
+		result := KCore(dok.ToCSR())
+		if !reflect.DeepEqual(test.expected, result) {
+			t.Errorf("Expected coreness %v but received %v", test.expected, result)
+		}
+	}
+}
+
+func TestRCM(t *testing.T) {
+	bandwidth := func(m *CSR) int {
+		var bw int
+		m.DoNonZero(func(i, j int, v float64) {
+			if d := i - j; d > bw {
+				bw = d
+			} else if -d > bw {
+				bw = -d
+			}
+		})
+		return bw
+	}
+
+	// paths (tridiagonal matrices) shuffled by a symmetric permutation, including a graph
+	// with an isolated vertex and two disconnected paths, and a grid
+	var tests = []struct {
+		n         int
+		edges     [][2]int
+		shuffle   []int
+		bandwidth int
+	}{
+		{
+			n:         6,
+			edges:     [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}},
+			shuffle:   []int{3, 0, 5, 1, 4, 2},
+			bandwidth: 1,
+		},
+		{
+			n:         7,
+			edges:     [][2]int{{0, 1}, {1, 2}, {4, 5}, {5, 6}},
+			shuffle:   []int{6, 2, 3, 0, 5, 1, 4},
+			bandwidth: 1,
+		},
+		{
+			// 2 x 4 grid (ladder)
+			n:         8,
+			edges:     [][2]int{{0, 1}, {1, 2}, {2, 3}, {4, 5}, {5, 6}, {6, 7}, {0, 4}, {1, 5}, {2, 6}, {3, 7}},
+			shuffle:   []int{0, 1, 2, 3, 4, 5, 6, 7},
+			bandwidth: 2,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		coo := NewCOO(test.n, test.n, nil, nil, nil)
+		for i := 0; i < test.n; i++ {
+			coo.Set(i, i, 1)
+		}
+		for _, e := range test.edges {
+			coo.Set(e[0], e[1], 1)
+			coo.Set(e[1], e[0], 1)
+		}
+		a := coo.ToCSR().Permute(test.shuffle)
+
+		perm := RCM(a)
+
+		seen := make([]bool, test.n)
+		for _, p := range perm {
+			if p < 0 || p >= test.n || seen[p] {
+				t.Fatalf("Expected a valid permutation but received %v", perm)
+			}
+			seen[p] = true
+		}
+
+		result := a.Permute(perm)
+		if bw := bandwidth(result); bw != test.bandwidth {
+			t.Logf("Expected bandwidth %d but received %d for ordering %v (original bandwidth %d)\n", test.bandwidth, bw, perm, bandwidth(a))
+			t.Fail()
+		}
+		if !result.IsSymmetric() {
+			t.Logf("Expected reordered matrix to be symmetric\n")
+			t.Fail()
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != mat.ErrShape {
+			t.Errorf("Expected panic with mat.ErrShape but received %v", r)
+		}
+	}()
+	RCM(CreateCSR(2, 3, nil).(*CSR))
+}
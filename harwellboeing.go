@@ -0,0 +1,402 @@
+package sparse
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrHarwellBoeingHeader is returned by ReadHarwellBoeing if the Harwell-Boeing header
+// lines are missing or malformed.
+var ErrHarwellBoeingHeader = errors.New("sparse: malformed Harwell-Boeing header")
+
+// hbFormatRE matches the simple Fortran format descriptors used for the data sections of
+// Harwell-Boeing files e.g. (10I8), (4E20.12), (1P,5D16.8) or (1P4E20.12E3) capturing the
+// repeat count, the descriptor type and the field width.
+var hbFormatRE = regexp.MustCompile(`^\(\s*(?:\d+P\s*,?\s*)?(\d*)\s*([IEDFG])\s*(\d+)(?:\.\d+)?(?:E\d+)?\s*\)$`)
+
+// hbFormat is a parsed Fortran format descriptor describing a fixed width field repeated
+// a number of times per line.
+type hbFormat struct {
+	repeat int
+	kind   byte
+	width  int
+}
+
+// parseHBFormat parses the Fortran format descriptor s.
+func parseHBFormat(s string) (hbFormat, error) {
+	m := hbFormatRE.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return hbFormat{}, fmt.Errorf("sparse: unsupported Harwell-Boeing format %q", s)
+	}
+	f := hbFormat{repeat: 1, kind: m[2][0]}
+	if m[1] != "" {
+		f.repeat, _ = strconv.Atoi(m[1])
+	}
+	f.width, _ = strconv.Atoi(m[3])
+	if f.repeat < 1 || f.width < 1 {
+		return hbFormat{}, fmt.Errorf("sparse: unsupported Harwell-Boeing format %q", s)
+	}
+	return f, nil
+}
+
+// hbField returns the trimmed, fixed width field of line starting at column begin, or an
+// empty string if line is too short.
+func hbField(line string, begin, width int) string {
+	if begin >= len(line) {
+		return ""
+	}
+	end := begin + width
+	if end > len(line) {
+		end = len(line)
+	}
+	return strings.TrimSpace(line[begin:end])
+}
+
+// hbInts parses n fixed width integer fields of the specified width from line starting at
+// column begin.  Missing (blank) fields are returned as zero.
+func hbInts(line string, begin, width, n int) ([]int, error) {
+	vals := make([]int, n)
+	for k := range vals {
+		s := hbField(line, begin+k*width, width)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			return nil, ErrHarwellBoeingHeader
+		}
+		vals[k] = v
+	}
+	return vals, nil
+}
+
+// parseHBFloat parses a Fortran formatted real value, accepting D as an exponent letter
+// along with exponents written without a letter e.g. 1.5-100.
+func parseHBFloat(s string) (float64, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == 'D' || r == 'd' {
+			return 'E'
+		}
+		return r
+	}, s)
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		if k := strings.LastIndexAny(s, "+-"); k > 0 && s[k-1] != 'E' && s[k-1] != 'e' {
+			return strconv.ParseFloat(s[:k]+"E"+s[k:], 64)
+		}
+	}
+	return v, err
+}
+
+// hbReader reads the fixed format lines of a Harwell-Boeing file.
+type hbReader struct {
+	scanner *bufio.Scanner
+	lineNo  int
+}
+
+// line returns the next line, without any trailing carriage return.
+func (r *hbReader) line() (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.ErrUnexpectedEOF
+	}
+	r.lineNo++
+	return strings.TrimRight(r.scanner.Text(), "\r"), nil
+}
+
+// fields reads n values formatted according to f from the next lines lines, calling
+// parse for each value.
+func (r *hbReader) fields(f hbFormat, lines, n int, parse func(k int, s string) error) error {
+	k := 0
+	for l := 0; l < lines; l++ {
+		line, err := r.line()
+		if err != nil {
+			return fmt.Errorf("sparse: line %d: %v", r.lineNo+1, err)
+		}
+		for p := 0; p < f.repeat && k < n; p++ {
+			s := hbField(line, p*f.width, f.width)
+			if s == "" {
+				break
+			}
+			if err := parse(k, s); err != nil {
+				return fmt.Errorf("sparse: line %d: invalid value %q", r.lineNo, s)
+			}
+			k++
+		}
+	}
+	if k != n {
+		return fmt.Errorf("sparse: expected %d Harwell-Boeing values but found %d", n, k)
+	}
+	return nil
+}
+
+// ReadHarwellBoeing reads a matrix in Harwell-Boeing exchange format from r and returns
+// it as a CSC matrix.  Harwell-Boeing files store matrices in compressed column form and
+// so the 1-based column pointers and row indices read from the file are loaded directly
+// as the storage of the returned matrix.  Real unsymmetric (RUA) and real symmetric
+// (RSA) assembled matrices are supported.  For symmetric matrices only the lower
+// triangle is stored in the file and so the mirrored upper triangular elements are
+// materialised in the returned matrix.  The data sections are parsed according to the
+// Fortran format descriptors in the header (e.g. (10I8) or (1P,4E20.12)) and any right
+// hand side vectors following the matrix are ignored.
+//
+// ReadHarwellBoeing returns an error if the header is malformed, specifies an unsupported
+// matrix type (e.g. complex, pattern or elemental matrices) or Fortran format, the file
+// is truncated, a value cannot be parsed or the column pointers or row indices are
+// inconsistent with the dimensions of the matrix.
+func ReadHarwellBoeing(r io.Reader) (*CSC, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	hb := &hbReader{scanner: scanner}
+
+	// title and key line
+	if _, err := hb.line(); err != nil {
+		return nil, ErrHarwellBoeingHeader
+	}
+
+	line, err := hb.line()
+	if err != nil {
+		return nil, ErrHarwellBoeingHeader
+	}
+	cards, err := hbInts(line, 0, 14, 5)
+	if err != nil {
+		return nil, err
+	}
+	ptrLines, indLines, valLines, rhsLines := cards[1], cards[2], cards[3], cards[4]
+
+	if line, err = hb.line(); err != nil {
+		return nil, ErrHarwellBoeingHeader
+	}
+	mxtype := strings.ToLower(hbField(line, 0, 3))
+	dims, err := hbInts(line, 14, 14, 4)
+	if err != nil {
+		return nil, err
+	}
+	rows, cols, nnz := dims[0], dims[1], dims[2]
+	switch mxtype {
+	case "rua":
+	case "rsa":
+		if rows != cols {
+			return nil, fmt.Errorf("sparse: symmetric Harwell-Boeing matrix must be square but is %dx%d", rows, cols)
+		}
+	default:
+		return nil, fmt.Errorf("sparse: unsupported Harwell-Boeing matrix type %q", mxtype)
+	}
+
+	if line, err = hb.line(); err != nil {
+		return nil, ErrHarwellBoeingHeader
+	}
+	ptrFmt, err := parseHBFormat(hbField(line, 0, 16))
+	if err != nil {
+		return nil, err
+	}
+	indFmt, err := parseHBFormat(hbField(line, 16, 16))
+	if err != nil {
+		return nil, err
+	}
+	valFmt, err := parseHBFormat(hbField(line, 32, 20))
+	if err != nil {
+		return nil, err
+	}
+	if ptrFmt.kind != 'I' || indFmt.kind != 'I' || valFmt.kind == 'I' {
+		return nil, errors.New("sparse: invalid Harwell-Boeing format descriptor types")
+	}
+	if rhsLines > 0 {
+		// right hand side header line
+		if _, err := hb.line(); err != nil {
+			return nil, ErrHarwellBoeingHeader
+		}
+	}
+
+	parseInt := func(dst []int) func(k int, s string) error {
+		return func(k int, s string) error {
+			v, err := strconv.Atoi(s)
+			dst[k] = v - 1
+			return err
+		}
+	}
+	indptr := make([]int, cols+1)
+	if err := hb.fields(ptrFmt, ptrLines, cols+1, parseInt(indptr)); err != nil {
+		return nil, err
+	}
+	ind := make([]int, nnz)
+	if err := hb.fields(indFmt, indLines, nnz, parseInt(ind)); err != nil {
+		return nil, err
+	}
+	data := make([]float64, nnz)
+	err = hb.fields(valFmt, valLines, nnz, func(k int, s string) (err error) {
+		data[k], err = parseHBFloat(s)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkIndptr(indptr, nnz); err != nil {
+		return nil, err
+	}
+	for j := 0; j < cols; j++ {
+		for k := indptr[j]; k < indptr[j+1]; k++ {
+			if i := ind[k]; i < 0 || i >= rows {
+				return nil, fmt.Errorf("sparse: Harwell-Boeing entry (%d, %d) out of range for %dx%d matrix", i+1, j+1, rows, cols)
+			} else if mxtype == "rsa" && i < j {
+				return nil, fmt.Errorf("sparse: Harwell-Boeing entry (%d, %d) above the diagonal of symmetric matrix", i+1, j+1)
+			}
+		}
+	}
+
+	if mxtype == "rsa" {
+		indptr, ind, data = mirrorLower(cols, indptr, ind, data)
+	}
+	return NewCSC(rows, cols, indptr, ind, data), nil
+}
+
+// mirrorLower returns the column pointers, row indices and values of the full n x n
+// symmetric matrix whose lower triangle is stored in compressed column form in indptr,
+// ind and data.  Within each column the mirrored upper triangular elements precede the
+// stored lower triangular elements.
+func mirrorLower(n int, indptr, ind []int, data []float64) ([]int, []int, []float64) {
+	full := make([]int, n+1)
+	for j := 0; j < n; j++ {
+		for k := indptr[j]; k < indptr[j+1]; k++ {
+			full[j+1]++
+			if i := ind[k]; i != j {
+				full[i+1]++
+			}
+		}
+	}
+	for j := 0; j < n; j++ {
+		full[j+1] += full[j]
+	}
+
+	next := make([]int, n)
+	copy(next, full)
+	fullInd := make([]int, full[n])
+	fullData := make([]float64, full[n])
+	for j := 0; j < n; j++ {
+		for k := indptr[j]; k < indptr[j+1]; k++ {
+			if i := ind[k]; i != j {
+				// element (j, i) of the upper triangle mirroring (i, j)
+				fullInd[next[i]], fullData[next[i]] = j, data[k]
+				next[i]++
+			}
+		}
+	}
+	for j := 0; j < n; j++ {
+		for k := indptr[j]; k < indptr[j+1]; k++ {
+			fullInd[next[j]], fullData[next[j]] = ind[k], data[k]
+			next[j]++
+		}
+	}
+	return full, fullInd, fullData
+}
+
+// WriteHarwellBoeing writes the matrix m to w in Harwell-Boeing format as a real
+// unsymmetric assembled (RUA) matrix with the specified title (up to 72 characters) and
+// key (up to 8 characters).  The column pointers and row indices are written with
+// integer formats wide enough for the largest value and the values with the Fortran
+// format (1P,3E26.17) so the output round-trips through ReadHarwellBoeing without loss
+// of precision.  Explicitly stored zero values are not written and duplicate entries
+// (as may be present in COO matrices) are summed.
+func WriteHarwellBoeing(w io.Writer, m mat.Matrix, title, key string) error {
+	return writeHarwellBoeing(w, m, title, key, false)
+}
+
+// WriteHarwellBoeingSymmetric writes the symmetric matrix m to w in Harwell-Boeing format
+// as a real symmetric assembled (RSA) matrix, storing only the elements on or below the
+// diagonal, which roughly halves the size of the output compared with WriteHarwellBoeing.
+// ReadHarwellBoeing will materialise the mirrored upper triangular elements when reading
+// the output.  WriteHarwellBoeingSymmetric returns an error, without writing anything, if
+// m is not square or not exactly symmetric.
+func WriteHarwellBoeingSymmetric(w io.Writer, m mat.Matrix, title, key string) error {
+	return writeHarwellBoeing(w, m, title, key, true)
+}
+
+// writeHarwellBoeing writes m to w in Harwell-Boeing format, writing only the lower
+// triangle with the RSA matrix type if lower is true.
+func writeHarwellBoeing(w io.Writer, m mat.Matrix, title, key string, lower bool) error {
+	r, c := m.Dims()
+	mxtype := "RUA"
+	if lower {
+		mxtype = "RSA"
+		if r != c {
+			return fmt.Errorf("sparse: symmetric Harwell-Boeing matrix must be square but is %dx%d", r, c)
+		}
+	}
+
+	// the transpose of m in CSR format stores the columns of m, canonicalised (sorted with
+	// duplicates summed) so that the symmetry check sees the logical value of each element
+	t := Repair(&CSR{matrix: cscOf(m).matrix})
+
+	indptr := make([]int, c+1)
+	var ind []int
+	var data []float64
+	for j := 0; j < c; j++ {
+		for k := t.matrix.Indptr[j]; k < t.matrix.Indptr[j+1]; k++ {
+			i, v := t.matrix.Ind[k], t.matrix.Data[k]
+			if v == 0 {
+				continue
+			}
+			if lower {
+				if t.At(i, j) != v {
+					return errors.New("sparse: matrix is not symmetric")
+				}
+				if i < j {
+					continue
+				}
+			}
+			ind = append(ind, i)
+			data = append(data, v)
+		}
+		indptr[j+1] = len(ind)
+	}
+	nnz := len(ind)
+
+	ptrWidth := len(strconv.Itoa(nnz+1)) + 1
+	indWidth := len(strconv.Itoa(r)) + 1
+	ptrFmt := hbFormat{repeat: 80 / ptrWidth, kind: 'I', width: ptrWidth}
+	indFmt := hbFormat{repeat: 80 / indWidth, kind: 'I', width: indWidth}
+	valFmt := hbFormat{repeat: 3, kind: 'E', width: 26}
+	lines := func(f hbFormat, n int) int {
+		return (n + f.repeat - 1) / f.repeat
+	}
+	ptrLines, indLines, valLines := lines(ptrFmt, c+1), lines(indFmt, nnz), lines(valFmt, nnz)
+
+	if len(title) > 72 {
+		title = title[:72]
+	}
+	if len(key) > 8 {
+		key = key[:8]
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%-72s%-8s\n", title, key)
+	fmt.Fprintf(bw, "%14d%14d%14d%14d%14d\n", ptrLines+indLines+valLines, ptrLines, indLines, valLines, 0)
+	fmt.Fprintf(bw, "%-3s%11s%14d%14d%14d%14d\n", mxtype, "", r, c, nnz, 0)
+	fmt.Fprintf(bw, "%-16s%-16s%-20s%-20s\n",
+		fmt.Sprintf("(%dI%d)", ptrFmt.repeat, ptrFmt.width),
+		fmt.Sprintf("(%dI%d)", indFmt.repeat, indFmt.width),
+		fmt.Sprintf("(1P,%dE%d.17)", valFmt.repeat, valFmt.width),
+		"")
+
+	writeFields := func(f hbFormat, n int, format func(k int) string) {
+		for k := 0; k < n; k++ {
+			fmt.Fprintf(bw, "%*s", f.width, format(k))
+			if (k+1)%f.repeat == 0 || k == n-1 {
+				bw.WriteByte('\n')
+			}
+		}
+	}
+	writeFields(ptrFmt, c+1, func(k int) string { return strconv.Itoa(indptr[k] + 1) })
+	writeFields(indFmt, nnz, func(k int) string { return strconv.Itoa(ind[k] + 1) })
+	writeFields(valFmt, nnz, func(k int) string { return strconv.FormatFloat(data[k], 'E', 17, 64) })
+	return bw.Flush()
+}
@@ -0,0 +1,280 @@
+package sparse
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// hbHeader returns Harwell-Boeing header lines with the specified fields laid out in
+// their fixed columns.
+func hbHeader(mxtype string, cards [5]int, rows, cols, nnz int, ptrFmt, indFmt, valFmt string) string {
+	return fmt.Sprintf("%-72s%-8s\n%14d%14d%14d%14d%14d\n%-3s%11s%14d%14d%14d%14d\n%-16s%-16s%-20s%-20s\n",
+		"Test matrix", "TEST", cards[0], cards[1], cards[2], cards[3], cards[4],
+		mxtype, "", rows, cols, nnz, 0, ptrFmt, indFmt, valFmt, "")
+}
+
+func TestReadHarwellBoeing(t *testing.T) {
+	var tests = []struct {
+		desc     string
+		input    string
+		r, c     int
+		expected []float64
+	}{
+		{
+			desc: "unsymmetric",
+			input: hbHeader("RUA", [5]int{5, 2, 1, 2, 0}, 3, 4, 4, "(3I4)", "(4I1)", "(1P,2D20.12)") +
+				"   1   3   3\n" +
+				"   4   5\n" +
+				"1323\n" +
+				"  1.500000000000D+00  3.000000000000D+02\n" +
+				" -2.000000000000D+00  4.000000000000D+00\n",
+			r: 3, c: 4,
+			expected: []float64{
+				1.5, 0, 0, 0,
+				0, 0, -2, 0,
+				300, 0, 0, 4,
+			},
+		},
+		{
+			desc: "symmetric with right hand side",
+			input: hbHeader("rsa", [5]int{5, 1, 1, 1, 2}, 3, 3, 4, "(4I3)", "(4I3)", "(4E10.3)") +
+				"F                          1             0\n" +
+				"  1  3  4  5\n" +
+				"  1  2  3  3\n" +
+				" 2.000E+00-1.000E+00-1.000E+00 2.000E+00\n" +
+				" 1.000E+00 2.000E+00 3.000E+00\n",
+			r: 3, c: 3,
+			expected: []float64{
+				2, -1, 0,
+				-1, 0, -1,
+				0, -1, 2,
+			},
+		},
+		{
+			desc: "exponents without letter and carriage returns",
+			input: strings.Replace(hbHeader("RUA", [5]int{3, 1, 1, 1, 0}, 2, 2, 2, "(3I2)", "(2I2)", "(2F12.0)"), "\n", "\r\n", -1) +
+				" 1 2 3\r\n" +
+				" 2 1\r\n" +
+				"     1.5-100     2.5+100\r\n",
+			r: 2, c: 2,
+			expected: []float64{
+				0, 2.5e100,
+				1.5e-100, 0,
+			},
+		},
+		{
+			desc:  "empty",
+			input: hbHeader("RUA", [5]int{1, 1, 0, 0, 0}, 2, 2, 0, "(3I2)", "(2I2)", "(2E12.4)") + " 1 1 1\n",
+			r:     2, c: 2,
+			expected: []float64{
+				0, 0,
+				0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		m, err := ReadHarwellBoeing(strings.NewReader(test.input))
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		if !mat.Equal(expected, m) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(m))
+			t.Fail()
+		}
+	}
+}
+
+func TestReadHarwellBoeingErrors(t *testing.T) {
+	valid := func(mxtype string, rows, cols int, ptr, ind, vals string) string {
+		return hbHeader(mxtype, [5]int{3, 1, 1, 1, 0}, rows, cols, 2, "(3I3)", "(2I3)", "(2E12.4)") +
+			ptr + "\n" + ind + "\n" + vals + "\n"
+	}
+
+	var tests = []struct {
+		desc  string
+		input string
+	}{
+		{"empty input", ""},
+		{"missing header lines", "Title\n             3             1             1             1             0\n"},
+		{"invalid card count", strings.Replace(valid("RUA", 2, 2, "  1  2  3", "  1  2", "  1.0000E+00  2.0000E+00"), "             3", "            x3", 1)},
+		{"complex matrix", valid("CUA", 2, 2, "  1  2  3", "  1  2", "  1.0000E+00  2.0000E+00")},
+		{"pattern matrix", valid("PUA", 2, 2, "  1  2  3", "  1  2", "  1.0000E+00  2.0000E+00")},
+		{"elemental matrix", valid("RUE", 2, 2, "  1  2  3", "  1  2", "  1.0000E+00  2.0000E+00")},
+		{"non-square symmetric", valid("RSA", 3, 2, "  1  2  3", "  1  2", "  1.0000E+00  2.0000E+00")},
+		{"unsupported format", strings.Replace(valid("RUA", 2, 2, "  1  2  3", "  1  2", "  1.0000E+00  2.0000E+00"), "(3I3)", "(3A3)", 1)},
+		{"real pointer format", strings.Replace(valid("RUA", 2, 2, "  1  2  3", "  1  2", "  1.0000E+00  2.0000E+00"), "(3I3)", "(3E3)", 1)},
+		{"truncated", strings.TrimSuffix(valid("RUA", 2, 2, "  1  2  3", "  1  2", ""), "\n\n")},
+		{"too few values", valid("RUA", 2, 2, "  1  2  3", "  1  2", "  1.0000E+00")},
+		{"invalid index", valid("RUA", 2, 2, "  1  2  3", "  1  x", "  1.0000E+00  2.0000E+00")},
+		{"invalid value", valid("RUA", 2, 2, "  1  2  3", "  1  2", "  1.0000E+00         abc")},
+		{"pointers not starting at one", valid("RUA", 2, 2, "  2  2  3", "  1  2", "  1.0000E+00  2.0000E+00")},
+		{"decreasing pointers", valid("RUA", 2, 2, "  1  4  3", "  1  2", "  1.0000E+00  2.0000E+00")},
+		{"index out of range", valid("RUA", 2, 2, "  1  2  3", "  1  3", "  1.0000E+00  2.0000E+00")},
+		{"zero index", valid("RUA", 2, 2, "  1  2  3", "  0  2", "  1.0000E+00  2.0000E+00")},
+		{"upper triangular symmetric entry", valid("RSA", 2, 2, "  1  2  3", "  2  1", "  1.0000E+00  2.0000E+00")},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		if _, err := ReadHarwellBoeing(strings.NewReader(test.input)); err == nil {
+			t.Logf("Expected error for %s but received none\n", test.desc)
+			t.Fail()
+		}
+	}
+}
+
+func TestWriteHarwellBoeing(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1.5, 0, 0, 0,
+				0, 0, -2, 0,
+				1.0 / 3, 0, 0, 4e-300,
+			},
+		},
+		{
+			r: 12, c: 3,
+			data: []float64{
+				1, 0, 0,
+				0, 2, 0,
+				0, 0, 3,
+				4, 0, 0,
+				0, -5e300, 0,
+				0, 0, 6,
+				7, 0, 0,
+				0, 8, 0,
+				0, 0, 9,
+				10, 0, 0,
+				0, 11, 0,
+				0, 0, 12,
+			},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.data)
+		for _, m := range []mat.Matrix{expected, CreateCSR(test.r, test.c, test.data), CreateCSC(test.r, test.c, test.data)} {
+			var buf bytes.Buffer
+			if err := WriteHarwellBoeing(&buf, m, "Test matrix", "TEST"); err != nil {
+				t.Errorf("Unexpected error writing: %v", err)
+				continue
+			}
+			for n, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+				if len(line) > 80 {
+					t.Errorf("Line %d exceeds 80 characters: %q", n+1, line)
+				}
+			}
+			result, err := ReadHarwellBoeing(&buf)
+			if err != nil {
+				t.Errorf("Unexpected error reading: %v", err)
+				continue
+			}
+			if !mat.Equal(expected, result) {
+				t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestWriteHarwellBoeingDuplicates(t *testing.T) {
+	coo := NewCOO(2, 2, []int{0, 1, 0, 1}, []int{0, 1, 0, 0}, []float64{1, 2, 3, 0})
+	var buf bytes.Buffer
+	if err := WriteHarwellBoeing(&buf, coo, "duplicates", "DUP"); err != nil {
+		t.Fatalf("Unexpected error writing: %v", err)
+	}
+	result, err := ReadHarwellBoeing(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error reading: %v", err)
+	}
+	if expected := mat.NewDense(2, 2, []float64{4, 0, 0, 2}); !mat.Equal(expected, result) || result.NNZ() != 2 {
+		t.Errorf("Expected:\n%v\n with 2 stored elements but received:\n%v\n with %d", mat.Formatted(expected), mat.Formatted(result), result.NNZ())
+	}
+}
+
+func TestWriteHarwellBoeingSymmetricDuplicates(t *testing.T) {
+	expected := mat.NewDense(2, 2, []float64{1, 2, 2, 0})
+	for _, m := range []mat.Matrix{
+		NewCSR(2, 2, []int{0, 2, 4}, []int{0, 1, 0, 0}, []float64{1, 2, 1, 1}),
+		NewCSC(2, 2, []int{0, 3, 4}, []int{0, 1, 1, 0}, []float64{1, 1, 1, 2}),
+		NewCOO(2, 2, []int{0, 0, 0, 1}, []int{1, 0, 1, 0}, []float64{1, 1, 1, 2}),
+	} {
+		var buf bytes.Buffer
+		if err := WriteHarwellBoeingSymmetric(&buf, m, "", ""); err != nil {
+			t.Fatalf("Unexpected error writing %T with duplicates: %v", m, err)
+		}
+		result, err := ReadHarwellBoeing(&buf)
+		if err != nil {
+			t.Fatalf("Unexpected error reading: %v", err)
+		}
+		if !mat.Equal(expected, result) {
+			t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+		}
+	}
+}
+
+func TestWriteHarwellBoeingSymmetric(t *testing.T) {
+	data := []float64{
+		2, -1, 0, 0,
+		-1, 2, -1, 0,
+		0, -1, 2, 1.0 / 3,
+		0, 0, 1.0 / 3, 2,
+	}
+	expected := mat.NewDense(4, 4, data)
+
+	var full, lower bytes.Buffer
+	if err := WriteHarwellBoeing(&full, expected, "", ""); err != nil {
+		t.Fatalf("Unexpected error writing: %v", err)
+	}
+	if err := WriteHarwellBoeingSymmetric(&lower, CreateCSR(4, 4, data), "", ""); err != nil {
+		t.Fatalf("Unexpected error writing: %v", err)
+	}
+	if !strings.Contains(lower.String(), "\nRSA ") {
+		t.Errorf("Expected RSA matrix type but received:\n%s", lower.String())
+	}
+	if lower.Len() >= full.Len() {
+		t.Errorf("Expected symmetric output to be smaller than full output (%d >= %d)", lower.Len(), full.Len())
+	}
+
+	result, err := ReadHarwellBoeing(&lower)
+	if err != nil {
+		t.Fatalf("Unexpected error reading: %v", err)
+	}
+	if !mat.Equal(expected, result) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHarwellBoeingSymmetric(&buf, mat.NewDense(2, 2, []float64{1, 2, 3, 4}), "", ""); err == nil {
+		t.Errorf("Expected error writing non-symmetric matrix")
+	}
+	if err := WriteHarwellBoeingSymmetric(&buf, mat.NewDense(2, 3, nil), "", ""); err == nil {
+		t.Errorf("Expected error writing non-square matrix")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing to be written on error but received %q", buf.String())
+	}
+}
@@ -0,0 +1,90 @@
+package sparse
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// ILU0 computes the zero fill-in incomplete LU factorisation, ILU(0), of the square matrix a
+// such that L * U approximates a, returning the factors as new CSR matrices.  l is unit lower
+// triangular (with its unit diagonal explicitly stored) and u is upper triangular.  Unlike a
+// complete LU factorisation, ILU(0) preserves the sparsity pattern of a: the stored elements
+// of l and u are exactly the stored elements of a below and on/above the main diagonal
+// respectively and any fill-in that a complete factorisation would introduce outside this
+// pattern is discarded.  Consequently L * U equals a at each stored element of a and, where a
+// complete factorisation introduces no fill-in (e.g. for tridiagonal matrices), the
+// factorisation is exact.  No pivoting is performed.
+//
+// ILU(0) is commonly used as a preconditioner for iterative solvers such as GMRES or BiCGSTAB
+// where applying the preconditioner M = L * U to a vector r involves solving L * y = r and
+// then U * z = y (see SolveLower and SolveUpper).  Duplicate elements of a are summed and the
+// column indices of each row of l and u are sorted.  mat.ErrSingular is returned if a zero pivot
+// is encountered, including where a diagonal element of a is not stored.  ILU0 will panic
+// with mat.ErrSquare if a is not square.
+func ILU0(a *CSR) (l, u *CSR, err error) {
+	n, c := a.Dims()
+	if n != c {
+		panic(mat.ErrSquare)
+	}
+
+	// factorise a sorted, deduplicated copy of a in place
+	lu := a.ToCOO().ToCSR()
+	lu.SortIndices()
+	indptr, ind, data := lu.matrix.Indptr, lu.matrix.Ind, lu.matrix.Data
+
+	diag := make([]int, n)
+	pos := getInts(n, false)
+	defer putInts(pos)
+	for j := range pos {
+		pos[j] = -1
+	}
+
+	for i := 0; i < n; i++ {
+		for k := indptr[i]; k < indptr[i+1]; k++ {
+			pos[ind[k]] = k
+		}
+
+		diag[i] = -1
+		for k := indptr[i]; k < indptr[i+1]; k++ {
+			col := ind[k]
+			if col >= i {
+				if col == i {
+					diag[i] = k
+				}
+				break
+			}
+			// eliminate element (i, col) using row col of U
+			data[k] /= data[diag[col]]
+			for p := diag[col] + 1; p < indptr[col+1]; p++ {
+				if q := pos[ind[p]]; q != -1 {
+					data[q] -= data[k] * data[p]
+				}
+			}
+		}
+		if diag[i] == -1 || data[diag[i]] == 0 {
+			return nil, nil, mat.ErrSingular
+		}
+
+		for k := indptr[i]; k < indptr[i+1]; k++ {
+			pos[ind[k]] = -1
+		}
+	}
+
+	// split into unit lower and upper triangular factors
+	lptr := make([]int, n+1)
+	uptr := make([]int, n+1)
+	var lind, uind []int
+	var ldata, udata []float64
+	for i := 0; i < n; i++ {
+		lind = append(lind, ind[indptr[i]:diag[i]]...)
+		ldata = append(ldata, data[indptr[i]:diag[i]]...)
+		lind = append(lind, i)
+		ldata = append(ldata, 1)
+		lptr[i+1] = len(lind)
+
+		uind = append(uind, ind[diag[i]:indptr[i+1]]...)
+		udata = append(udata, data[diag[i]:indptr[i+1]]...)
+		uptr[i+1] = len(uind)
+	}
+
+	return NewCSR(n, n, lptr, lind, ldata), NewCSR(n, n, uptr, uind, udata), nil
+}
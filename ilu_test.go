@@ -0,0 +1,151 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestILU0(t *testing.T) {
+	var tests = []struct {
+		desc  string
+		n     int
+		data  []float64
+		exact bool
+	}{
+		{
+			desc: "tridiagonal",
+			n:    5,
+			data: []float64{
+				4, -1, 0, 0, 0,
+				-1, 4, -1, 0, 0,
+				0, -1, 4, -1, 0,
+				0, 0, -1, 4, -1,
+				0, 0, 0, -1, 4,
+			},
+			exact: true,
+		},
+		{
+			desc: "arrow (no fill-in)",
+			n:    4,
+			data: []float64{
+				5, 0, 0, 1,
+				0, 5, 0, 2,
+				0, 0, 5, 3,
+				1, 2, 3, 5,
+			},
+			exact: true,
+		},
+		{
+			desc: "fill-in discarded",
+			n:    4,
+			data: []float64{
+				4, 1, 0, 1,
+				1, 4, 1, 0,
+				0, 1, 4, 1,
+				1, 0, 1, 4,
+			},
+			exact: false,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		a := CreateCSR(test.n, test.n, test.data).(*CSR)
+		l, u, err := ILU0(a)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for i := 0; i < test.n; i++ {
+			if l.At(i, i) != 1 {
+				t.Logf("Expected unit diagonal for L but received %v at %d\n", l.At(i, i), i)
+				t.Fail()
+			}
+			for j := 0; j < test.n; j++ {
+				if (j > i && l.At(i, j) != 0) || (j < i && u.At(i, j) != 0) {
+					t.Logf("Expected triangular factors\n")
+					t.Fail()
+				}
+			}
+		}
+
+		var product mat.Dense
+		product.Mul(l, u)
+
+		// L * U matches A at every stored element of A
+		a.DoNonZero(func(i, j int, v float64) {
+			if !floats.EqualWithinAbs(product.At(i, j), v, 1e-12) {
+				t.Logf("Expected (L*U)(%d, %d) = %v but received %v\n", i, j, v, product.At(i, j))
+				t.Fail()
+			}
+		})
+
+		if test.exact {
+			// compare with a complete dense LU factorisation
+			var lu mat.LU
+			lu.Factorize(mat.NewDense(test.n, test.n, test.data))
+			var denseL mat.TriDense
+			var denseU mat.TriDense
+			lu.LTo(&denseL)
+			lu.UTo(&denseU)
+			if !mat.EqualApprox(&denseL, l, 1e-12) || !mat.EqualApprox(&denseU, u, 1e-12) {
+				t.Logf("Expected L:\n%v\nU:\n%v\n but received L:\n%v\nU:\n%v\n",
+					mat.Formatted(&denseL), mat.Formatted(&denseU), mat.Formatted(l), mat.Formatted(u))
+				t.Fail()
+			}
+		} else if mat.EqualApprox(&product, a, 1e-12) {
+			t.Logf("Expected fill-in to be discarded such that L*U != A\n")
+			t.Fail()
+		}
+
+		// usable as a preconditioner with the triangular solves
+		b := make([]float64, test.n)
+		for i := range b {
+			b[i] = float64(i + 1)
+		}
+		z := SolveUpper(u, SolveLower(l, b))
+		r := make([]float64, test.n)
+		mat.NewVecDense(test.n, r).MulVec(&product, mat.NewVecDense(test.n, z))
+		if !floats.EqualApprox(r, b, 1e-12) {
+			t.Logf("Expected (L*U) * z = %v but received %v\n", b, r)
+			t.Fail()
+		}
+	}
+}
+
+func TestILU0ZeroPivot(t *testing.T) {
+	var tests = []struct {
+		n    int
+		data []float64
+	}{
+		{
+			// zero diagonal
+			n: 2,
+			data: []float64{
+				0, 1,
+				1, 1,
+			},
+		},
+		{
+			// pivot eliminated to zero
+			n: 2,
+			data: []float64{
+				1, 1,
+				1, 1,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		_, _, err := ILU0(CreateCSR(test.n, test.n, test.data).(*CSR))
+		if err != mat.ErrSingular {
+			t.Logf("Expected mat.ErrSingular but received %v\n", err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package sparse
+
+import (
+	"errors"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrAborted is returned by iterative solvers when iteration is stopped early by a
+// callback function (see WithCallback).
+var ErrAborted = errors.New("sparse: iteration aborted by callback")
+
+// SolverOption configures optional behaviour of the iterative solvers.
+type SolverOption func(*solverSettings)
+
+// solverSettings holds the optional settings for iterative solvers.
+type solverSettings struct {
+	callback func(iter int, residual float64) bool
+}
+
+// WithCallback returns a SolverOption registering the function fn to be called by the
+// solver at the end of each iteration with the iteration number (starting from 1) and
+// the norm of the residual of the current iterate.  This may be used to monitor
+// convergence, for example recording the residual history, or to implement custom
+// stopping rules - if fn returns false, the solver stops and returns the current
+// iterate along with ErrAborted (unless the iterate has already converged to within
+// tolerance).  As fn only receives copies of scalar values it has no access to, and so
+// cannot corrupt, the internal state of the solver.
+func WithCallback(fn func(iter int, residual float64) bool) SolverOption {
+	return func(s *solverSettings) {
+		s.callback = fn
+	}
+}
+
+// newSolverSettings returns the solver settings configured by opts.
+func newSolverSettings(opts []SolverOption) solverSettings {
+	var s solverSettings
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
+
+// CG solves the linear system a * x = b for x, where a is a symmetric positive definite
+// matrix, using the Conjugate Gradient method starting from the initial guess x0,
+// returning the solution and the number of iterations performed.  Each iteration requires
+// a single matrix vector product using the format specific sparse kernel of a where
+// available so a may be of any matrix type e.g. CSR, CSC or DIA.  Unlike CGFrom, x0 is not
+// modified by this call and may be nil, in which case iteration starts from the zero
+// vector.  Iteration stops once the norm of the residual ||b - a*x|| is less than or equal
+// to tol * ||b||.  If this does not happen within maxIter iterations, the current iterate
+// is returned along with ErrNotConverged.  Optional behaviour, such as a callback to
+// monitor convergence, may be configured with opts.  CG will panic if a is not square or if
+// the lengths of b or (non-nil) x0 do not match the dimensions of a.
+func CG(a mat.Matrix, b []float64, x0 []float64, tol float64, maxIter int, opts ...SolverOption) (x []float64, iters int, err error) {
+	n, _ := a.Dims()
+	x = make([]float64, n)
+	if x0 != nil {
+		if len(x0) != n {
+			panic(mat.ErrShape)
+		}
+		copy(x, x0)
+	}
+	return CGFrom(a, b, x, tol, maxIter, opts...)
+}
+
+// CGFrom solves the linear system a * x = b for x, where a is a symmetric positive
+// definite matrix, using the Conjugate Gradient method starting from the initial
+// guess x0.  Starting from a good initial guess, for example the solution of a
+// previous time step in a time stepping or continuation scheme, can dramatically
+// reduce the number of iterations required.  Each iteration requires a single
+// matrix vector product using the format specific sparse kernel of a where
+// available so a may be of any matrix type e.g. CSR, CSC or DIA.
+//
+// X0 is used in place as the storage for the iterates and so WILL be modified by
+// this call, the returned solution x sharing the same underlying storage as x0.
+// Callers wishing to preserve their initial guess should pass a copy.  Iteration
+// stops once the norm of the residual ||b - a*x|| is less than or equal to
+// tol * ||b|| returning the solution and the number of iterations performed.  If
+// this does not happen within maxIter iterations, the current iterate is returned
+// along with ErrNotConverged.  Optional behaviour, such as a callback to monitor
+// convergence, may be configured with opts.  CGFrom will panic if a is not square or
+// if the lengths of b or x0 do not match the dimensions of a.
+func CGFrom(a mat.Matrix, b []float64, x0 []float64, tol float64, maxIter int, opts ...SolverOption) (x []float64, iters int, err error) {
+	n, c := a.Dims()
+	if n != c || len(b) != n || len(x0) != n {
+		panic(mat.ErrShape)
+	}
+	x = x0
+	settings := newSolverSettings(opts)
+
+	r := make([]float64, n)
+	p := make([]float64, n)
+	ap := make([]float64, n)
+
+	// r = b - A*x
+	spmv(r, a, x)
+	floats.SubTo(r, b, r)
+
+	threshold := tol * floats.Norm(b, 2)
+	rr := floats.Dot(r, r)
+	if floats.Norm(r, 2) <= threshold {
+		return x, 0, nil
+	}
+	copy(p, r)
+
+	for iters = 1; iters <= maxIter; iters++ {
+		spmv(ap, a, p)
+		alpha := rr / floats.Dot(p, ap)
+		floats.AddScaled(x, alpha, p)
+		floats.AddScaled(r, -alpha, ap)
+
+		rrNext := floats.Dot(r, r)
+		rnorm := floats.Norm(r, 2)
+		proceed := settings.callback == nil || settings.callback(iters, rnorm)
+		if rnorm <= threshold {
+			return x, iters, nil
+		}
+		if !proceed {
+			return x, iters, ErrAborted
+		}
+
+		floats.AddScaledTo(p, r, rrNext/rr, p)
+		rr = rrNext
+	}
+
+	return x, maxIter, ErrNotConverged
+}
@@ -0,0 +1,203 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCGFrom(t *testing.T) {
+	var tests = []struct {
+		a mat.Matrix
+	}{
+		{a: laplacian1D(50)},
+		{a: laplacian1D(50).ToCSC()},
+		{a: NewDIA(5, 5, []float64{1, 2, 3, 4, 5})},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		n, _ := test.a.Dims()
+		expected := make([]float64, n)
+		for i := range expected {
+			expected[i] = float64(i%7) - 3
+		}
+		b := make([]float64, n)
+		spmv(b, test.a, expected)
+
+		x, iters, err := CGFrom(test.a, b, make([]float64, n), 1e-10, 1000)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		if !floats.EqualApprox(expected, x, 1e-8) {
+			t.Errorf("Expected solution %v but received %v", expected, x)
+		}
+		if iters > n {
+			t.Errorf("Expected convergence within %d iterations but took %d", n, iters)
+		}
+	}
+}
+
+func TestCG(t *testing.T) {
+	var tests = []struct {
+		a  mat.Matrix
+		x0 bool
+	}{
+		{a: laplacian1D(50)},
+		{a: laplacian1D(50).ToCSC(), x0: true},
+		{a: NewDIA(5, 5, []float64{1, 2, 3, 4, 5}), x0: true},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		n, _ := test.a.Dims()
+		expected := make([]float64, n)
+		for i := range expected {
+			expected[i] = float64(i%7) - 3
+		}
+		b := make([]float64, n)
+		spmv(b, test.a, expected)
+
+		var x0 []float64
+		if test.x0 {
+			x0 = make([]float64, n)
+			for i := range x0 {
+				x0[i] = 1
+			}
+		}
+
+		x, iters, err := CG(test.a, b, x0, 1e-10, 1000)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		if !floats.EqualApprox(expected, x, 1e-8) {
+			t.Errorf("Expected solution %v but received %v", expected, x)
+		}
+		if iters > n {
+			t.Errorf("Expected convergence within %d iterations but took %d", n, iters)
+		}
+		for i := range x0 {
+			if x0[i] != 1 {
+				t.Errorf("Expected x0 to be unmodified but received %v", x0)
+				break
+			}
+		}
+	}
+
+	b := make([]float64, 50)
+	for i := range b {
+		b[i] = 1
+	}
+	if _, iters, err := CG(laplacian1D(50), b, nil, 1e-12, 2); err != ErrNotConverged || iters != 2 {
+		t.Errorf("Expected ErrNotConverged after 2 iterations but received %v after %d", err, iters)
+	}
+}
+
+func TestCGFromWarmStart(t *testing.T) {
+	a := laplacian1D(100)
+	n, _ := a.Dims()
+
+	expected := make([]float64, n)
+	for i := range expected {
+		expected[i] = float64(i%7) - 3
+	}
+	b := make([]float64, n)
+	spmv(b, a, expected)
+
+	_, coldIters, err := CGFrom(a, b, make([]float64, n), 1e-10, 1000)
+	if err != nil {
+		t.Fatalf("Unexpected error for cold start: %v", err)
+	}
+
+	// warm start from a perturbation of the solution
+	x0 := make([]float64, n)
+	for i := range x0 {
+		x0[i] = expected[i] + 1e-4*float64(i%3-1)
+	}
+	x, warmIters, err := CGFrom(a, b, x0, 1e-10, 1000)
+	if err != nil {
+		t.Fatalf("Unexpected error for warm start: %v", err)
+	}
+	if &x[0] != &x0[0] {
+		t.Errorf("Expected solution to share storage with x0")
+	}
+	if !floats.EqualApprox(expected, x, 1e-6) {
+		t.Errorf("Expected solution %v but received %v", expected, x)
+	}
+	if warmIters >= coldIters {
+		t.Errorf("Expected warm start to converge in fewer iterations than cold start (%d) but took %d", coldIters, warmIters)
+	}
+
+	// already converged initial guess requires no iterations
+	if _, iters, _ := CGFrom(a, b, append([]float64(nil), expected...), 1e-10, 1000); iters != 0 {
+		t.Errorf("Expected 0 iterations from the exact solution but took %d", iters)
+	}
+}
+
+func TestCGFromNotConverged(t *testing.T) {
+	a := laplacian1D(50)
+	b := make([]float64, 50)
+	for i := range b {
+		b[i] = 1
+	}
+	if _, _, err := CGFrom(a, b, make([]float64, 50), 1e-12, 2); err != ErrNotConverged {
+		t.Errorf("Expected ErrNotConverged but received %v", err)
+	}
+}
+
+func TestCGFromCallback(t *testing.T) {
+	a := laplacian1D(50)
+	b := make([]float64, 50)
+	for i := range b {
+		b[i] = 1
+	}
+
+	var history []float64
+	var iterations []int
+	_, iters, err := CGFrom(a, b, make([]float64, 50), 1e-10, 1000, WithCallback(func(iter int, residual float64) bool {
+		iterations = append(iterations, iter)
+		history = append(history, residual)
+		return true
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(history) != iters {
+		t.Errorf("Expected callback to be called %d times but was called %d times", iters, len(history))
+	}
+	for i, iter := range iterations {
+		if iter != i+1 {
+			t.Errorf("Expected iteration %d but received %d", i+1, iter)
+		}
+	}
+
+	// final recorded residual should satisfy the convergence criterion
+	if final := history[len(history)-1]; final > 1e-10*floats.Norm(b, 2) {
+		t.Errorf("Expected final residual within tolerance but callback received %v", final)
+	}
+}
+
+func TestCGFromCallbackAbort(t *testing.T) {
+	a := laplacian1D(50)
+	b := make([]float64, 50)
+	for i := range b {
+		b[i] = 1
+	}
+
+	var calls int
+	_, iters, err := CGFrom(a, b, make([]float64, 50), 1e-10, 1000, WithCallback(func(iter int, residual float64) bool {
+		calls++
+		return iter < 3
+	}))
+	if err != ErrAborted {
+		t.Errorf("Expected ErrAborted but received %v", err)
+	}
+	if iters != 3 || calls != 3 {
+		t.Errorf("Expected abort after 3 iterations but ran %d iterations with %d callbacks", iters, calls)
+	}
+}
@@ -0,0 +1,50 @@
+package sparse
+
+import (
+	"github.com/james-bowman/sparse/blas"
+)
+
+// Iterator is a cursor over the stored non-zero elements of a sparse matrix providing a
+// pull based alternative to the callback based DoNonZero methods.  This allows
+// iteration to be driven by the consumer and stopped early simply by no longer calling
+// Next.  A new Iterator is positioned before the first element so Next must be called
+// before the first call to At e.g.
+//
+//	it := m.NonZeroIterator()
+//	for it.Next() {
+//		i, j, v := it.At()
+//		...
+//	}
+//
+// The matrix must not be structurally modified (e.g. by inserting new elements with Set)
+// while it is being iterated over.
+type Iterator struct {
+	matrix *blas.SparseMatrix
+	major  int
+	k      int
+}
+
+// NonZeroIterator returns a new Iterator over the stored non-zero elements of the
+// receiver visiting elements in row major order i.e. row by row with elements within
+// each row visited in the order they are stored.  No allocation is performed by calls
+// to the Iterator's Next or At methods.
+func (c *CSR) NonZeroIterator() *Iterator {
+	return &Iterator{matrix: &c.matrix, k: -1}
+}
+
+// Next advances the iterator to the next stored element returning true if there is
+// such an element or false if the iteration is complete.
+func (it *Iterator) Next() bool {
+	it.k++
+	for it.major < it.matrix.I && it.k >= it.matrix.Indptr[it.major+1] {
+		it.major++
+	}
+	return it.major < it.matrix.I
+}
+
+// At returns the row index, column index and value of the element at the current
+// position of the iterator.  At will panic if called before the first call to Next or
+// after Next has returned false.
+func (it *Iterator) At() (i, j int, v float64) {
+	return it.major, it.matrix.Ind[it.k], it.matrix.Data[it.k]
+}
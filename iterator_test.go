@@ -0,0 +1,103 @@
+package sparse
+
+import (
+	"testing"
+)
+
+func TestCSRNonZeroIterator(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 2, 0,
+				0, 0, 0, 0,
+				0, 3, 4, 5,
+			},
+		},
+		{
+			r: 4, c: 2,
+			data: []float64{
+				0, 0,
+				0, 1,
+				0, 0,
+				0, 0,
+			},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		csr := CreateCSR(test.r, test.c, test.data).(*CSR)
+
+		type element struct {
+			i, j int
+			v    float64
+		}
+		var expected, visited []element
+		csr.DoNonZero(func(i, j int, v float64) {
+			expected = append(expected, element{i, j, v})
+		})
+
+		it := csr.NonZeroIterator()
+		for it.Next() {
+			i, j, v := it.At()
+			visited = append(visited, element{i, j, v})
+		}
+		if it.Next() {
+			t.Logf("Expected Next to return false once iteration is complete\n")
+			t.Fail()
+		}
+
+		if len(visited) != len(expected) {
+			t.Logf("Expected %d elements but visited %d\n", len(expected), len(visited))
+			t.Fail()
+			continue
+		}
+		for k := range expected {
+			if visited[k] != expected[k] {
+				t.Logf("Expected element %d to be %v but received %v\n", k, expected[k], visited[k])
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestCSRNonZeroIteratorEarlyExit(t *testing.T) {
+	csr := CreateCSR(3, 3, []float64{
+		1, 0, 0,
+		0, 2, 0,
+		0, 0, 3,
+	}).(*CSR)
+
+	var sum float64
+	it := csr.NonZeroIterator()
+	for it.Next() {
+		i, _, v := it.At()
+		if i == 1 {
+			break
+		}
+		sum += v
+	}
+	if sum != 1 {
+		t.Errorf("Expected sum of 1 before early exit but received %v", sum)
+	}
+
+	if allocs := testing.AllocsPerRun(10, func() {
+		for it.Next() {
+			it.At()
+		}
+	}); allocs != 0 {
+		t.Errorf("Expected no allocations from Next and At but received %v", allocs)
+	}
+}
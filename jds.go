@@ -0,0 +1,232 @@
+package sparse
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Sparser    = (*JDS)(nil)
+	_ mulVecToer = (*JDS)(nil)
+)
+
+// JDS is a Jagged Diagonal Storage (also known as JAD - JAgged Diagonal) format sparse matrix
+// implementation and implements the Matrix interface from gonum/matrix.  The rows of the matrix are
+// permuted into order of descending number of non-zero elements and the d-th jagged diagonal is then
+// formed from the d-th stored element of each permuted row containing more than d elements.  As the
+// rows are sorted, the jagged diagonals are of non-increasing length and each is stored contiguously
+// with perm recording the original index of each permuted row, jdptr[d] to jdptr[d+1] indexing the
+// elements of the d-th jagged diagonal and ind and data holding the column index and value of each
+// element respectively.
+//
+// Matrix vector multiplication with JDS iterates over the jagged diagonals, each of which is a long,
+// contiguous and independent run of elements, suiting vectorised hardware where CSR would instead
+// iterate over many short rows.  Unlike ELL, JDS requires no padding and so remains compact where the
+// number of non-zero elements per row varies widely.
+type JDS struct {
+	r, c  int
+	perm  []int
+	jdptr []int
+	ind   []int
+	data  []float64
+}
+
+// NewJDS creates a new Jagged Diagonal Storage format sparse matrix with r rows and c columns.
+// perm is a permutation of the row indices 0 to r-1 where perm[k] is the original row index of the
+// k-th permuted row and jdptr, ind and data are the jagged diagonal pointers, column indices and
+// values respectively (see JDS).  The supplied slices are used as the backing storage of the matrix.
+// If perm, jdptr, ind and data are all nil, an empty matrix is created.  NewJDS will panic if the
+// dimensions are negative, perm is not a permutation of the rows, the slices are inconsistent with
+// each other, the jagged diagonals are not of non-increasing length or any column index is out of
+// range.
+func NewJDS(r, c int, perm, jdptr, ind []int, data []float64) *JDS {
+	if r < 0 {
+		panic(mat.ErrRowAccess)
+	}
+	if c < 0 {
+		panic(mat.ErrColAccess)
+	}
+	if perm == nil && jdptr == nil && ind == nil && data == nil {
+		perm = make([]int, r)
+		for i := range perm {
+			perm[i] = i
+		}
+		jdptr = []int{0}
+	}
+	checkPermutation(perm, r)
+	if len(jdptr) == 0 || jdptr[0] != 0 || jdptr[len(jdptr)-1] != len(ind) || len(ind) != len(data) {
+		panic(mat.ErrShape)
+	}
+	prev := r
+	for d := 1; d < len(jdptr); d++ {
+		n := jdptr[d] - jdptr[d-1]
+		if n < 0 || n > prev {
+			panic(mat.ErrShape)
+		}
+		prev = n
+	}
+	for _, j := range ind {
+		if j < 0 || j >= c {
+			panic(mat.ErrColAccess)
+		}
+	}
+
+	return &JDS{r: r, c: c, perm: perm, jdptr: jdptr, ind: ind, data: data}
+}
+
+// Dims returns the size of the matrix as the number of rows and columns
+func (m *JDS) Dims() (r, c int) {
+	return m.r, m.c
+}
+
+// At returns the element of the matrix located at row i and column j.  At will panic if specified values
+// for i or j fall outside the dimensions of the matrix.  As locating the row requires a search of the
+// row permutation, At is relatively slow and JDS matrices are better suited to matrix vector
+// multiplication than random access.
+func (m *JDS) At(i, j int) float64 {
+	if i < 0 || i >= m.r {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= m.c {
+		panic(mat.ErrColAccess)
+	}
+
+	var k int
+	for k = range m.perm {
+		if m.perm[k] == i {
+			break
+		}
+	}
+	for d := 0; d < len(m.jdptr)-1 && k < m.jdptr[d+1]-m.jdptr[d]; d++ {
+		if p := m.jdptr[d] + k; m.ind[p] == j {
+			return m.data[p]
+		}
+	}
+	return 0
+}
+
+// T transposes the matrix.  This is an implicit transpose, wrapping the matrix in a mat.Transpose type.
+func (m *JDS) T() mat.Matrix {
+	return mat.Transpose{Matrix: m}
+}
+
+// DoNonZero calls the function fn for each of the stored elements of the receiver.  The function fn
+// takes a row/column index and the element value of the receiver at (i, j).  The order of visiting
+// to each non-zero element is by jagged diagonal.
+func (m *JDS) DoNonZero(fn func(i, j int, v float64)) {
+	for d := 0; d < len(m.jdptr)-1; d++ {
+		for p := m.jdptr[d]; p < m.jdptr[d+1]; p++ {
+			fn(m.perm[p-m.jdptr[d]], m.ind[p], m.data[p])
+		}
+	}
+}
+
+// NNZ returns the Number of Non Zero elements in the sparse matrix.
+func (m *JDS) NNZ() int {
+	return len(m.data)
+}
+
+// Raw returns the row permutation, jagged diagonal pointers, column indices and values backing the
+// receiver.  The returned slices share storage with the receiver.
+func (m *JDS) Raw() (perm, jdptr, ind []int, data []float64) {
+	return m.perm, m.jdptr, m.ind, m.data
+}
+
+// FromCSR sets the receiver to a Jagged Diagonal Storage format copy of the CSR matrix a.  The rows
+// of a are sorted into order of descending number of stored elements, with rows containing the same
+// number of elements retaining their relative order, and the elements of each row are laid out along
+// the jagged diagonals in the order they are stored in a.  The receiver will not share underlying
+// storage with a.
+func (m *JDS) FromCSR(a *CSR) {
+	r, c := a.Dims()
+	rowNNZ := func(i int) int {
+		return a.matrix.Indptr[i+1] - a.matrix.Indptr[i]
+	}
+
+	perm := make([]int, r)
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(x, y int) bool { return rowNNZ(perm[x]) > rowNNZ(perm[y]) })
+
+	var ndiag int
+	if r > 0 {
+		ndiag = rowNNZ(perm[0])
+	}
+	jdptr := make([]int, ndiag+1)
+	ind := make([]int, a.NNZ())
+	data := make([]float64, a.NNZ())
+	p := 0
+	for d := 0; d < ndiag; d++ {
+		for _, i := range perm {
+			if rowNNZ(i) <= d {
+				break
+			}
+			q := a.matrix.Indptr[i] + d
+			ind[p] = a.matrix.Ind[q]
+			data[p] = a.matrix.Data[q]
+			p++
+		}
+		jdptr[d+1] = p
+	}
+
+	*m = JDS{r: r, c: c, perm: perm, jdptr: jdptr, ind: ind, data: data}
+}
+
+// ToCSR returns a CSR (Compressed Sparse Row)(AKA CRS (Compressed Row Storage)) sparse format
+// version of the matrix, inverting the row permutation so that the rows and the order of the
+// elements within each row match the CSR matrix the receiver was created from.  The returned CSR
+// matrix will not share underlying storage with the receiver nor is the receiver modified by this
+// call.
+func (m *JDS) ToCSR() *CSR {
+	indptr := make([]int, m.r+1)
+	for d := 0; d < len(m.jdptr)-1; d++ {
+		for k := 0; k < m.jdptr[d+1]-m.jdptr[d]; k++ {
+			indptr[m.perm[k]+1]++
+		}
+	}
+	for i := 0; i < m.r; i++ {
+		indptr[i+1] += indptr[i]
+	}
+
+	ind := make([]int, len(m.ind))
+	data := make([]float64, len(m.data))
+	for d := 0; d < len(m.jdptr)-1; d++ {
+		for p := m.jdptr[d]; p < m.jdptr[d+1]; p++ {
+			q := indptr[m.perm[p-m.jdptr[d]]] + d
+			ind[q] = m.ind[p]
+			data[q] = m.data[p]
+		}
+	}
+
+	return NewCSR(m.r, m.c, indptr, ind, data)
+}
+
+// MulVecTo performs matrix vector multiplication (dst+=A*x or dst+=A^T*x), where A is
+// the receiver, and stores the result in dst.  The product is accumulated one jagged
+// diagonal at a time, with each diagonal processed as a single contiguous run of
+// elements.  MulVecTo panics if ac != len(x) or ar != len(dst)
+func (m *JDS) MulVecTo(dst []float64, trans bool, x []float64) {
+	ar, ac := m.Dims()
+	if trans {
+		ar, ac = ac, ar
+	}
+	if ac != len(x) || ar != len(dst) {
+		panic(mat.ErrShape)
+	}
+
+	for d := 0; d < len(m.jdptr)-1; d++ {
+		begin, end := m.jdptr[d], m.jdptr[d+1]
+		perm := m.perm[:end-begin]
+		if trans {
+			for k, i := range perm {
+				dst[m.ind[begin+k]] += m.data[begin+k] * x[i]
+			}
+			continue
+		}
+		for k, i := range perm {
+			dst[i] += m.data[begin+k] * x[m.ind[begin+k]]
+		}
+	}
+}
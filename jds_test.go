@@ -0,0 +1,196 @@
+package sparse
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestJDS(t *testing.T) {
+	var tests = []struct {
+		r, c  int
+		data  []float64
+		perm  []int
+		jdptr []int
+		ind   []int
+		vals  []float64
+	}{
+		{
+			r: 4, c: 4,
+			data: []float64{
+				1, 0, 2, 0,
+				0, 0, 0, 0,
+				3, 4, 0, 5,
+				0, 6, 0, 0,
+			},
+			perm:  []int{2, 0, 3, 1},
+			jdptr: []int{0, 3, 5, 6},
+			ind:   []int{0, 0, 1, 1, 2, 3},
+			vals:  []float64{3, 1, 6, 4, 2, 5},
+		},
+		{
+			r: 2, c: 3,
+			data: []float64{
+				0, 7, 0,
+				8, 0, 9,
+			},
+			perm:  []int{1, 0},
+			jdptr: []int{0, 2, 3},
+			ind:   []int{0, 1, 2},
+			vals:  []float64{8, 7, 9},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+			perm:  []int{0, 1},
+			jdptr: []int{0},
+			ind:   []int{},
+			vals:  []float64{},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.data)
+		// scanning the dense matrix produces sorted column indices
+		csr := csrOf(expected)
+
+		jds, ok := csr.ToType(JDSFormat).(*JDS)
+		if !ok {
+			t.Errorf("Expected ToType(JDSFormat) to return a *JDS")
+			continue
+		}
+
+		if r, c := jds.Dims(); r != test.r || c != test.c {
+			t.Logf("Expected dimensions %dx%d but received %dx%d\n", test.r, test.c, r, c)
+			t.Fail()
+		}
+		perm, jdptr, ind, vals := jds.Raw()
+		if !reflect.DeepEqual(perm, test.perm) || !reflect.DeepEqual(jdptr, test.jdptr) ||
+			!reflect.DeepEqual(ind, test.ind) || !reflect.DeepEqual(vals, test.vals) {
+			t.Logf("Expected perm=%v, jdptr=%v, ind=%v, data=%v but received perm=%v, jdptr=%v, ind=%v, data=%v\n",
+				test.perm, test.jdptr, test.ind, test.vals, perm, jdptr, ind, vals)
+			t.Fail()
+		}
+		if !mat.Equal(expected, jds) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(jds))
+			t.Fail()
+		}
+		if jds.NNZ() != csr.NNZ() {
+			t.Logf("Expected %d non-zeros but found %d\n", csr.NNZ(), jds.NNZ())
+			t.Fail()
+		}
+
+		back := jds.ToCSR()
+		if !reflect.DeepEqual(back.RawMatrix().Indptr, csr.RawMatrix().Indptr) ||
+			!mat.Equal(expected, back) || back.NNZ() != csr.NNZ() {
+			t.Logf("ToCSR: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(back))
+			t.Fail()
+		}
+
+		fromRaw := NewJDS(test.r, test.c, test.perm, test.jdptr, test.ind, test.vals)
+		if !mat.Equal(expected, fromRaw) {
+			t.Logf("NewJDS: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(fromRaw))
+			t.Fail()
+		}
+	}
+}
+
+func TestJDSToType(t *testing.T) {
+	expected := mat.NewDense(3, 4, []float64{
+		1, 0, 2, 0,
+		0, 0, 0, -3,
+		4, 5, 0, 6,
+	})
+	for ti, from := range []TypeConverter{
+		CreateCSR(3, 4, expected.RawMatrix().Data).(*CSR),
+		CreateCSC(3, 4, expected.RawMatrix().Data).(*CSC),
+		CreateCOO(3, 4, expected.RawMatrix().Data).(*COO),
+		CreateDOK(3, 4, expected.RawMatrix().Data).(*DOK),
+	} {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		jds := from.ToType(JDSFormat)
+		if !mat.Equal(expected, jds) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(jds))
+			t.Fail()
+		}
+	}
+}
+
+func TestJDSMulVecTo(t *testing.T) {
+	a := mat.NewDense(4, 3, []float64{
+		1, 0, 2,
+		0, 0, 0,
+		3, 4, 5,
+		0, 6, 0,
+	})
+	var jds JDS
+	jds.FromCSR(csrOf(a))
+
+	x := []float64{1, 2, 3}
+	dst := []float64{1, 1, 1, 1}
+	jds.MulVecTo(dst, false, x)
+	var expected mat.VecDense
+	expected.MulVec(a, mat.NewVecDense(3, x))
+	expected.AddVec(&expected, mat.NewVecDense(4, []float64{1, 1, 1, 1}))
+	if !floats.Equal(dst, expected.RawVector().Data) {
+		t.Errorf("Expected %v but received %v", expected.RawVector().Data, dst)
+	}
+
+	xt := []float64{1, 2, 3, 4}
+	dst = make([]float64, 3)
+	jds.MulVecTo(dst, true, xt)
+	var expectedT mat.VecDense
+	expectedT.MulVec(a.T(), mat.NewVecDense(4, xt))
+	if !floats.Equal(dst, expectedT.RawVector().Data) {
+		t.Errorf("Expected %v but received %v", expectedT.RawVector().Data, dst)
+	}
+
+	// JDS should be used by the generic sparse matrix vector product
+	var vec mat.VecDense
+	vec.MulVec(a, mat.NewVecDense(3, x))
+	result := make([]float64, 4)
+	spmv(result, &jds, x)
+	if !floats.Equal(result, vec.RawVector().Data) {
+		t.Errorf("Expected %v but received %v", vec.RawVector().Data, result)
+	}
+}
+
+func TestNewJDSInvalid(t *testing.T) {
+	var tests = []struct {
+		desc  string
+		r, c  int
+		perm  []int
+		jdptr []int
+		ind   []int
+		data  []float64
+	}{
+		{"negative rows", -1, 2, nil, nil, nil, nil},
+		{"invalid permutation", 2, 2, []int{0, 0}, []int{0}, []int{}, []float64{}},
+		{"short perm", 2, 2, []int{0}, []int{0}, []int{}, []float64{}},
+		{"jdptr not ending at nnz", 2, 2, []int{0, 1}, []int{0, 1}, []int{0, 1}, []float64{1, 2}},
+		{"ind/data length mismatch", 2, 2, []int{0, 1}, []int{0, 1}, []int{0}, []float64{1, 2}},
+		{"increasing diagonal length", 2, 2, []int{0, 1}, []int{0, 1, 3}, []int{0, 1, 0}, []float64{1, 2, 3}},
+		{"diagonal longer than rows", 2, 3, []int{0, 1}, []int{0, 3}, []int{0, 1, 2}, []float64{1, 2, 3}},
+		{"column out of range", 2, 2, []int{0, 1}, []int{0, 1}, []int{2}, []float64{1}},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic for %s", test.desc)
+				}
+			}()
+			NewJDS(test.r, test.c, test.perm, test.jdptr, test.ind, test.data)
+		}()
+	}
+}
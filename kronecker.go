@@ -0,0 +1,39 @@
+package sparse
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// Kronecker returns the Kronecker product of the matrices a and b as a new CSR matrix.
+// If a is m x n and b is p x q then the result is the (m*p) x (n*q) block matrix
+// composed of m x n blocks where block (i, j) is a(i, j) * b.  Only the non-zero
+// elements of a and b are visited so the cost is proportional to NNZ(a) * NNZ(b)
+// rather than the size of the (dense) result.  Operands that are not CSR matrices are
+// first converted to CSR.  The column indices of each row of the result are sorted if
+// the column indices of each row of a and b are sorted.
+func Kronecker(a, b mat.Matrix) *CSR {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	lhs := csrOf(a)
+	rhs := csrOf(b)
+
+	nnz := lhs.NNZ() * rhs.NNZ()
+	indptr := make([]int, ar*br+1)
+	ind := make([]int, 0, nnz)
+	data := make([]float64, 0, nnz)
+
+	for i := 0; i < ar; i++ {
+		for bi := 0; bi < br; bi++ {
+			for k := lhs.matrix.Indptr[i]; k < lhs.matrix.Indptr[i+1]; k++ {
+				offset, v := lhs.matrix.Ind[k]*bc, lhs.matrix.Data[k]
+				for bk := rhs.matrix.Indptr[bi]; bk < rhs.matrix.Indptr[bi+1]; bk++ {
+					ind = append(ind, offset+rhs.matrix.Ind[bk])
+					data = append(data, v*rhs.matrix.Data[bk])
+				}
+			}
+			indptr[i*br+bi+1] = len(ind)
+		}
+	}
+
+	return NewCSR(ar*br, ac*bc, indptr, ind, data)
+}
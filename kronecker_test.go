@@ -0,0 +1,126 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// denseKronecker computes the Kronecker product of a and b as a dense reference.
+func denseKronecker(a, b mat.Matrix) *mat.Dense {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	k := mat.NewDense(ar*br, ac*bc, nil)
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			block := k.Slice(i*br, (i+1)*br, j*bc, (j+1)*bc).(*mat.Dense)
+			block.Scale(a.At(i, j), b)
+		}
+	}
+	return k
+}
+
+func TestKronecker(t *testing.T) {
+	var tests = []struct {
+		ar, ac int
+		a      []float64
+		br, bc int
+		b      []float64
+	}{
+		{
+			ar: 2, ac: 2,
+			a: []float64{
+				1, 2,
+				3, 4,
+			},
+			br: 2, bc: 2,
+			b: []float64{
+				0, 5,
+				6, 7,
+			},
+		},
+		{
+			ar: 2, ac: 3,
+			a: []float64{
+				1, 0, -2,
+				0, 0, 3,
+			},
+			br: 3, bc: 2,
+			b: []float64{
+				1, 0,
+				0, 0,
+				4, -1,
+			},
+		},
+		{
+			ar: 3, ac: 3,
+			a: []float64{
+				2, -1, 0,
+				-1, 2, -1,
+				0, -1, 2,
+			},
+			br: 2, bc: 2,
+			b: []float64{
+				1, 0,
+				0, 1,
+			},
+		},
+		{
+			ar: 1, ac: 3,
+			a: []float64{
+				0, 0, 0,
+			},
+			br: 2, bc: 1,
+			b: []float64{
+				1,
+				2,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := mat.NewDense(test.ar, test.ac, test.a)
+		b := mat.NewDense(test.br, test.bc, test.b)
+		expected := denseKronecker(a, b)
+
+		var nnzA, nnzB int
+		for _, v := range test.a {
+			if v != 0 {
+				nnzA++
+			}
+		}
+		for _, v := range test.b {
+			if v != 0 {
+				nnzB++
+			}
+		}
+
+		operands := []struct {
+			desc string
+			a, b mat.Matrix
+		}{
+			{"CSR (x) CSR", CreateCSR(test.ar, test.ac, test.a), CreateCSR(test.br, test.bc, test.b)},
+			{"CSC (x) Dense", CreateCSC(test.ar, test.ac, test.a), b},
+			{"Dense (x) COO", a, CreateCOO(test.br, test.bc, test.b)},
+		}
+
+		for _, op := range operands {
+			result := Kronecker(op.a, op.b)
+
+			if r, c := result.Dims(); r != test.ar*test.br || c != test.ac*test.bc {
+				t.Logf("%s: Expected dimensions %dx%d but received %dx%d\n", op.desc, test.ar*test.br, test.ac*test.bc, r, c)
+				t.Fail()
+			}
+			if !mat.Equal(expected, result) {
+				t.Logf("%s: Expected:\n%v\n but received:\n%v\n", op.desc, mat.Formatted(expected), mat.Formatted(result))
+				t.Fail()
+			}
+			if result.NNZ() != nnzA*nnzB {
+				t.Logf("%s: Expected %d non-zeros but found %d\n", op.desc, nnzA*nnzB, result.NNZ())
+				t.Fail()
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package sparse
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Sparser       = (*LIL)(nil)
+	_ TypeConverter = (*LIL)(nil)
+	_ mat.Mutable   = (*LIL)(nil)
+)
+
+// lilRow is a single row of a LIL matrix storing the column indices of the non-zero
+// elements of the row, in ascending order, along with their corresponding values.
+type lilRow struct {
+	ind  []int
+	data []float64
+}
+
+// LIL is a List of Lists format sparse matrix implementation and implements the Matrix interface
+// from gonum/matrix.  Each row of the matrix is stored as a list of column indices, kept sorted in
+// ascending order, along with the corresponding element values.  Like DOK, LIL matrices are good for
+// incrementally constructing sparse matrices but, unlike DOK, elements of the same row are stored
+// contiguously giving good locality for row oriented assembly.  As the rows are already sorted,
+// conversion to CSR format is a straightforward concatenation of the rows.  Setting an element uses a
+// binary search to locate its position within the row and so inserting into the middle of long rows
+// requires shifting the subsequent elements of the row.  LIL matrices are poor for arithmetic
+// operations and so should be converted to CSR or CSC format once constructed.
+type LIL struct {
+	r    int
+	c    int
+	rows []lilRow
+}
+
+// NewLIL creates a new List of Lists format sparse matrix initialised to the size of the specified
+// r * c dimensions (rows * columns)
+func NewLIL(r, c int) *LIL {
+	if r < 0 {
+		panic(mat.ErrRowAccess)
+	}
+	if c < 0 {
+		panic(mat.ErrColAccess)
+	}
+
+	return &LIL{r: r, c: c, rows: make([]lilRow, r)}
+}
+
+// Dims returns the size of the matrix as the number of rows and columns
+func (l *LIL) Dims() (r, c int) {
+	return l.r, l.c
+}
+
+// At returns the element of the matrix located at row i and column j.  At will panic if specified values
+// for i or j fall outside the dimensions of the matrix.
+func (l *LIL) At(i, j int) float64 {
+	if i < 0 || i >= l.r {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= l.c {
+		panic(mat.ErrColAccess)
+	}
+
+	row := &l.rows[i]
+	if k := sort.SearchInts(row.ind, j); k < len(row.ind) && row.ind[k] == j {
+		return row.data[k]
+	}
+	return 0
+}
+
+// T transposes the matrix.  This is an implicit transpose, wrapping the matrix in a mat.Transpose type.
+func (l *LIL) T() mat.Matrix {
+	return mat.Transpose{Matrix: l}
+}
+
+// Set sets the element of the matrix located at row i and column j to equal the specified value, v.  The
+// position of the element within row i is located using a binary search and the element inserted at that
+// position, if not already present, keeping the row sorted.  Setting an element to zero removes it from
+// the matrix.  Set will panic if specified values for i or j fall outside the dimensions of the matrix.
+func (l *LIL) Set(i, j int, v float64) {
+	if i < 0 || i >= l.r {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || j >= l.c {
+		panic(mat.ErrColAccess)
+	}
+
+	row := &l.rows[i]
+	k := sort.SearchInts(row.ind, j)
+	if k < len(row.ind) && row.ind[k] == j {
+		if v == 0 {
+			row.ind = append(row.ind[:k], row.ind[k+1:]...)
+			row.data = append(row.data[:k], row.data[k+1:]...)
+			return
+		}
+		row.data[k] = v
+		return
+	}
+	if v == 0 {
+		return
+	}
+
+	row.ind = append(row.ind, 0)
+	row.data = append(row.data, 0)
+	copy(row.ind[k+1:], row.ind[k:])
+	copy(row.data[k+1:], row.data[k:])
+	row.ind[k] = j
+	row.data[k] = v
+}
+
+// DoNonZero calls the function fn for each of the non-zero elements of the receiver.
+// The function fn takes a row/column index and the element value of the receiver at
+// (i, j).  The elements are visited in row major order.
+func (l *LIL) DoNonZero(fn func(i, j int, v float64)) {
+	for i := range l.rows {
+		row := &l.rows[i]
+		for k, j := range row.ind {
+			fn(i, j, row.data[k])
+		}
+	}
+}
+
+// NNZ returns the Number of Non Zero elements in the sparse matrix.
+func (l *LIL) NNZ() int {
+	var nnz int
+	for i := range l.rows {
+		nnz += len(l.rows[i].ind)
+	}
+	return nnz
+}
+
+// ToDense returns a mat.Dense dense format version of the matrix.  The returned mat.Dense
+// matrix will not share underlying storage with the receiver nor is the receiver modified by this call.
+func (l *LIL) ToDense() *mat.Dense {
+	dense := mat.NewDense(l.r, l.c, nil)
+	l.DoNonZero(dense.Set)
+	return dense
+}
+
+// ToDOK returns a DOK (Dictionary Of Keys) sparse format version of the matrix.  The returned DOK
+// matrix will not share underlying storage with the receiver nor is the receiver modified by this call.
+func (l *LIL) ToDOK() *DOK {
+	dok := NewDOK(l.r, l.c)
+	l.DoNonZero(dok.Set)
+	return dok
+}
+
+// ToCOO returns a COOrdinate sparse format version of the matrix.  The returned COO matrix will
+// not share underlying storage with the receiver nor is the receiver modified by this call.
+func (l *LIL) ToCOO() *COO {
+	nnz := l.NNZ()
+	rows := make([]int, 0, nnz)
+	cols := make([]int, 0, nnz)
+	data := make([]float64, 0, nnz)
+
+	for i := range l.rows {
+		row := &l.rows[i]
+		for range row.ind {
+			rows = append(rows, i)
+		}
+		cols = append(cols, row.ind...)
+		data = append(data, row.data...)
+	}
+
+	return NewCOO(l.r, l.c, rows, cols, data)
+}
+
+// ToCSR returns a CSR (Compressed Sparse Row)(AKA CRS (Compressed Row Storage)) sparse format
+// version of the matrix.  As the rows of the receiver are already sorted, the conversion simply
+// concatenates the rows and the column indices of each row of the returned CSR matrix will be sorted.
+// The returned CSR matrix will not share underlying storage with the receiver nor is the receiver
+// modified by this call.
+func (l *LIL) ToCSR() *CSR {
+	nnz := l.NNZ()
+	indptr := make([]int, l.r+1)
+	ind := make([]int, 0, nnz)
+	data := make([]float64, 0, nnz)
+
+	for i := range l.rows {
+		ind = append(ind, l.rows[i].ind...)
+		data = append(data, l.rows[i].data...)
+		indptr[i+1] = len(ind)
+	}
+
+	return NewCSR(l.r, l.c, indptr, ind, data)
+}
+
+// ToCSC returns a CSC (Compressed Sparse Column)(AKA CCS (Compressed Column Storage)) sparse format
+// version of the matrix.  The returned CSC matrix will not share underlying storage with the
+// receiver nor is the receiver modified by this call.
+func (l *LIL) ToCSC() *CSC {
+	return l.ToCOO().ToCSCReuseMem()
+}
+
+// ToType returns an alternative format version fo the matrix in the format specified.
+func (l *LIL) ToType(matType MatrixType) mat.Matrix {
+	return matType.Convert(l)
+}
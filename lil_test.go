@@ -0,0 +1,122 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLIL(t *testing.T) {
+	var tests = []struct {
+		r, c     int
+		sets     []struct{ i, j int }
+		values   []float64
+		expected []float64
+		nnz      int
+	}{
+		{ // inserted out of order within rows
+			r: 3, c: 4,
+			sets:   []struct{ i, j int }{{0, 3}, {0, 0}, {2, 2}, {0, 1}, {2, 0}, {1, 3}},
+			values: []float64{4, 1, 7, 2, 5, 3},
+			expected: []float64{
+				1, 2, 0, 4,
+				0, 0, 0, 3,
+				5, 0, 7, 0,
+			},
+			nnz: 6,
+		},
+		{ // overwriting and removing elements
+			r: 2, c: 3,
+			sets:   []struct{ i, j int }{{0, 1}, {0, 1}, {1, 0}, {1, 2}, {1, 0}, {0, 0}},
+			values: []float64{1, 9, 2, 3, 0, 0},
+			expected: []float64{
+				0, 9, 0,
+				0, 0, 3,
+			},
+			nnz: 2,
+		},
+		{ // empty
+			r: 2, c: 2,
+			expected: []float64{
+				0, 0,
+				0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		lil := NewLIL(test.r, test.c)
+		for k, s := range test.sets {
+			lil.Set(s.i, s.j, test.values[k])
+		}
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+
+		if !mat.Equal(expected, lil) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(lil))
+			t.Fail()
+		}
+		if lil.NNZ() != test.nnz {
+			t.Logf("Expected %d non-zeros but found %d\n", test.nnz, lil.NNZ())
+			t.Fail()
+		}
+		for i := range lil.rows {
+			for k := 1; k < len(lil.rows[i].ind); k++ {
+				if lil.rows[i].ind[k] <= lil.rows[i].ind[k-1] {
+					t.Logf("Row %d not sorted: %v\n", i, lil.rows[i].ind)
+					t.Fail()
+				}
+			}
+		}
+
+		csr := lil.ToCSR()
+		if !mat.Equal(expected, csr) {
+			t.Logf("ToCSR: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csr))
+			t.Fail()
+		}
+		if errs := Validate(csr); errs != nil {
+			t.Logf("ToCSR: Expected well formed CSR matrix but found: %v\n", errs)
+			t.Fail()
+		}
+
+		for _, m := range []mat.Matrix{lil.ToCOO(), lil.ToCSC(), lil.ToDOK(), lil.ToDense(), lil.ToType(CSRFormat)} {
+			if !mat.Equal(expected, m) {
+				t.Logf("%T: Expected:\n%v\n but received:\n%v\n", m, mat.Formatted(expected), mat.Formatted(m))
+				t.Fail()
+			}
+		}
+
+		// conversion must not share storage with the receiver
+		if test.nnz > 0 {
+			csr.RawMatrix().Data[0] = -100
+			if !mat.Equal(expected, lil) {
+				t.Logf("Modifying converted CSR modified the LIL matrix\n")
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestLILOutOfRange(t *testing.T) {
+	lil := NewLIL(2, 3)
+	for _, idx := range []struct{ i, j int }{{-1, 0}, {2, 0}, {0, -1}, {0, 3}} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic setting (%d, %d)", idx.i, idx.j)
+				}
+			}()
+			lil.Set(idx.i, idx.j, 1)
+		}()
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic getting (%d, %d)", idx.i, idx.j)
+				}
+			}()
+			lil.At(idx.i, idx.j)
+		}()
+	}
+}
@@ -84,6 +84,17 @@ func (s CSCType) Convert(from TypeConverter) mat.Matrix {
 	return from.ToCSC()
 }
 
+// JDSType represents the JDS (Jagged Diagonal Storage) matrix type format
+type JDSType int
+
+// Convert converts the specified TypeConverter to JDS (Jagged Diagonal Storage) format via
+// CSR format
+func (s JDSType) Convert(from TypeConverter) mat.Matrix {
+	var m JDS
+	m.FromCSR(from.ToCSR())
+	return &m
+}
+
 const (
 	// DenseFormat is an enum value representing Dense matrix format
 	DenseFormat DenseType = iota
@@ -99,6 +110,9 @@ const (
 
 	// CSCFormat is an enum value representing CSC matrix format
 	CSCFormat CSCType = iota
+
+	// JDSFormat is an enum value representing JDS matrix format
+	JDSFormat JDSType = iota
 )
 
 // Random constructs a new matrix of the specified type e.g. Dense, COO, CSR, etc.
@@ -123,6 +137,12 @@ func Random(t MatrixType, r int, c int, density float32) mat.Matrix {
 	return NewCOO(r, c, m, n, data).ToType(t)
 }
 
+// sameDims reports whether the matrix m has dimensions r x c.
+func sameDims(m mat.Matrix, r, c int) bool {
+	mr, mc := m.Dims()
+	return mr == r && mc == c
+}
+
 // alias reports whether x and y share the same base array.
 func aliasFloats(x, y []float64) bool {
 	return cap(x) > 0 && cap(y) > 0 && &x[0:cap(x)][cap(x)-1] == &y[0:cap(y)][cap(y)-1]
@@ -189,6 +209,32 @@ func Norm(m mat.Matrix, L float64) float64 {
 	return mat.Norm(m, L)
 }
 
+// mulVecToer is an interface for matrix formats providing a format specific (sparse)
+// matrix vector multiplication kernel (dst+=A*x or dst+=A^T*x).
+type mulVecToer interface {
+	MulVecTo(dst []float64, trans bool, x []float64)
+}
+
+// spmv computes the matrix vector product dst = A * x overwriting the contents of dst.
+// If the matrix a implements a format specific MulVecTo method, it will be used to
+// perform the multiplication only processing the non-zero elements of a, otherwise
+// the multiplication is delegated to Gonum.  spmv panics if dst and x are not the
+// correct lengths for a.
+func spmv(dst []float64, a mat.Matrix, x []float64) {
+	for i := range dst {
+		dst[i] = 0
+	}
+	if m, ok := a.(mulVecToer); ok {
+		m.MulVecTo(dst, false, x)
+		return
+	}
+	r, c := a.Dims()
+	if c != len(x) || r != len(dst) {
+		panic(mat.ErrShape)
+	}
+	mat.NewVecDense(r, dst).MulVec(a, mat.NewVecDense(c, x))
+}
+
 // BlasCompatibleSparser is an interface which represents Sparse matrices compatible with
 // sparse BLAS routines i.e. implementing the RawMatrix() method as a means of obtaining
 // a BLAS sparse matrix representation of the matrix.
@@ -0,0 +1,303 @@
+package sparse
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// mmBanner is the prefix of the first line of every MatrixMarket file.
+const mmBanner = "%%MatrixMarket"
+
+// ErrMatrixMarketHeader is returned by ReadMatrixMarket if the MatrixMarket banner line
+// is missing or malformed.
+var ErrMatrixMarketHeader = errors.New("sparse: malformed MatrixMarket header")
+
+// mmHeader holds the qualifiers parsed from a MatrixMarket banner line.
+type mmHeader struct {
+	format   string // coordinate or array
+	field    string // real, integer or pattern
+	symmetry string // general, symmetric or skew-symmetric
+}
+
+// parseMMBanner parses the MatrixMarket banner line e.g.
+// "%%MatrixMarket matrix coordinate real general".  Qualifiers are case insensitive.
+func parseMMBanner(line string) (mmHeader, error) {
+	var h mmHeader
+	tokens := strings.Fields(line)
+	if len(tokens) != 5 || tokens[0] != mmBanner || !strings.EqualFold(tokens[1], "matrix") {
+		return h, ErrMatrixMarketHeader
+	}
+	h.format = strings.ToLower(tokens[2])
+	h.field = strings.ToLower(tokens[3])
+	h.symmetry = strings.ToLower(tokens[4])
+
+	switch h.format {
+	case "coordinate", "array":
+	default:
+		return h, fmt.Errorf("sparse: unsupported MatrixMarket format %q", tokens[2])
+	}
+	switch h.field {
+	case "real", "integer":
+	case "pattern":
+		if h.format == "array" {
+			return h, errors.New("sparse: MatrixMarket pattern field is not valid for array format")
+		}
+	default:
+		return h, fmt.Errorf("sparse: unsupported MatrixMarket field %q", tokens[3])
+	}
+	switch h.symmetry {
+	case "general", "symmetric", "skew-symmetric":
+	default:
+		return h, fmt.Errorf("sparse: unsupported MatrixMarket symmetry %q", tokens[4])
+	}
+	return h, nil
+}
+
+// ReadMatrixMarket reads a matrix in MatrixMarket exchange format from r and returns it as
+// a COO matrix.  Both the sparse coordinate and dense array formats are supported with
+// real, integer or (for coordinate format only) pattern fields.  Pattern entries are
+// given the value 1.  Lines beginning with % following the banner line are treated as
+// comments and skipped along with blank lines.  For matrices with the symmetric or
+// skew-symmetric qualifier only the lower triangle is stored in the file and so the
+// mirrored upper triangular elements (negated for skew-symmetric matrices) are
+// materialised in the returned matrix.  Zero values in array format files are not
+// stored in the returned matrix.
+//
+// ReadMatrixMarket returns an error if the header is malformed, specifies an
+// unsupported format (e.g. complex or hermitian matrices), the number of entries does
+// not match the size line or an entry is out of range or cannot be parsed.
+func ReadMatrixMarket(r io.Reader) (*COO, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrMatrixMarketHeader
+	}
+	header, err := parseMMBanner(scanner.Text())
+	if err != nil {
+		return nil, err
+	}
+
+	lineNo := 1
+	nextLine := func() ([]string, bool) {
+		for scanner.Scan() {
+			lineNo++
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || line[0] == '%' {
+				continue
+			}
+			return strings.Fields(line), true
+		}
+		return nil, false
+	}
+
+	sizes, ok := nextLine()
+	if !ok {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("sparse: missing MatrixMarket size line")
+	}
+	expectedSizes := 3
+	if header.format == "array" {
+		expectedSizes = 2
+	}
+	if len(sizes) != expectedSizes {
+		return nil, fmt.Errorf("sparse: line %d: expected %d values in MatrixMarket size line but found %d", lineNo, expectedSizes, len(sizes))
+	}
+	dims := make([]int, len(sizes))
+	for i, s := range sizes {
+		if dims[i], err = strconv.Atoi(s); err != nil || dims[i] < 0 {
+			return nil, fmt.Errorf("sparse: line %d: invalid MatrixMarket size %q", lineNo, s)
+		}
+	}
+	rows, cols := dims[0], dims[1]
+	if header.symmetry != "general" && rows != cols {
+		return nil, fmt.Errorf("sparse: %s MatrixMarket matrix must be square but is %dx%d", header.symmetry, rows, cols)
+	}
+
+	// the number of entries stored in the file
+	var entries int
+	if header.format == "coordinate" {
+		entries = dims[2]
+	} else {
+		switch header.symmetry {
+		case "general":
+			entries = rows * cols
+		case "symmetric":
+			entries = rows * (rows + 1) / 2
+		case "skew-symmetric":
+			entries = rows * (rows - 1) / 2
+		}
+	}
+
+	sign := 1.0
+	if header.symmetry == "skew-symmetric" {
+		sign = -1
+	}
+	coo := NewCOO(rows, cols, nil, nil, nil)
+	add := func(i, j int, v float64) {
+		coo.Set(i, j, v)
+		if header.symmetry != "general" && i != j {
+			coo.Set(j, i, sign*v)
+		}
+	}
+
+	// array format values are stored in column major order starting from the diagonal
+	// (or below it for skew-symmetric matrices) when only the lower triangle is stored
+	firstRow := func(j int) int {
+		switch header.symmetry {
+		case "symmetric":
+			return j
+		case "skew-symmetric":
+			return j + 1
+		}
+		return 0
+	}
+	ai, aj := firstRow(0), 0
+	for n := 0; n < entries; n++ {
+		fields, ok := nextLine()
+		if !ok {
+			if err := scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("sparse: expected %d MatrixMarket entries but found %d", entries, n)
+		}
+
+		if header.format == "array" {
+			if len(fields) != 1 {
+				return nil, fmt.Errorf("sparse: line %d: expected a single value but found %d", lineNo, len(fields))
+			}
+			v, err := strconv.ParseFloat(fields[0], 64)
+			if err != nil {
+				return nil, fmt.Errorf("sparse: line %d: invalid value %q", lineNo, fields[0])
+			}
+			if v != 0 {
+				add(ai, aj, v)
+			}
+			if ai++; ai >= rows {
+				aj++
+				ai = firstRow(aj)
+			}
+			continue
+		}
+
+		expectedFields := 3
+		if header.field == "pattern" {
+			expectedFields = 2
+		}
+		if len(fields) != expectedFields {
+			return nil, fmt.Errorf("sparse: line %d: expected %d values in entry but found %d", lineNo, expectedFields, len(fields))
+		}
+		i, erri := strconv.Atoi(fields[0])
+		j, errj := strconv.Atoi(fields[1])
+		if erri != nil || errj != nil {
+			return nil, fmt.Errorf("sparse: line %d: invalid entry coordinates %q %q", lineNo, fields[0], fields[1])
+		}
+		if i < 1 || i > rows || j < 1 || j > cols {
+			return nil, fmt.Errorf("sparse: line %d: entry (%d, %d) out of range for %dx%d matrix", lineNo, i, j, rows, cols)
+		}
+		v := 1.0
+		if header.field != "pattern" {
+			if v, err = strconv.ParseFloat(fields[2], 64); err != nil {
+				return nil, fmt.Errorf("sparse: line %d: invalid value %q", lineNo, fields[2])
+			}
+		}
+		if header.symmetry != "general" && j > i {
+			return nil, fmt.Errorf("sparse: line %d: entry (%d, %d) above the diagonal of %s matrix", lineNo, i, j, header.symmetry)
+		}
+		if header.symmetry == "skew-symmetric" && i == j {
+			return nil, fmt.Errorf("sparse: line %d: diagonal entry (%d, %d) in skew-symmetric matrix", lineNo, i, j)
+		}
+		add(i-1, j-1, v)
+	}
+
+	if fields, ok := nextLine(); ok {
+		return nil, fmt.Errorf("sparse: line %d: unexpected data %q after %d MatrixMarket entries", lineNo, strings.Join(fields, " "), entries)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return coo, nil
+}
+
+// WriteMatrixMarket writes the matrix m to w in MatrixMarket coordinate format with the
+// banner "%%MatrixMarket matrix coordinate real general".  Each non-zero element is
+// written on its own line as a 1-based "row col value" triple in row major order.
+// Explicitly stored zero values are not written and duplicate entries (as may be
+// present in COO matrices) are summed.  Values are formatted with the minimum number of
+// digits required to represent them exactly so the output round-trips through
+// ReadMatrixMarket without loss of precision.
+func WriteMatrixMarket(w io.Writer, m mat.Matrix) error {
+	return writeMatrixMarket(w, m, false)
+}
+
+// WriteMatrixMarketSymmetric writes the symmetric matrix m to w in MatrixMarket
+// coordinate format with the "symmetric" qualifier, storing only the elements on or
+// below the diagonal, which roughly halves the size of the output compared with
+// WriteMatrixMarket.  ReadMatrixMarket will materialise the mirrored upper triangular
+// elements when reading the output.  WriteMatrixMarketSymmetric returns an error,
+// without writing anything, if m is not square or not exactly symmetric.
+func WriteMatrixMarketSymmetric(w io.Writer, m mat.Matrix) error {
+	return writeMatrixMarket(w, m, true)
+}
+
+// writeMatrixMarket writes m to w in MatrixMarket coordinate format, writing only the
+// lower triangle with the symmetric qualifier if lower is true.
+func writeMatrixMarket(w io.Writer, m mat.Matrix, lower bool) error {
+	r, c := m.Dims()
+	// canonicalise (sort with duplicates summed) so that the symmetry check sees the
+	// logical value of each element
+	csr := Repair(csrOf(m))
+
+	symmetry := "general"
+	if lower {
+		symmetry = "symmetric"
+		if r != c {
+			return fmt.Errorf("sparse: symmetric MatrixMarket matrix must be square but is %dx%d", r, c)
+		}
+	}
+
+	var entries [][]indexPair
+	var nnz int
+	for i := 0; i < r; i++ {
+		var row []indexPair
+		for k := csr.matrix.Indptr[i]; k < csr.matrix.Indptr[i+1]; k++ {
+			j, v := csr.matrix.Ind[k], csr.matrix.Data[k]
+			if v == 0 {
+				continue
+			}
+			if lower {
+				if csr.At(j, i) != v {
+					return errors.New("sparse: matrix is not symmetric")
+				}
+				if j > i {
+					continue
+				}
+			}
+			row = append(row, indexPair{index: j, value: v})
+		}
+		entries = append(entries, row)
+		nnz += len(row)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s matrix coordinate real %s\n", mmBanner, symmetry)
+	fmt.Fprintf(bw, "%d %d %d\n", r, c, nnz)
+	for i, row := range entries {
+		for _, e := range row {
+			fmt.Fprintf(bw, "%d %d %s\n", i+1, e.index+1, strconv.FormatFloat(e.value, 'g', -1, 64))
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,322 @@
+package sparse
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestReadMatrixMarket(t *testing.T) {
+	var tests = []struct {
+		input    string
+		r, c     int
+		expected []float64
+	}{
+		{ // coordinate general with comments and blank lines
+			input: `%%MatrixMarket matrix coordinate real general
+% a comment
+%
+3 4 4
+
+1 1 1.5
+2 3 -2
+3 1 3e2
+3 4 4
+`,
+			r: 3, c: 4,
+			expected: []float64{
+				1.5, 0, 0, 0,
+				0, 0, -2, 0,
+				300, 0, 0, 4,
+			},
+		},
+		{ // coordinate symmetric
+			input: `%%MatrixMarket matrix coordinate real symmetric
+3 3 4
+1 1 2
+2 1 -1
+3 2 -1
+3 3 2
+`,
+			r: 3, c: 3,
+			expected: []float64{
+				2, -1, 0,
+				-1, 0, -1,
+				0, -1, 2,
+			},
+		},
+		{ // coordinate skew-symmetric integer
+			input: `%%MatrixMarket matrix coordinate integer skew-symmetric
+3 3 2
+2 1 5
+3 1 -7
+`,
+			r: 3, c: 3,
+			expected: []float64{
+				0, -5, 7,
+				5, 0, 0,
+				-7, 0, 0,
+			},
+		},
+		{ // coordinate pattern, upper case qualifiers
+			input: `%%MatrixMarket MATRIX Coordinate Pattern General
+2 3 2
+1 2
+2 3
+`,
+			r: 2, c: 3,
+			expected: []float64{
+				0, 1, 0,
+				0, 0, 1,
+			},
+		},
+		{ // array general stored column major
+			input: `%%MatrixMarket matrix array real general
+2 3
+1
+4
+0
+5
+3
+0
+`,
+			r: 2, c: 3,
+			expected: []float64{
+				1, 0, 3,
+				4, 5, 0,
+			},
+		},
+		{ // array symmetric
+			input: `%%MatrixMarket matrix array real symmetric
+3 3
+1
+2
+3
+4
+5
+6
+`,
+			r: 3, c: 3,
+			expected: []float64{
+				1, 2, 3,
+				2, 4, 5,
+				3, 5, 6,
+			},
+		},
+		{ // array skew-symmetric
+			input: `%%MatrixMarket matrix array real skew-symmetric
+3 3
+1
+2
+3
+`,
+			r: 3, c: 3,
+			expected: []float64{
+				0, -1, -2,
+				1, 0, -3,
+				2, 3, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		coo, err := ReadMatrixMarket(strings.NewReader(test.input))
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		if !mat.Equal(expected, coo) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(coo))
+			t.Fail()
+		}
+	}
+}
+
+func TestReadMatrixMarketErrors(t *testing.T) {
+	var tests = []struct {
+		desc  string
+		input string
+	}{
+		{"empty input", ""},
+		{"missing banner", "3 3 1\n1 1 1\n"},
+		{"malformed banner", "%%MatrixMarket matrix coordinate real\n1 1 1\n1 1 1\n"},
+		{"complex field", "%%MatrixMarket matrix coordinate complex general\n1 1 1\n1 1 1 0\n"},
+		{"hermitian", "%%MatrixMarket matrix coordinate real hermitian\n1 1 1\n1 1 1\n"},
+		{"pattern array", "%%MatrixMarket matrix array pattern general\n1 1\n1\n"},
+		{"missing size line", "%%MatrixMarket matrix coordinate real general\n% comment only\n"},
+		{"short size line", "%%MatrixMarket matrix coordinate real general\n3 3\n"},
+		{"invalid size", "%%MatrixMarket matrix coordinate real general\n3 x 1\n1 1 1\n"},
+		{"too few entries", "%%MatrixMarket matrix coordinate real general\n3 3 3\n1 1 1\n2 2 2\n"},
+		{"too many entries", "%%MatrixMarket matrix coordinate real general\n3 3 1\n1 1 1\n2 2 2\n"},
+		{"out of range", "%%MatrixMarket matrix coordinate real general\n3 3 1\n4 1 1\n"},
+		{"zero index", "%%MatrixMarket matrix coordinate real general\n3 3 1\n0 1 1\n"},
+		{"invalid value", "%%MatrixMarket matrix coordinate real general\n3 3 1\n1 1 abc\n"},
+		{"missing value", "%%MatrixMarket matrix coordinate real general\n3 3 1\n1 1\n"},
+		{"non-square symmetric", "%%MatrixMarket matrix coordinate real symmetric\n3 2 1\n1 1 1\n"},
+		{"upper triangular symmetric entry", "%%MatrixMarket matrix coordinate real symmetric\n3 3 1\n1 2 1\n"},
+		{"skew-symmetric diagonal", "%%MatrixMarket matrix coordinate real skew-symmetric\n3 3 1\n2 2 1\n"},
+		{"short array", "%%MatrixMarket matrix array real general\n2 2\n1\n2\n3\n"},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		if _, err := ReadMatrixMarket(strings.NewReader(test.input)); err == nil {
+			t.Logf("Expected error for %s but received none\n", test.desc)
+			t.Fail()
+		}
+	}
+}
+
+func TestWriteMatrixMarket(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1.5, 0, 0, 0,
+				0, 0, -2, 0,
+				1.0 / 3, 0, 0, 4e-300,
+			},
+		},
+		{
+			r: 3, c: 3,
+			data: []float64{
+				1, 0, 0,
+				0, 2, 0,
+				0, 0, 3,
+			},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.data)
+		var nnz int
+		for _, v := range test.data {
+			if v != 0 {
+				nnz++
+			}
+		}
+
+		for _, m := range []mat.Matrix{
+			CreateCSR(test.r, test.c, test.data),
+			CreateCSC(test.r, test.c, test.data),
+			CreateCOO(test.r, test.c, test.data),
+			CreateDOK(test.r, test.c, test.data),
+			CreateDIA(test.r, test.c, test.data),
+		} {
+			if _, isDIA := m.(*DIA); isDIA && test.r != test.c {
+				continue
+			}
+
+			var buf strings.Builder
+			if err := WriteMatrixMarket(&buf, m); err != nil {
+				t.Errorf("Unexpected error writing %T: %v", m, err)
+				continue
+			}
+			lines := strings.Split(buf.String(), "\n")
+			if lines[0] != "%%MatrixMarket matrix coordinate real general" {
+				t.Logf("Unexpected banner for %T: %q\n", m, lines[0])
+				t.Fail()
+			}
+			if want := fmt.Sprintf("%d %d %d", test.r, test.c, nnz); lines[1] != want {
+				t.Logf("Expected size line %q for %T but received %q\n", want, m, lines[1])
+				t.Fail()
+			}
+
+			coo, err := ReadMatrixMarket(strings.NewReader(buf.String()))
+			if err != nil {
+				t.Errorf("Unexpected error reading output for %T: %v", m, err)
+				continue
+			}
+			if !mat.Equal(expected, coo) {
+				t.Logf("Round trip of %T: Expected:\n%v\n but received:\n%v\n", m, mat.Formatted(expected), mat.Formatted(coo))
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestWriteMatrixMarketDuplicates(t *testing.T) {
+	coo := NewCOO(2, 2, []int{0, 1, 0}, []int{1, 0, 1}, []float64{1, 2, 3})
+
+	var buf strings.Builder
+	if err := WriteMatrixMarket(&buf, coo); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "%%MatrixMarket matrix coordinate real general\n2 2 2\n1 2 4\n2 1 2\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\nbut received:\n%s", expected, buf.String())
+	}
+}
+
+func TestWriteMatrixMarketSymmetricDuplicates(t *testing.T) {
+	expected := "%%MatrixMarket matrix coordinate real symmetric\n2 2 2\n1 1 1\n2 1 2\n"
+	for _, m := range []mat.Matrix{
+		NewCSR(2, 2, []int{0, 3, 4}, []int{1, 0, 1, 0}, []float64{1, 1, 1, 2}),
+		NewCOO(2, 2, []int{0, 0, 0, 1}, []int{1, 0, 1, 0}, []float64{1, 1, 1, 2}),
+	} {
+		var buf strings.Builder
+		if err := WriteMatrixMarketSymmetric(&buf, m); err != nil {
+			t.Fatalf("Unexpected error writing %T with duplicates: %v", m, err)
+		}
+		if buf.String() != expected {
+			t.Errorf("Expected:\n%s\nbut received:\n%s", expected, buf.String())
+		}
+	}
+}
+
+func TestWriteMatrixMarketSymmetric(t *testing.T) {
+	data := []float64{
+		2, -1, 0,
+		-1, 2, -1,
+		0, -1, 2,
+	}
+	m := CreateCSR(3, 3, data)
+
+	var buf strings.Builder
+	if err := WriteMatrixMarketSymmetric(&buf, m); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "%%MatrixMarket matrix coordinate real symmetric\n3 3 5\n1 1 2\n2 1 -1\n2 2 2\n3 2 -1\n3 3 2\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\nbut received:\n%s", expected, buf.String())
+	}
+
+	coo, err := ReadMatrixMarket(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Unexpected error reading output: %v", err)
+	}
+	if !mat.Equal(m, coo) {
+		t.Errorf("Round trip: Expected:\n%v\n but received:\n%v\n", mat.Formatted(m), mat.Formatted(coo))
+	}
+
+	for _, bad := range []mat.Matrix{
+		CreateCSR(2, 3, []float64{1, 0, 0, 0, 1, 0}),
+		CreateCSR(2, 2, []float64{1, 2, 3, 1}),
+	} {
+		buf.Reset()
+		if err := WriteMatrixMarketSymmetric(&buf, bad); err == nil {
+			t.Errorf("Expected error writing non-symmetric matrix %v", mat.Formatted(bad))
+		}
+		if buf.Len() != 0 {
+			t.Errorf("Expected nothing written on error but received %q", buf.String())
+		}
+	}
+}
@@ -0,0 +1,118 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+package sparse
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"reflect"
+	"syscall"
+	"unsafe"
+)
+
+// OpenMmapCSR opens the file at path, containing a CSR matrix in the binary format
+// written by CSR.MarshalBinary and CSR.MarshalBinaryTo, and memory maps it so that the
+// index pointers, indices and data of the returned CSR matrix are backed directly by
+// the mapped region of the file rather than by heap allocations.  This allows
+// operations such as matrix vector multiplication to be performed over matrices larger
+// than available memory with the operating system paging the matrix in from disk as it
+// is accessed.  The returned close function unmaps the file and must be called once the
+// matrix is no longer required.  The matrix must not be used after close is called.
+//
+// The mapping is read-only and so the returned matrix must be treated as immutable -
+// any attempt to modify it (e.g. with Set or by using it as the receiver of an
+// arithmetic operation) will cause the program to crash with a segmentation fault.
+//
+// As the file is mapped directly into Go int and float64 slices, OpenMmapCSR is only
+// supported on 64 bit little-endian Unix platforms and will return an error on other
+// architectures.  An error is also returned if the file is truncated, its header is
+// inconsistent with its size or the mapped matrix fails the checks of NewCSRChecked
+// e.g. because the index pointers or column indices have been corrupted.
+func OpenMmapCSR(path string) (*CSR, func() error, error) {
+	if !mmapSupported() {
+		return nil, nil, errors.New("sparse: memory mapping requires a 64 bit little-endian platform")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := fi.Size()
+	if size < 5*int64(sizeInt64) || size > maxLen {
+		return nil, nil, errors.New("sparse: invalid memory mapped CSR file size")
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	closer := func() error {
+		return syscall.Munmap(data)
+	}
+
+	var header [5]int64
+	for i := range header {
+		header[i] = int64(binary.LittleEndian.Uint64(data[i*sizeInt64:]))
+	}
+	r, c, nIndptr, nInd, nnz := header[0], header[1], header[2], header[3], header[4]
+	if r < 0 || c < 0 || nIndptr != r+1 || nInd != nnz || nnz < 0 ||
+		size != 5*int64(sizeInt64)+(nIndptr+nInd)*int64(sizeInt64)+nnz*int64(sizeFloat64) {
+		closer()
+		return nil, nil, errors.New("sparse: invalid memory mapped CSR file header")
+	}
+
+	p := 5 * sizeInt64
+	indptr := mmapInts(data[p:], int(nIndptr))
+	p += int(nIndptr) * sizeInt64
+
+	var ind []int
+	var vals []float64
+	if nnz > 0 {
+		ind = mmapInts(data[p:], int(nInd))
+		p += int(nInd) * sizeInt64
+		vals = mmapFloats(data[p:], int(nnz))
+	}
+
+	m, err := NewCSRChecked(int(r), int(c), indptr, ind, vals)
+	if err != nil {
+		closer()
+		return nil, nil, err
+	}
+	return m, closer, nil
+}
+
+// mmapInts returns a slice of n ints backed by the start of the mapped region b.
+func mmapInts(b []byte, n int) []int {
+	var s []int
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	h.Data = uintptr(unsafe.Pointer(&b[0]))
+	h.Len, h.Cap = n, n
+	return s
+}
+
+// mmapFloats returns a slice of n float64s backed by the start of the mapped region b.
+func mmapFloats(b []byte, n int) []float64 {
+	var s []float64
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&s))
+	h.Data = uintptr(unsafe.Pointer(&b[0]))
+	h.Len, h.Cap = n, n
+	return s
+}
+
+// mmapSupported returns true if Go ints are 64 bits wide and the platform is
+// little-endian such that the binary encoding of CSR matrices may be used in place.
+func mmapSupported() bool {
+	if unsafe.Sizeof(int(0)) != 8 {
+		return false
+	}
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package sparse
+
+import "errors"
+
+// OpenMmapCSR memory maps a CSR matrix from the file at path.  Memory mapping is only
+// supported on Unix platforms so on this platform OpenMmapCSR always returns an error.
+func OpenMmapCSR(path string) (*CSR, func() error, error) {
+	return nil, nil, errors.New("sparse: memory mapping is not supported on this platform")
+}
@@ -0,0 +1,125 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+package sparse
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestOpenMmapCSR(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 7,
+				0, 0, 0, 0,
+				3, 0, 3, 6,
+			},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "sparse")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		csr := CreateCSR(test.r, test.c, test.data).(*CSR)
+
+		path := filepath.Join(dir, "matrix.bin")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		if _, err := csr.MarshalBinaryTo(f); err != nil {
+			t.Fatalf("Failed to write matrix: %v", err)
+		}
+		f.Close()
+
+		m, closer, err := OpenMmapCSR(path)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+
+		if !mat.Equal(csr, m) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(csr), mat.Formatted(m))
+			t.Fail()
+		}
+
+		x := make([]float64, test.c)
+		for i := range x {
+			x[i] = float64(i + 1)
+		}
+		var expected, result mat.VecDense
+		expected.MulVec(csr, mat.NewVecDense(test.c, x))
+		result.MulVec(m, mat.NewVecDense(test.c, x))
+		if !mat.Equal(&expected, &result) {
+			t.Logf("Expected MulVec result %v but received %v", mat.Formatted(expected.T()), mat.Formatted(result.T()))
+			t.Fail()
+		}
+
+		if err := closer(); err != nil {
+			t.Errorf("Unexpected error unmapping file: %v", err)
+		}
+	}
+}
+
+func TestOpenMmapCSRInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sparse")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the 40 byte header is followed by indptr (3 values) then ind (2 values)
+	corrupt := func(offset int, v uint64) func([]byte) []byte {
+		return func(buf []byte) []byte {
+			binary.LittleEndian.PutUint64(buf[offset:], v)
+			return buf
+		}
+	}
+	var tests = []struct {
+		desc   string
+		modify func([]byte) []byte
+	}{
+		{desc: "truncated", modify: func(buf []byte) []byte { return buf[:len(buf)-4] }},
+		{desc: "decreasing indptr", modify: corrupt(48, 5)},
+		{desc: "indptr not ending at nnz", modify: corrupt(56, 1)},
+		{desc: "column index out of range", modify: corrupt(64, 7)},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		path := filepath.Join(dir, "invalid.bin")
+		buf, _ := CreateCSR(2, 2, []float64{1, 0, 0, 2}).(*CSR).MarshalBinary()
+		if err := ioutil.WriteFile(path, test.modify(buf), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+
+		if _, _, err := OpenMmapCSR(path); err == nil {
+			t.Errorf("Expected error opening %s file but received none", test.desc)
+		}
+	}
+}
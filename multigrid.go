@@ -0,0 +1,58 @@
+package sparse
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// Coarsen builds the operators for a two level aggregation based algebraic multigrid
+// (AMG) hierarchy from the square matrix a and the aggregation aggregates.  Each
+// element aggregates[i] specifies the index of the coarse degree of freedom (aggregate)
+// to which the fine degree of freedom i belongs.  The number of coarse degrees of
+// freedom, nc, is taken as the largest aggregate index plus 1.
+//
+// The returned prolongation is the n x nc piecewise constant prolongation (interpolation)
+// operator P, where P(i, aggregates[i]) = 1 and all other elements are zero, and coarse
+// is the nc x nc Galerkin coarse grid operator P^T * A * P.  As P is piecewise constant,
+// the coarse operator is formed directly by summing the elements of a in each pair of
+// aggregates rather than by explicit matrix multiplication.  Coarsen will panic if a
+// is not square, if the length of aggregates does not equal the dimension of a or if
+// any of the aggregate indices are negative.
+func Coarsen(a *CSR, aggregates []int) (coarse *CSR, prolongation *CSR) {
+	n, c := a.Dims()
+	if n != c || len(aggregates) != n {
+		panic(mat.ErrShape)
+	}
+
+	nc := 0
+	for _, agg := range aggregates {
+		if agg < 0 {
+			panic(mat.ErrIndexOutOfRange)
+		}
+		if agg >= nc {
+			nc = agg + 1
+		}
+	}
+
+	indptr := make([]int, n+1)
+	ind := make([]int, n)
+	data := make([]float64, n)
+	for i, agg := range aggregates {
+		indptr[i+1] = i + 1
+		ind[i] = agg
+		data[i] = 1
+	}
+	prolongation = NewCSR(n, nc, indptr, ind, data)
+
+	nnz := a.NNZ()
+	rows := make([]int, 0, nnz)
+	cols := make([]int, 0, nnz)
+	vals := make([]float64, 0, nnz)
+	a.DoNonZero(func(i, j int, v float64) {
+		rows = append(rows, aggregates[i])
+		cols = append(cols, aggregates[j])
+		vals = append(vals, v)
+	})
+	coarse = NewCOO(nc, nc, rows, cols, vals).ToCSR()
+
+	return coarse, prolongation
+}
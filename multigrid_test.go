@@ -0,0 +1,90 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCoarsen(t *testing.T) {
+	var tests = []struct {
+		n          int
+		aggregates []int
+		nc         int
+	}{
+		{
+			n:          6,
+			aggregates: []int{0, 0, 1, 1, 2, 2},
+			nc:         3,
+		},
+		{
+			n:          5,
+			aggregates: []int{1, 0, 1, 0, 1},
+			nc:         2,
+		},
+		{
+			n:          4,
+			aggregates: []int{0, 1, 2, 3},
+			nc:         4,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := laplacian1D(test.n)
+
+		coarse, p := Coarsen(a, test.aggregates)
+
+		if r, c := coarse.Dims(); r != test.nc || c != test.nc {
+			t.Errorf("Expected coarse operator of size %dx%d but received %dx%d", test.nc, test.nc, r, c)
+			continue
+		}
+		if r, c := p.Dims(); r != test.n || c != test.nc {
+			t.Errorf("Expected prolongation of size %dx%d but received %dx%d", test.n, test.nc, r, c)
+			continue
+		}
+
+		for i, agg := range test.aggregates {
+			for j := 0; j < test.nc; j++ {
+				expected := 0.0
+				if j == agg {
+					expected = 1
+				}
+				if v := p.At(i, j); v != expected {
+					t.Errorf("Expected P(%d, %d) = %v but received %v", i, j, expected, v)
+				}
+			}
+		}
+
+		var ap, expected mat.Dense
+		ap.Mul(a, p)
+		expected.Mul(p.T(), &ap)
+		if !mat.EqualApprox(&expected, coarse, 1e-12) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(coarse))
+			t.Fail()
+		}
+	}
+}
+
+func TestCoarsenValues(t *testing.T) {
+	coarse, _ := Coarsen(laplacian1D(4), []int{0, 0, 1, 1})
+
+	expected := mat.NewDense(2, 2, []float64{
+		2, -1,
+		-1, 2,
+	})
+	if !mat.Equal(expected, coarse) {
+		t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(coarse))
+		t.Fail()
+	}
+}
+
+func TestFailCoarsen(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("Expected panic for aggregates of incorrect length")
+		}
+	}()
+	Coarsen(laplacian1D(4), []int{0, 0, 1})
+}
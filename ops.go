@@ -0,0 +1,63 @@
+package sparse
+
+import (
+	"github.com/james-bowman/sparse/blas"
+	"gonum.org/v1/gonum/mat"
+)
+
+// The following functions expose the sparse vector (Level 1) BLAS routines used
+// internally by this package as building blocks for custom sparse vector kernels.  In
+// each case, the sparse vector is represented by the slices x (the non-zero values)
+// and indx (the index of each value in the full vector) and the dense vector by the
+// slice y.  Unlike the underlying BLAS routines (some of which are implemented in
+// assembly without bounds checking), the arguments are validated before use: each
+// function will panic with mat.ErrShape if x and indx are different lengths and with
+// mat.ErrIndexOutOfRange if any index in indx falls outside the bounds of y.
+
+// Axpyi (sparse update) scales the sparse vector x by alpha and adds the result to the
+// dense vector y i.e. y[indx[i]] += alpha * x[i].
+func Axpyi(alpha float64, x []float64, indx []int, y []float64) {
+	checkSparseVec(x, indx, len(y))
+	blas.Dusaxpy(alpha, x, indx, y, 1)
+}
+
+// Doti (sparse dot product) returns the dot product of the sparse vector x and the
+// dense vector y i.e. the sum of x[i] * y[indx[i]].
+func Doti(x []float64, indx []int, y []float64) float64 {
+	checkSparseVec(x, indx, len(y))
+	return blas.Dusdot(x, indx, y, 1)
+}
+
+// Gthr (sparse gather) gathers the elements of the dense vector y specified by indx
+// into the sparse vector x i.e. x[i] = y[indx[i]] returning x.  If x is nil, a new
+// slice of the same length as indx will be allocated.
+func Gthr(x []float64, indx []int, y []float64) []float64 {
+	if x == nil {
+		x = make([]float64, len(indx))
+	}
+	checkSparseVec(x, indx, len(y))
+	blas.Dusga(y, 1, x, indx)
+	return x
+}
+
+// Sctr (sparse scatter) scatters the elements of the sparse vector x into the dense
+// vector y at the positions specified by indx i.e. y[indx[i]] = x[i].  Other elements
+// of y are left unchanged.
+func Sctr(x []float64, indx []int, y []float64) {
+	checkSparseVec(x, indx, len(y))
+	blas.Dussc(x, y, 1, indx)
+}
+
+// checkSparseVec validates the sparse vector (x, indx) for use with a dense vector of
+// length n, panicking if the lengths of x and indx differ or if any index is out of
+// range.
+func checkSparseVec(x []float64, indx []int, n int) {
+	if len(x) != len(indx) {
+		panic(mat.ErrShape)
+	}
+	for _, i := range indx {
+		if i < 0 || i >= n {
+			panic(mat.ErrIndexOutOfRange)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestSparseVectorOps(t *testing.T) {
+	var tests = []struct {
+		x    []float64
+		indx []int
+		y    []float64
+	}{
+		{
+			x:    []float64{1, 2, 3},
+			indx: []int{0, 3, 5},
+			y:    []float64{1, 2, 3, 4, 5, 6},
+		},
+		{
+			x:    []float64{-1, 4},
+			indx: []int{4, 1},
+			y:    []float64{0, 1, 0, 0, 2},
+		},
+		{
+			x:    nil,
+			indx: nil,
+			y:    []float64{1, 2},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		// dense representation of the sparse vector x
+		dense := make([]float64, len(test.y))
+		for i, idx := range test.indx {
+			dense[idx] = test.x[i]
+		}
+
+		// Axpyi
+		y := append([]float64(nil), test.y...)
+		Axpyi(2, test.x, test.indx, y)
+		expected := append([]float64(nil), test.y...)
+		floats.AddScaled(expected, 2, dense)
+		if !floats.Equal(expected, y) {
+			t.Errorf("Axpyi: Expected %v but received %v", expected, y)
+		}
+
+		// Doti
+		if dot, expectedDot := Doti(test.x, test.indx, test.y), floats.Dot(dense, test.y); dot != expectedDot {
+			t.Errorf("Doti: Expected %v but received %v", expectedDot, dot)
+		}
+
+		// Gthr
+		x := Gthr(nil, test.indx, test.y)
+		for i, idx := range test.indx {
+			if x[i] != test.y[idx] {
+				t.Errorf("Gthr: Expected %v at %d but received %v", test.y[idx], i, x[i])
+			}
+		}
+
+		// Sctr
+		y = append([]float64(nil), test.y...)
+		Sctr(test.x, test.indx, y)
+		expected = append([]float64(nil), test.y...)
+		for i, idx := range test.indx {
+			expected[idx] = test.x[i]
+		}
+		if !floats.Equal(expected, y) {
+			t.Errorf("Sctr: Expected %v but received %v", expected, y)
+		}
+	}
+}
+
+func TestFailSparseVectorOps(t *testing.T) {
+	y := make([]float64, 3)
+
+	var tests = []struct {
+		desc string
+		fn   func()
+	}{
+		{"Axpyi index out of range", func() { Axpyi(1, []float64{1}, []int{3}, y) }},
+		{"Axpyi negative index", func() { Axpyi(1, []float64{1}, []int{-1}, y) }},
+		{"Doti length mismatch", func() { Doti([]float64{1, 2}, []int{0}, y) }},
+		{"Gthr index out of range", func() { Gthr(nil, []int{0, 5}, y) }},
+		{"Gthr length mismatch", func() { Gthr(make([]float64, 1), []int{0, 1}, y) }},
+		{"Sctr index out of range", func() { Sctr([]float64{1}, []int{3}, y) }},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic but received none")
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
@@ -0,0 +1,98 @@
+package sparse
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// MulParallel takes the matrix product of the supplied matrices a and b and stores the
+// result in dst as per CSR.Mul but distributing the work across workers goroutines.  As
+// each row of the product depends only upon the corresponding row of a (and all of b),
+// rows of the result can be computed independently.  The rows are partitioned into
+// contiguous blocks, one per goroutine, balanced so that each block spans roughly the
+// same number of non-zero elements of a, and each goroutine computes its block of rows
+// into its own storage using Gustavson's algorithm with a private sparse accumulator.
+// The blocks are then stitched together into dst.  Operands that are not CSR matrices
+// are first converted to CSR.  If workers is less than 1, runtime.GOMAXPROCS(0)
+// goroutines are used and if only a single goroutine would be used, the product is
+// computed directly by CSR.Mul on the calling goroutine.  MulParallel will panic if the
+// number of columns in a does not equal the number of rows in b or if dst is not
+// zero-sized and is the wrong shape.
+func MulParallel(dst *CSR, a, b mat.Matrix, workers int) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(mat.ErrShape)
+	}
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > ar {
+		workers = ar
+	}
+
+	lhs := csrOf(a)
+	rhs := csrOf(b)
+	if workers <= 1 {
+		dst.Mul(lhs, rhs)
+		return
+	}
+
+	// partition the rows into blocks of approximately equal numbers of non-zeros of a
+	bounds := make([]int, workers+1)
+	nnz := lhs.matrix.Indptr[ar]
+	for w := 1; w < workers; w++ {
+		bounds[w] = sort.SearchInts(lhs.matrix.Indptr[:ar+1], w*nnz/workers)
+		if bounds[w] < bounds[w-1] {
+			bounds[w] = bounds[w-1]
+		}
+	}
+	bounds[workers] = ar
+
+	type block struct {
+		indptr []int
+		ind    []int
+		data   []float64
+	}
+	blocks := make([]block, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			begin, end := bounds[w], bounds[w+1]
+			blk := block{indptr: make([]int, end-begin)}
+			spa := NewSPA(bc)
+			for i := begin; i < end; i++ {
+				for k := lhs.matrix.Indptr[i]; k < lhs.matrix.Indptr[i+1]; k++ {
+					rb := rhs.matrix.Indptr[lhs.matrix.Ind[k]]
+					re := rhs.matrix.Indptr[lhs.matrix.Ind[k]+1]
+					spa.Scatter(rhs.matrix.Data[rb:re], rhs.matrix.Ind[rb:re], lhs.matrix.Data[k], &blk.ind)
+				}
+				spa.GatherAndZero(&blk.data, &blk.ind)
+				blk.indptr[i-begin] = len(blk.ind)
+			}
+			blocks[w] = blk
+		}(w)
+	}
+	wg.Wait()
+
+	c := dst
+	if m, temp, restore := c.spalloc(lhs, rhs); temp {
+		defer restore()
+		c = m
+	}
+	var offset int
+	for w, blk := range blocks {
+		for i, p := range blk.indptr {
+			c.matrix.Indptr[bounds[w]+i+1] = offset + p
+		}
+		c.matrix.Ind = append(c.matrix.Ind, blk.ind...)
+		c.matrix.Data = append(c.matrix.Data, blk.data...)
+		offset += len(blk.ind)
+	}
+}
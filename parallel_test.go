@@ -0,0 +1,62 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestMulParallel(t *testing.T) {
+	var tests = []struct {
+		ar, ac, bc int
+		density    float32
+	}{
+		{ar: 1, ac: 1, bc: 1, density: 1},
+		{ar: 5, ac: 4, bc: 3, density: 0.5},
+		{ar: 50, ac: 40, bc: 60, density: 0.1},
+		{ar: 100, ac: 100, bc: 100, density: 0.05},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := Random(CSRFormat, test.ar, test.ac, test.density)
+		b := Random(CSRFormat, test.ac, test.bc, test.density)
+
+		var expected mat.Dense
+		expected.Mul(a, b)
+
+		for _, workers := range []int{-1, 0, 1, 2, 3, 7, 1000} {
+			var c CSR
+			MulParallel(&c, a, b, workers)
+			if !mat.EqualApprox(&expected, &c, 1e-12) {
+				t.Logf("%d workers: Expected:\n%v\n but received:\n%v\n", workers, mat.Formatted(&expected), mat.Formatted(&c))
+				t.Fail()
+			}
+			if raw := c.RawMatrix(); len(raw.Indptr) != test.ar+1 || checkIndptr(raw.Indptr, len(raw.Ind)) != nil {
+				t.Logf("%d workers: Result has malformed index pointers: %v\n", workers, raw.Indptr)
+				t.Fail()
+			}
+		}
+
+		// receiver aliasing an operand
+		if test.ar == test.ac {
+			var expectedSq mat.Dense
+			expectedSq.Mul(a, a)
+			aliased := a.(*CSR).ToCSC().ToCSR()
+			MulParallel(aliased, aliased, aliased, 2)
+			if !mat.EqualApprox(&expectedSq, aliased, 1e-12) {
+				t.Logf("Aliased: Expected:\n%v\n but received:\n%v\n", mat.Formatted(&expectedSq), mat.Formatted(aliased))
+				t.Fail()
+			}
+		}
+
+		// operands in other formats
+		var c CSR
+		MulParallel(&c, a.(*CSR).ToCSC(), mat.DenseCopyOf(b), 2)
+		if !mat.EqualApprox(&expected, &c, 1e-12) {
+			t.Logf("Mixed formats: Expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(&c))
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package sparse
+
+import (
+	"errors"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrPermutation is the panic value used when a slice specified as a permutation is not a
+// valid permutation i.e. does not contain each of the integers in [0, n) exactly once.
+var ErrPermutation = errors.New("sparse: invalid permutation")
+
+// PermuteRows returns a new CSR matrix containing the rows of the receiver reordered
+// according to perm where perm[i] is the index of the row of the receiver to be placed at
+// row i of the result i.e. result(i, j) = c(perm[i], j).  The returned matrix will not share
+// underlying storage with the receiver nor is the receiver modified by this call.
+// PermuteRows will panic with mat.ErrShape if len(perm) is not equal to the number of rows
+// of the receiver or with ErrPermutation if perm is not a valid permutation.
+func (c *CSR) PermuteRows(perm []int) *CSR {
+	checkPermutation(perm, c.matrix.I)
+	return c.permute(perm, nil)
+}
+
+// PermuteCols returns a new CSR matrix containing the columns of the receiver reordered
+// according to perm where perm[j] is the index of the column of the receiver to be placed
+// at column j of the result i.e. result(i, j) = c(i, perm[j]).  The stored elements of each
+// row retain their relative order and so the column indices of each row of the result will
+// generally not be sorted (see SortIndices).  The returned matrix will not share underlying
+// storage with the receiver nor is the receiver modified by this call.  PermuteCols will
+// panic with mat.ErrShape if len(perm) is not equal to the number of columns of the
+// receiver or with ErrPermutation if perm is not a valid permutation.
+func (c *CSR) PermuteCols(perm []int) *CSR {
+	checkPermutation(perm, c.matrix.J)
+	return c.permute(nil, perm)
+}
+
+// Permute returns a new CSR matrix containing the receiver symmetrically reordered by
+// applying the permutation perm to both its rows and columns i.e. result(i, j) =
+// c(perm[i], perm[j]), equivalent to P * A * P^T for the permutation matrix P.  Symmetric
+// reordering preserves the symmetry of symmetric matrices and is typically used to apply
+// fill reducing or bandwidth reducing orderings (see RCM) ahead of factorisation.  As for
+// PermuteCols, the column indices of each row of the result will generally not be sorted.
+// The returned matrix will not share underlying storage with the receiver nor is the
+// receiver modified by this call.  Permute will panic with mat.ErrSquare if the receiver is
+// not square, with mat.ErrShape if len(perm) is not equal to the dimension of the receiver
+// or with ErrPermutation if perm is not a valid permutation.
+func (c *CSR) Permute(perm []int) *CSR {
+	if c.matrix.I != c.matrix.J {
+		panic(mat.ErrSquare)
+	}
+	checkPermutation(perm, c.matrix.I)
+	return c.permute(perm, perm)
+}
+
+// permute returns a new CSR matrix with the rows of the receiver reordered by rowPerm and
+// the columns reordered by colPerm.  A nil permutation leaves the corresponding axis
+// unchanged.
+func (c *CSR) permute(rowPerm, colPerm []int) *CSR {
+	var inv []int
+	if colPerm != nil {
+		inv = make([]int, len(colPerm))
+		for j, src := range colPerm {
+			inv[src] = j
+		}
+	}
+
+	indptr := make([]int, c.matrix.I+1)
+	ind := make([]int, 0, c.matrix.Indptr[c.matrix.I])
+	data := make([]float64, 0, c.matrix.Indptr[c.matrix.I])
+	for i := 0; i < c.matrix.I; i++ {
+		src := i
+		if rowPerm != nil {
+			src = rowPerm[i]
+		}
+		for k := c.matrix.Indptr[src]; k < c.matrix.Indptr[src+1]; k++ {
+			j := c.matrix.Ind[k]
+			if inv != nil {
+				j = inv[j]
+			}
+			ind = append(ind, j)
+			data = append(data, c.matrix.Data[k])
+		}
+		indptr[i+1] = len(ind)
+	}
+
+	return NewCSR(c.matrix.I, c.matrix.J, indptr, ind, data)
+}
+
+// checkPermutation panics if perm is not a valid permutation of [0, n).
+func checkPermutation(perm []int, n int) {
+	if len(perm) != n {
+		panic(mat.ErrShape)
+	}
+	seen := make([]bool, n)
+	for _, p := range perm {
+		if p < 0 || p >= n || seen[p] {
+			panic(ErrPermutation)
+		}
+		seen[p] = true
+	}
+}
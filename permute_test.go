@@ -0,0 +1,127 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCSRPermute(t *testing.T) {
+	r, c := 3, 4
+	data := []float64{
+		1, 0, 2, 0,
+		0, 3, 0, 0,
+		4, 0, 5, 6,
+	}
+
+	var tests = []struct {
+		desc     string
+		perm     []int
+		permute  func(*CSR, []int) *CSR
+		expected []float64
+	}{
+		{
+			desc:    "rows",
+			perm:    []int{2, 0, 1},
+			permute: (*CSR).PermuteRows,
+			expected: []float64{
+				4, 0, 5, 6,
+				1, 0, 2, 0,
+				0, 3, 0, 0,
+			},
+		},
+		{
+			desc:    "cols",
+			perm:    []int{3, 2, 0, 1},
+			permute: (*CSR).PermuteCols,
+			expected: []float64{
+				0, 2, 1, 0,
+				0, 0, 0, 3,
+				6, 5, 4, 0,
+			},
+		},
+		{
+			desc:     "identity",
+			perm:     []int{0, 1, 2},
+			permute:  (*CSR).PermuteRows,
+			expected: data,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		a := CreateCSR(r, c, data).(*CSR)
+		result := test.permute(a, test.perm)
+
+		expected := mat.NewDense(r, c, test.expected)
+		if !mat.Equal(expected, result) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+			t.Fail()
+		}
+		if !mat.Equal(mat.NewDense(r, c, data), a) {
+			t.Logf("Expected receiver to be unmodified but was:\n%v\n", mat.Formatted(a))
+			t.Fail()
+		}
+	}
+}
+
+func TestCSRPermuteSymmetric(t *testing.T) {
+	data := []float64{
+		4, 1, 0, 2,
+		1, 5, 3, 0,
+		0, 3, 6, 0,
+		2, 0, 0, 7,
+	}
+	a := CreateCSR(4, 4, data).(*CSR)
+	perm := []int{3, 1, 0, 2}
+
+	result := a.Permute(perm)
+
+	// P * A * P^T where row i of P is the unit vector e_perm[i]
+	p := mat.NewDense(4, 4, nil)
+	for i, src := range perm {
+		p.Set(i, src, 1)
+	}
+	var expected mat.Dense
+	expected.Mul(p, mat.NewDense(4, 4, data))
+	expected.Mul(&expected, p.T())
+
+	if !mat.Equal(&expected, result) {
+		t.Errorf("Expected:\n%v\n but received:\n%v", mat.Formatted(&expected), mat.Formatted(result))
+	}
+	if !result.IsSymmetric() {
+		t.Errorf("Expected symmetric reordering to preserve symmetry")
+	}
+}
+
+func TestCSRPermutePanics(t *testing.T) {
+	a := CreateCSR(3, 4, nil).(*CSR)
+	square := CreateCSR(3, 3, nil).(*CSR)
+
+	var tests = []struct {
+		fn       func()
+		expected interface{}
+	}{
+		{fn: func() { a.PermuteRows([]int{0, 1}) }, expected: mat.ErrShape},
+		{fn: func() { a.PermuteCols([]int{0, 1, 2}) }, expected: mat.ErrShape},
+		{fn: func() { a.PermuteRows([]int{0, 1, 1}) }, expected: ErrPermutation},
+		{fn: func() { a.PermuteCols([]int{0, 1, 2, 4}) }, expected: ErrPermutation},
+		{fn: func() { square.Permute([]int{0, -1, 2}) }, expected: ErrPermutation},
+		{fn: func() { a.Permute([]int{0, 1, 2}) }, expected: mat.ErrSquare},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		func() {
+			defer func() {
+				if r := recover(); r != test.expected {
+					t.Logf("Expected panic with %v but received %v\n", test.expected, r)
+					t.Fail()
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
@@ -3,6 +3,8 @@ package sparse
 import (
 	"encoding"
 	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
 	"errors"
 	"io"
 	"math"
@@ -27,6 +29,12 @@ var (
 	_ encoding.BinaryUnmarshaler = (*CSC)(nil)
 	_ encoding.BinaryMarshaler   = (*CSR)(nil)
 	_ encoding.BinaryUnmarshaler = (*CSR)(nil)
+	_ gob.GobEncoder             = (*CSC)(nil)
+	_ gob.GobDecoder             = (*CSC)(nil)
+	_ gob.GobEncoder             = (*CSR)(nil)
+	_ gob.GobDecoder             = (*CSR)(nil)
+	_ json.Marshaler             = (*CSR)(nil)
+	_ json.Unmarshaler           = (*CSR)(nil)
 )
 
 // MarshalBinary binary serialises the receiver into a []byte and returns the result.
@@ -335,6 +343,7 @@ func (c *CSR) UnmarshalBinary(data []byte) error {
 	if len(data) < 5*sizeInt64 {
 		return errors.New("sparse: data is missing required attributes")
 	}
+	c.sorted = false
 
 	p := 0
 	c.matrix.I = int(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
@@ -442,6 +451,7 @@ func (c *CSR) UnmarshalBinaryFrom(r io.Reader) (int, error) {
 		return n, errors.New("sparse: dimensions/data size mismatch")
 	}
 
+	c.sorted = false
 	c.matrix.I = int(i)
 	c.matrix.J = int(j)
 	c.matrix.Indptr = make([]int, indptrn)
@@ -611,6 +621,7 @@ func (c *CSC) UnmarshalBinary(data []byte) error {
 	if len(data) < 5*sizeInt64 {
 		return errors.New("sparse: data is missing required attributes")
 	}
+	c.sorted = false
 
 	p := 0
 	c.matrix.I = int(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
@@ -718,6 +729,7 @@ func (c *CSC) UnmarshalBinaryFrom(r io.Reader) (int, error) {
 		return n, errors.New("sparse: dimensions/data size mismatch")
 	}
 
+	c.sorted = false
 	c.matrix.I = int(i)
 	c.matrix.J = int(j)
 	c.matrix.Indptr = make([]int, indptrn)
@@ -874,25 +886,28 @@ func (c *COO) MarshalBinaryTo(w io.Writer) (int, error) {
 }
 
 // UnmarshalBinary binary deserialises the []byte into the receiver.
-// It panics if the receiver is a non-zero DIA matrix.
 //
 // See MarshalBinary for the on-disk layout.
 //
-// Limited checks on the validity of the binary input are performed:
-//  - an error is returned if the resulting compressed sprase matrix is too
+// The following checks on the validity of the binary input are performed and an
+// error returned, leaving the receiver unmodified, if any fail:
+//  - the declared number of non zero elements must match the lengths of the
+//  rows, cols and data slices and the length of the input
+//  - all row and column indices must fall within the declared dimensions
+//  - an error is returned if the resulting sparse matrix is too
 //  big for the current architecture (e.g. a 16GB matrix written by a
 //  64b application and read back from a 32b application.)
 // UnmarshalBinary does not limit the size of the unmarshaled matrix, and so
 // it should not be used on untrusted data.
 func (c *COO) UnmarshalBinary(data []byte) error {
-	if len(data) < 5*sizeInt64+2 {
+	if len(data) < 5*sizeInt64 {
 		return errors.New("sparse: data is missing required attributes")
 	}
 
 	p := 0
-	c.r = int(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
+	r := int64(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
 	p += sizeInt64
-	c.c = int(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
+	cols := int64(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
 	p += sizeInt64
 	pr := int64(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
 	p += sizeInt64
@@ -901,34 +916,36 @@ func (c *COO) UnmarshalBinary(data []byte) error {
 	pd := int64(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
 	p += sizeInt64
 
-	// if int(nnz) < 0 || nnz > maxLen {
-	// 	return errors.New("sparse: data is too big")
-	// }
-	// if r < 0 || c < 0 || r < nnz || c < nnz {
-	// 	return errors.New("sparse: dimensions/data size mismatch")
-	// }
-	// if len(data) != int(nnz)*sizeFloat64+3*sizeInt64 {
-	// 	return errors.New("sparse: data/buffer size mismatch")
-	// }
+	if err := checkCOOHeader(r, cols, pr, pc, pd); err != nil {
+		return err
+	}
+	if pd > int64(len(data)) || int64(len(data)) != 5*int64(sizeInt64)+2*pd*int64(sizeInt64)+pd*int64(sizeFloat64) {
+		return errors.New("sparse: data/buffer size mismatch")
+	}
 
-	c.rows = make([]int, pr)
-	for i := 0; i < len(c.rows); i++ {
-		c.rows[i] = int(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
+	m := COO{r: int(r), c: int(cols)}
+	m.rows = make([]int, pr)
+	for i := 0; i < len(m.rows); i++ {
+		m.rows[i] = int(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
 		p += sizeInt64
 	}
 
-	c.cols = make([]int, pc)
-	for i := 0; i < len(c.cols); i++ {
-		c.cols[i] = int(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
+	m.cols = make([]int, pc)
+	for i := 0; i < len(m.cols); i++ {
+		m.cols[i] = int(binary.LittleEndian.Uint64(data[p : p+sizeInt64]))
 		p += sizeInt64
 	}
 
-	c.data = make([]float64, pd)
-	for i := 0; i < len(c.data); i++ {
-		c.data[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[p : p+sizeFloat64]))
+	m.data = make([]float64, pd)
+	for i := 0; i < len(m.data); i++ {
+		m.data[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[p : p+sizeFloat64]))
 		p += sizeFloat64
 	}
 
+	if err := checkCOOIndices(&m); err != nil {
+		return err
+	}
+	*c = m
 	return nil
 }
 
@@ -937,100 +954,91 @@ func (c *COO) UnmarshalBinary(data []byte) error {
 //
 // See MarshalBinary for the on-disk layout.
 //
-// Limited checks on the validity of the binary input are performed:
-//  - an error is returned if the resulting compressed sparse matrix is too
-//  big for the current architecture (e.g. a 16GB matrix written by a
-//  64b application and read back from a 32b application.)
-// UnmarshalBinary does not limit the size of the unmarshaled matrix, and so
+// The same checks on the validity of the binary input are performed as for
+// UnmarshalBinary and the receiver is left unmodified if any fail.
+// UnmarshalBinaryFrom does not limit the size of the unmarshaled matrix, and so
 // it should not be used on untrusted data.
 func (c *COO) UnmarshalBinaryFrom(r io.Reader) (int, error) {
 	var n int
 	var buf [8]byte
 
-	nn, err := readUntilFull(r, buf[:])
-	n += nn
-	if err != nil {
-		return n, err
-	}
-	i := int64(binary.LittleEndian.Uint64(buf[:]))
-
-	nn, err = readUntilFull(r, buf[:])
-	n += nn
-	if err != nil {
-		return n, err
-	}
-	j := int64(binary.LittleEndian.Uint64(buf[:]))
-
-	nn, err = readUntilFull(r, buf[:])
-	n += nn
-	if err != nil {
-		return n, err
-	}
-	rcnt := int64(binary.LittleEndian.Uint64(buf[:]))
-
-	nn, err = readUntilFull(r, buf[:])
-	n += nn
-	if err != nil {
-		return n, err
+	var header [5]int64
+	for h := range header {
+		nn, err := readUntilFull(r, buf[:])
+		n += nn
+		if err != nil {
+			return n, err
+		}
+		header[h] = int64(binary.LittleEndian.Uint64(buf[:]))
 	}
-	ccnt := int64(binary.LittleEndian.Uint64(buf[:]))
-
-	nn, err = readUntilFull(r, buf[:])
-	n += nn
-	if err != nil {
+	if err := checkCOOHeader(header[0], header[1], header[2], header[3], header[4]); err != nil {
 		return n, err
 	}
-	datan := int64(binary.LittleEndian.Uint64(buf[:]))
 
-	if int(rcnt) < 0 || rcnt > maxLen {
-		return n, errors.New("sparse: data is too big")
-	}
-	if int(ccnt) < 0 || ccnt > maxLen {
-		return n, errors.New("sparse: data is too big")
-	}
-	if int(datan) < 0 || datan > maxLen {
-		return n, errors.New("sparse: data is too big")
-	}
-	if i < 0 || j < 0 {
-		return n, errors.New("sparse: dimensions/data size mismatch")
-	}
+	m := COO{r: int(header[0]), c: int(header[1])}
+	m.rows = make([]int, header[2])
+	m.cols = make([]int, header[3])
+	m.data = make([]float64, header[4])
 
-	c.r = int(i)
-	c.c = int(j)
-	c.rows = make([]int, rcnt)
-	c.cols = make([]int, ccnt)
-	c.data = make([]float64, datan)
-
-	for i := range c.rows {
-		nn, err = readUntilFull(r, buf[:])
+	for i := range m.rows {
+		nn, err := readUntilFull(r, buf[:])
 		n += nn
 		if err != nil {
 			return n, err
 		}
-		c.rows[i] = int(binary.LittleEndian.Uint64(buf[:]))
+		m.rows[i] = int(binary.LittleEndian.Uint64(buf[:]))
 	}
 
-	for i := range c.cols {
-		nn, err = readUntilFull(r, buf[:])
+	for i := range m.cols {
+		nn, err := readUntilFull(r, buf[:])
 		n += nn
 		if err != nil {
 			return n, err
 		}
-		c.cols[i] = int(binary.LittleEndian.Uint64(buf[:]))
+		m.cols[i] = int(binary.LittleEndian.Uint64(buf[:]))
 	}
 
-	for i := range c.data {
-		nn, err = readUntilFull(r, buf[:])
+	for i := range m.data {
+		nn, err := readUntilFull(r, buf[:])
 		n += nn
 		if err != nil {
 			return n, err
 		}
-		c.data[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[:]))
+		m.data[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[:]))
 	}
 
+	if err := checkCOOIndices(&m); err != nil {
+		return n, err
+	}
+	*c = m
 	return n, nil
 }
 
+// checkCOOHeader checks the dimensions and slice lengths read from the header of a
+// binary serialised COO matrix are valid and consistent with each other.
+func checkCOOHeader(r, c, nrows, ncols, nnz int64) error {
+	if r < 0 || c < 0 || r > maxLen || c > maxLen {
+		return errors.New("sparse: dimensions/data size mismatch")
+	}
+	if nnz < 0 || nnz > maxLen {
+		return errors.New("sparse: data is too big")
+	}
+	if nrows != nnz || ncols != nnz {
+		return errors.New("sparse: number of non zero elements does not match index lengths")
+	}
+	return nil
+}
+
+// checkCOOIndices checks all row and column indices of m lie within its dimensions.
+func checkCOOIndices(m *COO) error {
+	for k := range m.data {
+		if m.rows[k] < 0 || m.rows[k] >= m.r || m.cols[k] < 0 || m.cols[k] >= m.c {
+			return errors.New("sparse: index out of range")
+		}
+	}
+	return nil
+}
+
 // MarshalBinary binary serialises the receiver into a []byte and returns the result.
 //
 // DOK is little-endian encoded as follows:
@@ -1255,3 +1263,149 @@ func readUntilFull(r io.Reader, buf []byte) (int, error) {
 	}
 	return n, err
 }
+
+// GobEncode implements the gob.GobEncoder interface serialising the receiver using the
+// same layout as MarshalBinary.
+func (c *CSR) GobEncode() ([]byte, error) {
+	return c.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface deserialising data produced by
+// GobEncode (or MarshalBinary) into the receiver.  Unlike UnmarshalBinary, GobDecode
+// validates the encoded dimensions, slice lengths, index pointers and index ranges against
+// each other and the size of data, returning an error and leaving the receiver unmodified
+// if they are inconsistent.
+func (c *CSR) GobDecode(data []byte) error {
+	var m CSR
+	if err := checkCompressedBinary(data); err != nil {
+		return err
+	}
+	if err := m.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if err := checkCompressed(m.matrix.I, m.matrix.J, m.matrix.Indptr, m.matrix.Ind, m.matrix.Data, "row", "column"); err != nil {
+		return err
+	}
+	*c = m
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface serialising the receiver using the
+// same layout as MarshalBinary.
+func (c *CSC) GobEncode() ([]byte, error) {
+	return c.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface deserialising data produced by
+// GobEncode (or MarshalBinary) into the receiver.  Unlike UnmarshalBinary, GobDecode
+// validates the encoded dimensions, slice lengths, index pointers and index ranges against
+// each other and the size of data, returning an error and leaving the receiver unmodified
+// if they are inconsistent.
+func (c *CSC) GobDecode(data []byte) error {
+	var m CSC
+	if err := checkCompressedBinary(data); err != nil {
+		return err
+	}
+	if err := m.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	if err := checkCompressed(m.matrix.I, m.matrix.J, m.matrix.Indptr, m.matrix.Ind, m.matrix.Data, "column", "row"); err != nil {
+		return err
+	}
+	*c = m
+	return nil
+}
+
+// csrJSON is the JSON representation of a CSR matrix, using the same field names and layout
+// as scipy's CSR matrix attributes.
+type csrJSON struct {
+	Rows    int       `json:"rows"`
+	Cols    int       `json:"cols"`
+	Indptr  []int     `json:"indptr"`
+	Indices []int     `json:"indices"`
+	Data    []float64 `json:"data"`
+}
+
+// MarshalJSON implements the json.Marshaler interface serialising the receiver as a JSON
+// object with rows, cols, indptr, indices and data fields holding the dimensions, row index
+// pointers, column indices and values of the receiver respectively e.g.
+//   {"rows":2,"cols":3,"indptr":[0,1,2],"indices":[2,0],"data":[1,2]}
+// As JSON has no representation for them, an error is returned if the receiver stores NaN
+// or infinite values.
+func (c *CSR) MarshalJSON() ([]byte, error) {
+	m := csrJSON{
+		Rows:    c.matrix.I,
+		Cols:    c.matrix.J,
+		Indptr:  c.matrix.Indptr,
+		Indices: c.matrix.Ind,
+		Data:    c.matrix.Data,
+	}
+	if m.Indptr == nil {
+		m.Indptr = make([]int, m.Rows+1)
+	}
+	if m.Indices == nil {
+		m.Indices = []int{}
+	}
+	if m.Data == nil {
+		m.Data = []float64{}
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface deserialising a JSON object, as
+// produced by MarshalJSON, into the receiver.  The decoded matrix is validated in the same
+// way as NewCSRChecked, returning the same error and leaving the receiver unmodified if any
+// of its invariants are violated.  Column indices are not required to be sorted within each
+// row.
+func (c *CSR) UnmarshalJSON(data []byte) error {
+	var m csrJSON
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	if m.Indices == nil {
+		m.Indices = []int{}
+	}
+	if m.Data == nil {
+		m.Data = []float64{}
+	}
+	csr, err := NewCSRChecked(m.Rows, m.Cols, m.Indptr, m.Indices, m.Data)
+	if err != nil {
+		return err
+	}
+	*c = *csr
+	return nil
+}
+
+// checkCompressedBinary checks the header of a binary serialised compressed sparse
+// matrix (CSR or CSC) is self consistent and matches the length of data.
+func checkCompressedBinary(data []byte) error {
+	if len(data) < 5*sizeInt64 {
+		return errors.New("sparse: data is missing required attributes")
+	}
+	var header [5]int64
+	for i := range header {
+		header[i] = int64(binary.LittleEndian.Uint64(data[i*sizeInt64:]))
+	}
+	major, minor, nIndptr, nInd, nnz := header[0], header[1], header[2], header[3], header[4]
+	if major < 0 || minor < 0 || major >= maxLen || nIndptr != major+1 || nInd != nnz || nnz < 0 || nnz > maxLen {
+		return errors.New("sparse: dimensions/data size mismatch")
+	}
+	if int64(len(data)) != 5*int64(sizeInt64)+(nIndptr+nInd)*int64(sizeInt64)+nnz*int64(sizeFloat64) {
+		return errors.New("sparse: data/buffer size mismatch")
+	}
+	return nil
+}
+
+// checkIndptr checks the index pointers of a compressed sparse matrix start at zero,
+// are non-decreasing and end at nnz.
+func checkIndptr(indptr []int, nnz int) error {
+	if indptr[0] != 0 || indptr[len(indptr)-1] != nnz {
+		return errors.New("sparse: invalid index pointers")
+	}
+	for i := 1; i < len(indptr); i++ {
+		if indptr[i] < indptr[i-1] {
+			return errors.New("sparse: invalid index pointers")
+		}
+	}
+	return nil
+}
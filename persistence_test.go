@@ -2,6 +2,9 @@ package sparse
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
 	"testing"
 
 	"gonum.org/v1/gonum/mat"
@@ -648,6 +651,68 @@ var (
 	}
 )
 
+func TestCOOUnmarshalInvalid(t *testing.T) {
+	raw, err := NewCOO(2, 3, []int{0, 1}, []int{1, 2}, []float64{0.5, -0.5}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("error encoding: %v\n", err)
+	}
+
+	corrupt := func(offset int, value uint64) []byte {
+		b := make([]byte, len(raw))
+		copy(b, raw)
+		binary.LittleEndian.PutUint64(b[offset:], value)
+		return b
+	}
+
+	var tests = []struct {
+		desc string
+		data []byte
+	}{
+		{"truncated header", raw[:4*sizeInt64]},
+		{"truncated data", raw[:len(raw)-sizeFloat64]},
+		{"negative rows", corrupt(0, ^uint64(0))},
+		{"rows length mismatch", corrupt(2*sizeInt64, 1)},
+		{"cols length mismatch", corrupt(3*sizeInt64, 3)},
+		{"nnz mismatch", corrupt(4*sizeInt64, 1)},
+		{"huge nnz", corrupt(4*sizeInt64, 1<<62)},
+		{"row index out of range", corrupt(6*sizeInt64, 2)},
+		{"column index out of range", corrupt(8*sizeInt64, 3)},
+		{"negative column index", corrupt(7*sizeInt64, ^uint64(0))},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** TestCOOUnmarshalInvalid - Test Run %d. %s\n", ti+1, test.desc)
+
+		v := NewCOO(1, 1, []int{0}, []int{0}, []float64{7})
+		if err := v.UnmarshalBinary(test.data); err == nil {
+			t.Errorf("expected error decoding %s", test.desc)
+		}
+		if r, c := v.Dims(); r != 1 || c != 1 || v.At(0, 0) != 7 {
+			t.Errorf("receiver modified by failed decode of %s", test.desc)
+		}
+
+		if _, err := v.UnmarshalBinaryFrom(bytes.NewReader(test.data)); err == nil {
+			t.Errorf("expected error reading %s", test.desc)
+		}
+		if r, c := v.Dims(); r != 1 || c != 1 || v.At(0, 0) != 7 {
+			t.Errorf("receiver modified by failed read of %s", test.desc)
+		}
+	}
+
+	// empty matrix round trip
+	empty, err := NewCOO(2, 2, nil, nil, nil).MarshalBinary()
+	if err != nil {
+		t.Fatalf("error encoding: %v\n", err)
+	}
+	var v COO
+	if err := v.UnmarshalBinary(empty); err != nil {
+		t.Errorf("error decoding empty matrix: %v\n", err)
+	}
+	if r, c := v.Dims(); r != 2 || c != 2 || v.NNZ() != 0 {
+		t.Errorf("error decoding empty matrix: got %dx%d with %d non zeros", r, c, v.NNZ())
+	}
+}
+
 func TestDOKMarshalBinary(t *testing.T) {
 	for ti, test := range elements {
 		t.Logf("**** TestDOKMarshallBinary - Test Run %d.\n", ti+1)
@@ -786,3 +851,204 @@ func TestDOKUnmarshalFrom(t *testing.T) {
 		}
 	}
 }
+
+func TestCompressedGob(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 2, 0,
+				0, 0, 0, 0,
+				0, 3, 0, 4,
+			},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** TestCompressedGob - Test Run %d.\n", ti+1)
+
+		type payload struct {
+			Name string
+			CSR  *CSR
+			CSC  *CSC
+		}
+		in := payload{
+			Name: "matrices",
+			CSR:  CreateCSR(test.r, test.c, test.data).(*CSR),
+			CSC:  CreateCSC(test.r, test.c, test.data).(*CSC),
+		}
+
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+			t.Errorf("error encoding: %v\n", err)
+			continue
+		}
+		var out payload
+		if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+			t.Errorf("error decoding: %v\n", err)
+			continue
+		}
+
+		if !mat.Equal(in.CSR, out.CSR) || !mat.Equal(in.CSC, out.CSC) {
+			t.Errorf("error decoding: values differ.\n got=%v %v\nwant=%v %v\n", out.CSR, out.CSC, in.CSR, in.CSC)
+		}
+		if r, c := out.CSR.Dims(); r != test.r || c != test.c || len(out.CSR.RawMatrix().Indptr) != test.r+1 {
+			t.Errorf("error decoding: CSR decoded as %dx%d with %d index pointers", r, c, len(out.CSR.RawMatrix().Indptr))
+		}
+		if r, c := out.CSC.Dims(); r != test.r || c != test.c || len(out.CSC.RawMatrix().Indptr) != test.c+1 {
+			t.Errorf("error decoding: CSC decoded as %dx%d with %d index pointers", r, c, len(out.CSC.RawMatrix().Indptr))
+		}
+
+		// the decoded matrix should be fully usable
+		out.CSR.Set(0, 0, 5)
+		if out.CSR.At(0, 0) != 5 {
+			t.Errorf("error decoding: decoded CSR not usable after Set")
+		}
+	}
+}
+
+func TestCompressedGobDecodeInvalid(t *testing.T) {
+	raw, err := CreateCSR(2, 3, []float64{1, 0, 2, 0, 3, 0}).(*CSR).MarshalBinary()
+	if err != nil {
+		t.Fatalf("error encoding: %v\n", err)
+	}
+
+	corrupt := func(offset int, value uint64) []byte {
+		b := make([]byte, len(raw))
+		copy(b, raw)
+		binary.LittleEndian.PutUint64(b[offset:], value)
+		return b
+	}
+
+	var tests = []struct {
+		desc string
+		data []byte
+	}{
+		{"truncated header", raw[:3*sizeInt64]},
+		{"truncated data", raw[:len(raw)-1]},
+		{"indptr length mismatch", corrupt(0, 3)},
+		{"nnz mismatch", corrupt(4*sizeInt64, 2)},
+		{"indptr not starting at zero", corrupt(5*sizeInt64, 1)},
+		{"indptr exceeding nnz", corrupt(7*sizeInt64, 4)},
+		{"index out of range", corrupt(8*sizeInt64, 3)},
+		{"negative index", corrupt(8*sizeInt64, ^uint64(0))},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** TestCompressedGobDecodeInvalid - Test Run %d. %s\n", ti+1, test.desc)
+
+		var csr CSR
+		if err := csr.GobDecode(test.data); err == nil {
+			t.Errorf("expected error decoding CSR with %s", test.desc)
+		}
+		var csc CSC
+		if err := csc.GobDecode(test.data); err == nil {
+			t.Errorf("expected error decoding CSC with %s", test.desc)
+		}
+	}
+}
+
+func TestCSRJSON(t *testing.T) {
+	var tests = []struct {
+		m        *CSR
+		expected string
+	}{
+		{
+			m:        CreateCSR(2, 3, []float64{0, 0, 1, 2, 0, 0}).(*CSR),
+			expected: `{"rows":2,"cols":3,"indptr":[0,1,2],"indices":[2,0],"data":[1,2]}`,
+		},
+		{
+			m:        CreateCSR(2, 2, []float64{0, 0, 0, 0}).(*CSR),
+			expected: `{"rows":2,"cols":2,"indptr":[0,0,0],"indices":[],"data":[]}`,
+		},
+		{
+			// unsorted column indices are preserved
+			m:        NewCSR(1, 3, []int{0, 2}, []int{2, 0}, []float64{1.5, -2}),
+			expected: `{"rows":1,"cols":3,"indptr":[0,2],"indices":[2,0],"data":[1.5,-2]}`,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** TestCSRJSON - Test Run %d.\n", ti+1)
+
+		raw, err := json.Marshal(test.m)
+		if err != nil {
+			t.Errorf("error encoding: %v\n", err)
+			continue
+		}
+		if string(raw) != test.expected {
+			t.Errorf("error encoding: got=%s want=%s\n", raw, test.expected)
+		}
+
+		var out CSR
+		if err := json.Unmarshal(raw, &out); err != nil {
+			t.Errorf("error decoding: %v\n", err)
+			continue
+		}
+		if !mat.Equal(test.m, &out) {
+			t.Errorf("error decoding: values differ.\n got=%v\nwant=%v\n", mat.Formatted(&out), mat.Formatted(test.m))
+		}
+
+		// the decoded matrix should be fully usable
+		out.Set(0, 0, 5)
+		if out.At(0, 0) != 5 {
+			t.Errorf("error decoding: decoded CSR not usable after Set")
+		}
+	}
+}
+
+func TestCSRUnmarshalJSONInvalid(t *testing.T) {
+	var tests = []struct {
+		desc string
+		data string
+	}{
+		{"negative dimensions", `{"rows":-1,"cols":2,"indptr":[],"indices":[],"data":[]}`},
+		{"indptr length mismatch", `{"rows":2,"cols":2,"indptr":[0,1],"indices":[0],"data":[1]}`},
+		{"missing indptr", `{"rows":2,"cols":2,"indices":[],"data":[]}`},
+		{"indices/data length mismatch", `{"rows":1,"cols":2,"indptr":[0,1],"indices":[0],"data":[1,2]}`},
+		{"indptr not starting at zero", `{"rows":1,"cols":2,"indptr":[1,1],"indices":[0],"data":[1]}`},
+		{"indptr not monotonic", `{"rows":2,"cols":2,"indptr":[0,2,1],"indices":[0],"data":[1]}`},
+		{"indptr not ending at nnz", `{"rows":1,"cols":2,"indptr":[0,1],"indices":[0,1],"data":[1,2]}`},
+		{"column index out of range", `{"rows":1,"cols":2,"indptr":[0,1],"indices":[2],"data":[1]}`},
+		{"negative column index", `{"rows":1,"cols":2,"indptr":[0,1],"indices":[-1],"data":[1]}`},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** TestCSRUnmarshalJSONInvalid - Test Run %d. %s\n", ti+1, test.desc)
+
+		m := CreateCSR(1, 1, []float64{7}).(*CSR)
+		err := m.UnmarshalJSON([]byte(test.data))
+		if err == nil {
+			t.Errorf("expected error decoding CSR with %s", test.desc)
+		}
+		if r, c := m.Dims(); r != 1 || c != 1 || m.At(0, 0) != 7 {
+			t.Errorf("expected receiver to be unmodified decoding CSR with %s", test.desc)
+		}
+
+		var raw csrJSON
+		if jerr := json.Unmarshal([]byte(test.data), &raw); jerr != nil {
+			t.Fatalf("failed to decode test JSON: %v", jerr)
+		}
+		if _, cerr := NewCSRChecked(raw.Rows, raw.Cols, raw.Indptr, raw.Indices, raw.Data); err == nil || cerr == nil || err.Error() != cerr.Error() {
+			t.Errorf("expected error %v matching NewCSRChecked but received %v", cerr, err)
+		}
+	}
+
+	m := CreateCSR(1, 1, []float64{7}).(*CSR)
+	if err := m.UnmarshalJSON([]byte(`{"rows":2,`)); err == nil {
+		t.Errorf("expected error decoding malformed JSON")
+	}
+	if r, c := m.Dims(); r != 1 || c != 1 || m.At(0, 0) != 7 {
+		t.Errorf("expected receiver to be unmodified decoding malformed JSON")
+	}
+}
@@ -38,6 +38,7 @@ var (
 
 func getWorkspace(r, c, nnz int, clear bool) *CSR {
 	w := pool.Get().(*CSR)
+	w.sorted = false
 	w.matrix.Indptr = useInts(w.matrix.Indptr, r+1, false)
 	w.matrix.Ind = useInts(w.matrix.Ind, nnz, false)
 	w.matrix.Data = useFloats(w.matrix.Data, nnz, false)
@@ -102,3 +103,59 @@ func putInts(w []int) {
 		intPool.Put(w)
 	}
 }
+
+// ConversionPool is a sync.Pool backed allocator for matrices created by format
+// conversions.  Drawing converted matrices, and their underlying storage, from a
+// ConversionPool and returning them once they are no longer required allows the
+// storage to be reused across repeated conversions, reducing allocations and pressure
+// on the garbage collector in hot paths.  A ConversionPool is safe for concurrent use
+// by multiple goroutines.  The zero value is ready to use.
+type ConversionPool struct {
+	coos sync.Pool
+}
+
+// NewConversionPool returns a new, empty ConversionPool.
+func NewConversionPool() *ConversionPool {
+	return &ConversionPool{}
+}
+
+// getCOO returns a COO matrix of dimensions r x c with storage for nnz elements drawn
+// from the pool where possible.
+func (p *ConversionPool) getCOO(r, c, nnz int) *COO {
+	m, ok := p.coos.Get().(*COO)
+	if !ok {
+		m = &COO{}
+	}
+	m.r, m.c = r, c
+	m.rows = useInts(m.rows, nnz, false)
+	m.cols = useInts(m.cols, nnz, false)
+	m.data = useFloats(m.data, nnz, false)
+	return m
+}
+
+// ReleaseCOO returns the COO matrix m, along with its underlying storage, to the pool
+// so that it may be reused by subsequent conversions.  m should have been created by a
+// conversion using the receiver (e.g. CSR.ToCOOWith) and must not be used after it is
+// released as it, and its storage, may be handed out again by the pool at any time.
+// Any other references to the slices backing m must also not be retained.
+func (p *ConversionPool) ReleaseCOO(m *COO) {
+	p.coos.Put(m)
+}
+
+// ConversionOptions specifies options for format conversions.
+type ConversionOptions struct {
+	// Pool, if not nil, is used to allocate the converted matrix and its storage
+	// rather than allocating afresh.  The pooled storage backs the converted matrix
+	// until it is released back to the Pool e.g. with ConversionPool.ReleaseCOO,
+	// after which the matrix must no longer be used.
+	Pool *ConversionPool
+}
+
+// newCOO returns a COO matrix of dimensions r x c with storage for nnz elements
+// drawing it from the configured pool, if any.
+func (o ConversionOptions) newCOO(r, c, nnz int) *COO {
+	if o.Pool == nil {
+		return NewCOO(r, c, make([]int, nnz), make([]int, nnz), make([]float64, nnz))
+	}
+	return o.Pool.getCOO(r, c, nnz)
+}
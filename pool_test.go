@@ -0,0 +1,74 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestToCOOWithPool(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 7,
+				0, 2, 4, 0,
+				3, 0, 3, 6,
+			},
+		},
+		{
+			r: 4, c: 3,
+			data: []float64{
+				1, 0, 0,
+				0, 0, 5,
+				0, 0, 0,
+				2, 0, 0,
+			},
+		},
+	}
+
+	pool := NewConversionPool()
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		expected := mat.NewDense(test.r, test.c, test.data)
+		csr := CreateCSR(test.r, test.c, test.data).(*CSR)
+		csc := CreateCSC(test.r, test.c, test.data).(*CSC)
+
+		for i := 0; i < 3; i++ {
+			coo := csr.ToCOOWith(ConversionOptions{Pool: pool})
+			if !mat.Equal(expected, coo) {
+				t.Logf("CSR: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(coo))
+				t.Fail()
+			}
+			pool.ReleaseCOO(coo)
+
+			coo = csc.ToCOOWith(ConversionOptions{Pool: pool})
+			if !mat.Equal(expected, coo) {
+				t.Logf("CSC: Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(coo))
+				t.Fail()
+			}
+			pool.ReleaseCOO(coo)
+		}
+	}
+}
+
+func TestToCOOWithPoolAllocs(t *testing.T) {
+	csr := Random(CSRFormat, 100, 100, 0.1).(*CSR)
+	pool := NewConversionPool()
+
+	unpooled := testing.AllocsPerRun(100, func() {
+		csr.ToCOO()
+	})
+	pooled := testing.AllocsPerRun(100, func() {
+		pool.ReleaseCOO(csr.ToCOOWith(ConversionOptions{Pool: pool}))
+	})
+
+	if pooled >= unpooled {
+		t.Errorf("Expected fewer allocations with pool than without (%v) but received %v", unpooled, pooled)
+	}
+}
@@ -0,0 +1,66 @@
+package sparse
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// Pow returns a new CSR matrix containing the square matrix a raised to the non-negative
+// integer power k i.e. the matrix product of k copies of a.  For an adjacency matrix a,
+// element (i, j) of a^k is the number of walks of length k from node i to node j.  The
+// power is computed using exponentiation by squaring, requiring O(log k) sparse matrix
+// multiplications rather than k-1.  If k is 0, the identity matrix of the same dimensions
+// as a is returned.  The returned matrix will not share underlying storage with a nor is a
+// modified by this call.  Pow will panic with mat.ErrShape if a is not square or if k is
+// negative.  See PowPruned to drop negligible elements between multiplications.
+func Pow(a *CSR, k int) *CSR {
+	return PowPruned(a, k, -1)
+}
+
+// PowPruned returns a new CSR matrix containing the square matrix a raised to the
+// non-negative integer power k as per Pow but, as intermediate products may fill in
+// rapidly, stored elements with an absolute value less than or equal to tol are pruned
+// (see Prune) from each intermediate and the final product.  A tol of 0 removes only
+// elements that are exactly zero (e.g. where products cancelled each other out) and a
+// negative tol disables pruning.  Note that pruning intermediate products introduces an
+// error into the result which may be amplified by subsequent multiplications.
+// PowPruned will panic with mat.ErrShape if a is not square or if k is negative.
+func PowPruned(a *CSR, k int, tol float64) *CSR {
+	r, c := a.Dims()
+	if r != c || k < 0 {
+		panic(mat.ErrShape)
+	}
+
+	mul := func(x, y *CSR) *CSR {
+		var product CSR
+		product.Mul(x, y)
+		if tol >= 0 {
+			product.Prune(tol)
+		}
+		return &product
+	}
+
+	var result *CSR
+	base := a
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			if result == nil {
+				result = &CSR{}
+				result.Clone(base)
+				if tol >= 0 {
+					result.Prune(tol)
+				}
+			} else {
+				result = mul(result, base)
+			}
+		}
+		if k > 1 {
+			base = mul(base, base)
+		}
+	}
+
+	if result == nil {
+		// a^0 is the identity
+		result = Identity(r)
+	}
+	return result
+}
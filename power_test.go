@@ -0,0 +1,89 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPow(t *testing.T) {
+	// adjacency matrix of directed graph 0 -> 1 -> 2 -> 0, 1 -> 3
+	data := []float64{
+		0, 1, 0, 0,
+		0, 0, 1, 1,
+		1, 0, 0, 0,
+		0, 0, 0, 0,
+	}
+
+	for k := 0; k <= 9; k++ {
+		t.Logf("**** Test Run %d.\n", k+1)
+
+		a := CreateCSR(4, 4, data).(*CSR)
+		expected := mat.NewDense(4, 4, nil)
+		for i := 0; i < 4; i++ {
+			expected.Set(i, i, 1)
+		}
+		for i := 0; i < k; i++ {
+			expected.Mul(expected, a)
+		}
+
+		result := Pow(a, k)
+
+		if !mat.Equal(expected, result) {
+			t.Logf("Expected A^%d:\n%v\n but received:\n%v\n", k, mat.Formatted(expected), mat.Formatted(result))
+			t.Fail()
+		}
+		if !mat.Equal(a, mat.NewDense(4, 4, data)) {
+			t.Logf("Expected a to be unmodified but was:\n%v\n", mat.Formatted(a))
+			t.Fail()
+		}
+	}
+}
+
+func TestPowPruned(t *testing.T) {
+	a := CreateCSR(2, 2, []float64{
+		0.5, 1e-3,
+		0, 0.5,
+	}).(*CSR)
+
+	result := PowPruned(a, 4, 1e-2)
+	expected := mat.NewDense(2, 2, []float64{
+		0.0625, 0,
+		0, 0.0625,
+	})
+	if !mat.EqualApprox(expected, result, 1e-12) {
+		t.Errorf("Expected:\n%v\n but received:\n%v", mat.Formatted(expected), mat.Formatted(result))
+	}
+	if result.NNZ() != 2 {
+		t.Errorf("Expected 2 stored elements but received %d", result.NNZ())
+	}
+
+	unpruned := Pow(a, 4)
+	if unpruned.NNZ() != 3 {
+		t.Errorf("Expected 3 stored elements without pruning but received %d", unpruned.NNZ())
+	}
+}
+
+func TestPowPanics(t *testing.T) {
+	var tests = []struct {
+		a *CSR
+		k int
+	}{
+		{a: CreateCSR(2, 3, nil).(*CSR), k: 2},
+		{a: CreateCSR(2, 2, nil).(*CSR), k: -1},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		func() {
+			defer func() {
+				if r := recover(); r != mat.ErrShape {
+					t.Logf("Expected panic with mat.ErrShape but received %v\n", r)
+					t.Fail()
+				}
+			}()
+			Pow(test.a, test.k)
+		}()
+	}
+}
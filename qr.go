@@ -0,0 +1,116 @@
+package sparse
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// RankRevealingQR computes a column pivoted QR factorisation A*P = Q*R of the sparse
+// matrix a using Householder reflections and returns the numerical rank of a along
+// with the column permutation P.  perm[k] is the index of the column of a that was
+// moved to position k so the first rank entries of perm identify a set of linearly
+// independent columns and the remaining entries identify the columns that are
+// (numerically) dependent upon them.  Neither Q nor R are formed explicitly.
+//
+// The pivoting strategy is that of Businger and Golub: at step k, the remaining column
+// with the largest Euclidean norm below row k-1 (i.e. the norm of the part of the column
+// not yet reduced into R) is swapped into position k before the Householder reflection
+// is applied.  Ties are broken in favour of the column appearing first.  As the
+// diagonal values of R are then non-increasing in magnitude, factorisation stops as soon
+// as the largest remaining norm is less than or equal to tol and the rank is the number
+// of diagonal values of R greater than tol.  Column norms are recomputed at each step
+// rather than downdated to avoid the cancellation errors of norm downdating.
+//
+// The columns are held as sparse vectors throughout the factorisation and so each
+// reflection only introduces fill-in where the Householder vector and a column
+// overlap.  RankRevealingQR returns an error if tol is negative or NaN.
+func RankRevealingQR(a *CSR, tol float64) (rank int, perm []int, err error) {
+	if tol < 0 || math.IsNaN(tol) {
+		return 0, nil, errors.New("sparse: tolerance must be non-negative")
+	}
+
+	r, c := a.Dims()
+	csc := a.ToCSC()
+	cols := make([]*Vector, c)
+	perm = make([]int, c)
+	for j := 0; j < c; j++ {
+		begin, end := csc.matrix.Indptr[j], csc.matrix.Indptr[j+1]
+		ind := make([]int, end-begin)
+		data := make([]float64, end-begin)
+		copy(ind, csc.matrix.Ind[begin:end])
+		copy(data, csc.matrix.Data[begin:end])
+		cols[j] = NewVector(r, ind, data)
+		cols[j].Sort()
+		perm[j] = j
+	}
+
+	steps := min(r, c)
+	for k := 0; k < steps; k++ {
+		p := k
+		maxNorm := -1.0
+		for j := k; j < c; j++ {
+			if norm := trailingNorm(cols[j], k); norm > maxNorm {
+				p, maxNorm = j, norm
+			}
+		}
+		if maxNorm <= tol {
+			break
+		}
+		cols[k], cols[p] = cols[p], cols[k]
+		perm[k], perm[p] = perm[p], perm[k]
+		rank++
+
+		// form the Householder vector v = x - alpha*e_k from the trailing part of
+		// column k choosing the sign of alpha to avoid cancellation.
+		x := cols[k]
+		start := sort.SearchInts(x.ind, k)
+		var xk float64
+		if start < len(x.ind) && x.ind[start] == k {
+			xk = x.data[start]
+		}
+		alpha := -math.Copysign(maxNorm, xk)
+		v := &Vector{len: r}
+		if xk == 0 {
+			v.ind = append(v.ind, k)
+			v.data = append(v.data, -alpha)
+		}
+		for i := start; i < len(x.ind); i++ {
+			val := x.data[i]
+			if x.ind[i] == k {
+				val -= alpha
+			}
+			v.ind = append(v.ind, x.ind[i])
+			v.data = append(v.data, val)
+		}
+		vtv := 2 * maxNorm * (maxNorm + math.Abs(xk))
+
+		// apply H = I - 2*v*v'/(v'*v) to the remaining columns
+		for j := k + 1; j < c; j++ {
+			s := Dot(v, cols[j])
+			if s == 0 {
+				continue
+			}
+			updated := &Vector{}
+			updated.AddScaledVec(cols[j], -2*s/vtv, v)
+			updated.Sort()
+			cols[j] = updated
+		}
+
+		// column k is reduced to R[0:k+1, k] with alpha on the diagonal
+		x.ind = append(x.ind[:start], k)
+		x.data = append(x.data[:start], alpha)
+	}
+
+	return rank, perm, nil
+}
+
+// trailingNorm returns the Euclidean norm of the elements of the sparse vector v with
+// indices greater than or equal to k.  The indices of v must be sorted.
+func trailingNorm(v *Vector, k int) float64 {
+	var sum float64
+	for i := sort.SearchInts(v.ind, k); i < len(v.ind); i++ {
+		sum += v.data[i] * v.data[i]
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,95 @@
+package sparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRankRevealingQR(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+		tol  float64
+		rank int
+		perm []int
+	}{
+		{ // full rank, pivots taken in order of decreasing column norm
+			r: 3, c: 3,
+			data: []float64{
+				1, 0, 0,
+				0, 3, 0,
+				0, 0, 2,
+			},
+			tol:  1e-12,
+			rank: 3,
+			perm: []int{1, 2, 0},
+		},
+		{ // column 2 = 2 * column 0
+			r: 4, c: 3,
+			data: []float64{
+				1, 0, 2,
+				0, 1, 0,
+				0, 1, 0,
+				0, 0, 0,
+			},
+			tol:  1e-12,
+			rank: 2,
+			perm: []int{2, 1, 0},
+		},
+		{ // column 1 = 2 * column 0 and column 3 = column 0 - column 2
+			r: 5, c: 4,
+			data: []float64{
+				1, 2, 0, 1,
+				0, 0, 1, -1,
+				2, 4, 0, 2,
+				0, 0, 3, -3,
+				1, 2, 1, 0,
+			},
+			tol:  1e-10,
+			rank: 2,
+		},
+		{ // zero matrix
+			r: 3, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+				0, 0,
+			},
+			tol:  1e-12,
+			rank: 0,
+			perm: []int{0, 1},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := CreateCSR(test.r, test.c, test.data).(*CSR)
+		rank, perm, err := RankRevealingQR(a, test.tol)
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		if rank != test.rank {
+			t.Logf("Expected rank %d but received %d\n", test.rank, rank)
+			t.Fail()
+		}
+		if test.perm != nil && !reflect.DeepEqual(perm, test.perm) {
+			t.Logf("Expected permutation %v but received %v\n", test.perm, perm)
+			t.Fail()
+		}
+		seen := make([]bool, test.c)
+		for _, p := range perm {
+			if p < 0 || p >= test.c || seen[p] {
+				t.Logf("Invalid permutation %v\n", perm)
+				t.Fail()
+				break
+			}
+			seen[p] = true
+		}
+	}
+
+	if _, _, err := RankRevealingQR(CreateCSR(2, 2, []float64{1, 0, 0, 1}).(*CSR), -1); err == nil {
+		t.Errorf("Expected error for negative tolerance")
+	}
+}
@@ -0,0 +1,292 @@
+package sparse
+
+import (
+	"math"
+
+	"github.com/james-bowman/sparse/blas"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// RowSums returns a slice of length rows containing the sum of the stored elements of
+// each row of the receiver.  The sums are computed in O(NNZ) by iterating directly over
+// the compressed row data.  See RowSumsTo to avoid allocating and RowSumsKahan for
+// improved accuracy when summing very long rows.
+func (c *CSR) RowSums() []float64 {
+	return c.RowSumsTo(nil)
+}
+
+// RowSumsTo computes the sum of the stored elements of each row of the receiver as per
+// RowSums, storing the result in dst and returning it.  If dst is nil, a new slice of
+// the correct length will be allocated.  RowSumsTo will panic if dst is not nil and its
+// length is not equal to the number of rows of the receiver.
+func (c *CSR) RowSumsTo(dst []float64) []float64 {
+	return compressedSums(&c.matrix, dst)
+}
+
+// ColSums returns a slice of length cols containing the sum of the stored elements of
+// each column of the receiver.  The sums are computed in O(NNZ) by iterating directly
+// over the compressed column data.  See ColSumsTo to avoid allocating.
+func (c *CSC) ColSums() []float64 {
+	return c.ColSumsTo(nil)
+}
+
+// ColSumsTo computes the sum of the stored elements of each column of the receiver as per
+// ColSums, storing the result in dst and returning it.  If dst is nil, a new slice of
+// the correct length will be allocated.  ColSumsTo will panic if dst is not nil and its
+// length is not equal to the number of columns of the receiver.
+func (c *CSC) ColSumsTo(dst []float64) []float64 {
+	return compressedSums(&c.matrix, dst)
+}
+
+// compressedSums sums the elements of each compressed vector (row for CSR or column for
+// CSC) of m into dst, allocating dst if it is nil.
+func compressedSums(m *blas.SparseMatrix, dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, m.I)
+	} else if len(dst) != m.I {
+		panic(mat.ErrShape)
+	}
+	for i := range dst {
+		var sum float64
+		for _, v := range m.Data[m.Indptr[i]:m.Indptr[i+1]] {
+			sum += v
+		}
+		dst[i] = sum
+	}
+	return dst
+}
+
+// NormalizeRowsL1 divides, in place, the stored values of each row of the receiver by the L1
+// norm of the row (the sum of the absolute values of its elements) so that the absolute values
+// of each row sum to 1 e.g. to convert a non-negative matrix of weights into the transition
+// matrix of a Markov chain.  All-zero rows, including rows with no stored elements, are left
+// untouched.  NormalizeRowsL1 returns a slice of length rows containing the norm of each row
+// before normalisation (0 for all-zero rows) which may be passed to ScaleRows to invert the
+// normalisation.
+func (c *CSR) NormalizeRowsL1() []float64 {
+	return c.normalizeRows(1)
+}
+
+// NormalizeRowsL2 divides, in place, the stored values of each row of the receiver by the L2
+// (Euclidean) norm of the row so that each row has unit length e.g. prior to computing cosine
+// similarities between rows.  All-zero rows, including rows with no stored elements, are left
+// untouched.  NormalizeRowsL2 returns a slice of length rows containing the norm of each row
+// before normalisation (0 for all-zero rows) which may be passed to ScaleRows to invert the
+// normalisation.
+func (c *CSR) NormalizeRowsL2() []float64 {
+	return c.normalizeRows(2)
+}
+
+// normalizeRows divides the stored values of each row of the receiver by the specified
+// norm of the row, skipping all-zero rows, and returns the norms.
+func (c *CSR) normalizeRows(L float64) []float64 {
+	norms := make([]float64, c.matrix.I)
+	for i := range norms {
+		row := c.matrix.Data[c.matrix.Indptr[i]:c.matrix.Indptr[i+1]]
+		norm := floats.Norm(row, L)
+		if norm == 0 {
+			continue
+		}
+		for k := range row {
+			row[k] /= norm
+		}
+		norms[i] = norm
+	}
+	return norms
+}
+
+// Sum returns the sum of all the elements of the receiver.  As only the stored
+// elements can be non-zero, the sum is computed in O(NNZ) directly from the stored
+// values.  Duplicate stored elements are all included in the sum, consistent with
+// them being summed when read with At.
+func (c *CSR) Sum() float64 {
+	return floats.Sum(c.matrix.Data)
+}
+
+// Sum returns the sum of all the elements of the receiver.  As only the stored
+// elements can be non-zero, the sum is computed in O(NNZ) directly from the stored
+// values.  Duplicate stored elements are all included in the sum, consistent with
+// them being summed when read with At.
+func (c *CSC) Sum() float64 {
+	return floats.Sum(c.matrix.Data)
+}
+
+// Sum returns the sum of all the elements of the receiver computed in O(NNZ) directly
+// from the stored values.  The COO format permits duplicate elements for the same
+// row and column which, when the matrix has not been canonicalized (see
+// Canonicalize), are each included in the sum.  This reflects the logical value of
+// the matrix as, like At and conversion to other formats, duplicate elements are
+// summed together rather than one overwriting another.
+func (c *COO) Sum() float64 {
+	return floats.Sum(c.data)
+}
+
+// RowSumsKahan returns a vector containing the sum of the elements of each row of the
+// receiver, computed using Kahan compensated summation.  Kahan summation tracks the low
+// order bits lost to rounding at each addition and feeds them back into the next
+// addition so that the error of each sum is essentially independent of the number of
+// elements summed, rather than growing with it as for naive summation.  This matters
+// for very long rows (e.g. millions of non-zero elements) or rows whose elements vary
+// greatly in magnitude where small elements would otherwise be lost when added to a
+// large running total.  For short rows, or rows of similar magnitude values, naive
+// summation is faster and equally accurate.
+func (c *CSR) RowSumsKahan() *mat.VecDense {
+	sums := make([]float64, c.matrix.I)
+	for i := range sums {
+		var sum, comp float64
+		for k := c.matrix.Indptr[i]; k < c.matrix.Indptr[i+1]; k++ {
+			y := c.matrix.Data[k] - comp
+			t := sum + y
+			comp = (t - sum) - y
+			sum = t
+		}
+		sums[i] = sum
+	}
+	return mat.NewVecDense(c.matrix.I, sums)
+}
+
+// Max returns the row and column index and value of the maximum element of the
+// receiver.  The stored elements are scanned in O(NNZ) in row order and then storage
+// order within each row, with ties resolved in favour of the first element visited.
+// Implicit zero elements are also candidates: if the matrix has any implicit zero
+// elements and all the stored elements are negative then the position of the first
+// implicit zero is returned i.e. the lowest column index not stored in the first row
+// that has fewer stored elements than columns.  An implicit zero is only returned in
+// preference to a stored element if it is strictly greater, so a stored zero is
+// returned in preference to an implicit one.  Duplicate stored elements are
+// considered individually rather than summed.  Max will panic with mat.ErrZeroLength
+// if the receiver has zero rows or columns.
+func (c *CSR) Max() (i, j int, v float64) {
+	return compressedExtreme(&c.matrix, func(x, y float64) bool { return x > y })
+}
+
+// Min returns the row and column index and value of the minimum element of the
+// receiver.  Ties and implicit zero elements are resolved as for Max so, if the
+// matrix has any implicit zero elements and all the stored elements are positive,
+// the position of the first implicit zero is returned.  Min will panic with
+// mat.ErrZeroLength if the receiver has zero rows or columns.
+func (c *CSR) Min() (i, j int, v float64) {
+	return compressedExtreme(&c.matrix, func(x, y float64) bool { return x < y })
+}
+
+// MaxAbs returns the row and column index and value of the element of the receiver
+// with the largest absolute value.  The returned value v is the (signed) value of the
+// element rather than its absolute value.  Ties are resolved as for Max, in favour of
+// the first stored element visited, so the position of an implicit zero element is
+// only returned if the receiver has no stored elements.  MaxAbs will panic with
+// mat.ErrZeroLength if the receiver has zero rows or columns.
+func (c *CSR) MaxAbs() (i, j int, v float64) {
+	return compressedExtreme(&c.matrix, func(x, y float64) bool { return math.Abs(x) > math.Abs(y) })
+}
+
+// compressedExtreme returns the position and value of the element of the CSR format
+// matrix m that is better than all others according to better, including implicit
+// zero elements as candidates if m has any.
+func compressedExtreme(m *blas.SparseMatrix, better func(x, y float64) bool) (i, j int, v float64) {
+	if m.I == 0 || m.J == 0 {
+		panic(mat.ErrZeroLength)
+	}
+
+	found := false
+	zeroRow := -1
+	for r := 0; r < m.I; r++ {
+		begin, end := m.Indptr[r], m.Indptr[r+1]
+		if zeroRow == -1 && end-begin < m.J {
+			zeroRow = r
+		}
+		for k := begin; k < end; k++ {
+			if !found || better(m.Data[k], v) {
+				i, j, v = r, m.Ind[k], m.Data[k]
+				found = true
+			}
+		}
+	}
+
+	if zeroRow == -1 || (found && !better(0, v)) {
+		return i, j, v
+	}
+
+	// locate the first implicit zero within the row
+	stored := getInts(m.J, true)
+	defer putInts(stored)
+	for _, col := range m.Ind[m.Indptr[zeroRow]:m.Indptr[zeroRow+1]] {
+		stored[col] = 1
+	}
+	for col, s := range stored {
+		if s == 0 {
+			return zeroRow, col, 0
+		}
+	}
+	panic("sparse: no implicit zero element found")
+}
+
+// Norm returns the specified norm of the receiver, mirroring mat.Norm.  The supported
+// norms are:
+//
+//	1 - The maximum absolute column sum
+//	2 - The Frobenius norm, the square root of the sum of the squares of the elements
+//	Inf - The maximum absolute row sum
+//
+// The norms are computed directly from the stored elements without densifying.  The
+// infinity norm is computed row by row and the 1 norm by accumulating absolute column
+// sums in a single pass over the stored elements.  Norm will panic with
+// mat.ErrNormOrder if an unsupported norm is specified.
+func (c *CSR) Norm(norm float64) float64 {
+	return compressedNorm(&c.matrix, norm, true)
+}
+
+// Norm returns the specified norm of the receiver, mirroring mat.Norm.  The supported
+// norms are:
+//
+//	1 - The maximum absolute column sum
+//	2 - The Frobenius norm, the square root of the sum of the squares of the elements
+//	Inf - The maximum absolute row sum
+//
+// The norms are computed directly from the stored elements without densifying.  The
+// 1 norm is computed column by column and the infinity norm by accumulating absolute
+// row sums in a single pass over the stored elements.  Norm will panic with
+// mat.ErrNormOrder if an unsupported norm is specified.
+func (c *CSC) Norm(norm float64) float64 {
+	return compressedNorm(&c.matrix, norm, false)
+}
+
+// compressedNorm returns the specified norm of the compressed sparse matrix m.
+// rowMajor indicates whether the primary (compressed) axis of m is rows (CSR) or
+// columns (CSC).
+func compressedNorm(m *blas.SparseMatrix, norm float64, rowMajor bool) float64 {
+	// the zero value matrix has no index pointers and is treated as empty
+	var nnz int
+	if len(m.Indptr) > 0 {
+		nnz = m.Indptr[m.I]
+	}
+
+	switch {
+	case norm == 2:
+		return floats.Norm(m.Data[:nnz], 2)
+	case norm == 1 && !rowMajor, math.IsInf(norm, 1) && rowMajor:
+		// maximum absolute sum along the primary axis
+		var max float64
+		for i := 0; i < m.I; i++ {
+			var sum float64
+			for _, v := range m.Data[m.Indptr[i]:m.Indptr[i+1]] {
+				sum += math.Abs(v)
+			}
+			max = math.Max(max, sum)
+		}
+		return max
+	case norm == 1, math.IsInf(norm, 1):
+		// maximum absolute sum along the secondary axis
+		sums := getFloats(m.J, true)
+		defer putFloats(sums)
+		for k, j := range m.Ind[:nnz] {
+			sums[j] += math.Abs(m.Data[k])
+		}
+		var max float64
+		for _, sum := range sums {
+			max = math.Max(max, sum)
+		}
+		return max
+	}
+	panic(mat.ErrNormOrder)
+}
@@ -0,0 +1,422 @@
+package sparse
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCompressedRowColSums(t *testing.T) {
+	var tests = []struct {
+		r, c    int
+		data    []float64
+		rowSums []float64
+		colSums []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 0, 7,
+				0, 0, 0, 0,
+				3, 0, -3, 6,
+			},
+			rowSums: []float64{8, 0, 6},
+			colSums: []float64{4, 0, -3, 13},
+		},
+		{
+			r: 1, c: 1,
+			data:    []float64{0},
+			rowSums: []float64{0},
+			colSums: []float64{0},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		csr := CreateCSR(test.r, test.c, test.data).(*CSR)
+		csc := CreateCSC(test.r, test.c, test.data).(*CSC)
+
+		if result := csr.RowSums(); !reflect.DeepEqual(result, test.rowSums) {
+			t.Logf("Expected row sums %v but received %v\n", test.rowSums, result)
+			t.Fail()
+		}
+		if result := csc.ColSums(); !reflect.DeepEqual(result, test.colSums) {
+			t.Logf("Expected column sums %v but received %v\n", test.colSums, result)
+			t.Fail()
+		}
+
+		// destination slices are reused and returned, overwriting existing values
+		rowDst := make([]float64, test.r)
+		for i := range rowDst {
+			rowDst[i] = 99
+		}
+		if result := csr.RowSumsTo(rowDst); &result[0] != &rowDst[0] || !reflect.DeepEqual(rowDst, test.rowSums) {
+			t.Logf("Expected row sums %v in supplied slice but received %v\n", test.rowSums, result)
+			t.Fail()
+		}
+		colDst := make([]float64, test.c)
+		for i := range colDst {
+			colDst[i] = 99
+		}
+		if result := csc.ColSumsTo(colDst); &result[0] != &colDst[0] || !reflect.DeepEqual(colDst, test.colSums) {
+			t.Logf("Expected column sums %v in supplied slice but received %v\n", test.colSums, result)
+			t.Fail()
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != mat.ErrShape {
+			t.Errorf("Expected panic with mat.ErrShape for wrong length destination but received %v", r)
+		}
+	}()
+	CreateCSR(2, 3, nil).(*CSR).RowSumsTo(make([]float64, 3))
+}
+
+func TestSum(t *testing.T) {
+	var tests = []struct {
+		r, c     int
+		rows     []int
+		cols     []int
+		data     []float64
+		expected float64
+	}{
+		{
+			r: 3, c: 4,
+			rows:     []int{0, 0, 2, 2, 2},
+			cols:     []int{0, 3, 0, 2, 3},
+			data:     []float64{1, 7, 3, -3, 6},
+			expected: 14,
+		},
+		{
+			// duplicate elements
+			r: 2, c: 2,
+			rows:     []int{0, 1, 0, 1},
+			cols:     []int{0, 1, 0, 0},
+			data:     []float64{1, 2, 3, 4},
+			expected: 10,
+		},
+		{
+			r: 2, c: 2,
+			expected: 0,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		coo := NewCOO(test.r, test.c, test.rows, test.cols, test.data)
+		dense := mat.NewDense(test.r, test.c, nil)
+		dense.Copy(coo)
+		if expected := mat.Sum(dense); expected != test.expected {
+			t.Logf("Expected logical sum %v but received %v\n", test.expected, expected)
+			t.Fail()
+		}
+
+		if result := coo.Sum(); result != test.expected {
+			t.Logf("Expected COO sum %v but received %v\n", test.expected, result)
+			t.Fail()
+		}
+		if result := coo.ToCSR().Sum(); result != test.expected {
+			t.Logf("Expected CSR sum %v but received %v\n", test.expected, result)
+			t.Fail()
+		}
+		if result := coo.ToCSC().Sum(); result != test.expected {
+			t.Logf("Expected CSC sum %v but received %v\n", test.expected, result)
+			t.Fail()
+		}
+	}
+}
+
+func TestCSRMaxMin(t *testing.T) {
+	type result struct {
+		i, j int
+		v    float64
+	}
+	var tests = []struct {
+		r, c   int
+		data   []float64
+		max    result
+		min    result
+		maxAbs result
+	}{
+		{
+			r: 3, c: 3,
+			data: []float64{
+				1, 0, 7,
+				0, 0, 0,
+				7, -9, 2,
+			},
+			max:    result{0, 2, 7},
+			min:    result{2, 1, -9},
+			maxAbs: result{2, 1, -9},
+		},
+		{
+			// all stored elements negative with implicit zeros
+			r: 2, c: 3,
+			data: []float64{
+				-1, -2, -3,
+				-4, 0, -5,
+			},
+			max:    result{1, 1, 0},
+			min:    result{1, 2, -5},
+			maxAbs: result{1, 2, -5},
+		},
+		{
+			// all stored elements positive with implicit zeros
+			r: 2, c: 2,
+			data: []float64{
+				0, 3,
+				3, 1,
+			},
+			max:    result{0, 1, 3},
+			min:    result{0, 0, 0},
+			maxAbs: result{0, 1, 3},
+		},
+		{
+			// fully dense so no implicit zeros
+			r: 2, c: 2,
+			data: []float64{
+				2, 3,
+				4, 1,
+			},
+			max:    result{1, 0, 4},
+			min:    result{1, 1, 1},
+			maxAbs: result{1, 0, 4},
+		},
+		{
+			// no stored elements
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+			max:    result{0, 0, 0},
+			min:    result{0, 0, 0},
+			maxAbs: result{0, 0, 0},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		csr := CreateCSR(test.r, test.c, test.data).(*CSR)
+
+		if i, j, v := csr.Max(); (result{i, j, v}) != test.max {
+			t.Logf("Expected Max %v but received %v\n", test.max, result{i, j, v})
+			t.Fail()
+		}
+		if i, j, v := csr.Min(); (result{i, j, v}) != test.min {
+			t.Logf("Expected Min %v but received %v\n", test.min, result{i, j, v})
+			t.Fail()
+		}
+		if i, j, v := csr.MaxAbs(); (result{i, j, v}) != test.maxAbs {
+			t.Logf("Expected MaxAbs %v but received %v\n", test.maxAbs, result{i, j, v})
+			t.Fail()
+		}
+	}
+
+	// explicitly stored zero is preferred over implicit zero
+	csr := NewCSR(2, 2, []int{0, 2, 3}, []int{0, 1, 1}, []float64{-1, 0, -2})
+	if i, j, v := csr.Max(); i != 0 || j != 1 || v != 0 {
+		t.Errorf("Expected Max of stored zero at (0, 1) but received (%d, %d) %v", i, j, v)
+	}
+
+	defer func() {
+		if r := recover(); r != mat.ErrZeroLength {
+			t.Errorf("Expected panic with mat.ErrZeroLength but received %v", r)
+		}
+	}()
+	(&CSR{}).Max()
+}
+
+func TestRowSumsAllocs(t *testing.T) {
+	csr := CreateCSR(3, 3, []float64{1, 2, 0, 0, 3, 0, 4, 0, 5}).(*CSR)
+	dst := make([]float64, 3)
+	if allocs := testing.AllocsPerRun(10, func() { csr.RowSumsTo(dst) }); allocs != 0 {
+		t.Errorf("Expected no allocations with preallocated destination but received %v", allocs)
+	}
+}
+
+func TestCSRRowSumsKahan(t *testing.T) {
+	csr := CreateCSR(3, 4, []float64{
+		1, 0, 0, 7,
+		0, 0, 0, 0,
+		3, 0, 3, 6,
+	}).(*CSR)
+
+	expected := mat.NewVecDense(3, []float64{8, 0, 12})
+	if result := csr.RowSumsKahan(); !mat.Equal(expected, result) {
+		t.Errorf("Expected row sums %v but received %v", mat.Formatted(expected.T()), mat.Formatted(result.T()))
+	}
+}
+
+func TestCSRRowSumsKahanPrecision(t *testing.T) {
+	// a single row of 1 followed by many tiny values each lost to rounding
+	// when naively added to the running total of 1
+	n := 10000
+	tiny := 1e-16
+	ind := make([]int, n+1)
+	data := make([]float64, n+1)
+	data[0] = 1
+	for j := 1; j <= n; j++ {
+		ind[j] = j
+		data[j] = tiny
+	}
+	csr := NewCSR(1, n+1, []int{0, n + 1}, ind, data)
+
+	exact := 1 + float64(n)*tiny
+
+	var naive float64
+	csr.DoNonZero(func(i, j int, v float64) {
+		naive += v
+	})
+
+	kahan := csr.RowSumsKahan().AtVec(0)
+
+	if math.Abs(kahan-exact) >= math.Abs(naive-exact) {
+		t.Errorf("Expected Kahan sum %v to be more accurate than naive sum %v (exact %v)", kahan, naive, exact)
+	}
+	if math.Abs(kahan-exact) > 1e-15 {
+		t.Errorf("Expected Kahan sum %v to be within 1e-15 of %v", kahan, exact)
+	}
+}
+
+func TestCompressedNorm(t *testing.T) {
+	var tests = []struct {
+		r, c int
+		data []float64
+	}{
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, -2, 7,
+				0, 0, 0, 0,
+				-3, 0, 3, -6,
+			},
+		},
+		{
+			r: 4, c: 2,
+			data: []float64{
+				0, -1,
+				2, 0,
+				0, 5,
+				-8, 0,
+			},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				0, 0,
+				0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		dense := mat.NewDense(test.r, test.c, test.data)
+		for _, norm := range []float64{1, 2, math.Inf(1)} {
+			expected := mat.Norm(dense, norm)
+			for _, m := range []mat.Matrix{
+				CreateCSR(test.r, test.c, test.data),
+				CreateCSC(test.r, test.c, test.data),
+				CreateCOO(test.r, test.c, test.data),
+				CreateDOK(test.r, test.c, test.data),
+				dense,
+			} {
+				if result := Norm(m, norm); math.Abs(result-expected) > 1e-12 {
+					t.Logf("%T: Expected %v norm %v but received %v\n", m, norm, expected, result)
+					t.Fail()
+				}
+			}
+		}
+	}
+
+	for _, norm := range []float64{1, 2, math.Inf(1)} {
+		if result := (&CSR{}).Norm(norm); result != 0 {
+			t.Errorf("Expected %v norm of zero value CSR to be 0 but received %v", norm, result)
+		}
+		if result := (&CSC{}).Norm(norm); result != 0 {
+			t.Errorf("Expected %v norm of zero value CSC to be 0 but received %v", norm, result)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != mat.ErrNormOrder {
+			t.Errorf("Expected panic with mat.ErrNormOrder but received %v", r)
+		}
+	}()
+	Norm(CreateCSR(2, 2, nil), 3)
+}
+
+func TestCSRNormalizeRows(t *testing.T) {
+	data := []float64{
+		1, 0, 3, 0,
+		0, 0, 0, 0,
+		0, -3, 0, 4,
+		0, 0, 0, 0,
+	}
+	var tests = []struct {
+		normalize func(c *CSR) []float64
+		norms     []float64
+		expected  []float64
+	}{
+		{
+			normalize: (*CSR).NormalizeRowsL1,
+			norms:     []float64{4, 0, 7, 0},
+			expected: []float64{
+				0.25, 0, 0.75, 0,
+				0, 0, 0, 0,
+				0, -3.0 / 7, 0, 4.0 / 7,
+				0, 0, 0, 0,
+			},
+		},
+		{
+			normalize: (*CSR).NormalizeRowsL2,
+			norms:     []float64{math.Sqrt(10), 0, 5, 0},
+			expected: []float64{
+				1 / math.Sqrt(10), 0, 3 / math.Sqrt(10), 0,
+				0, 0, 0, 0,
+				0, -0.6, 0, 0.8,
+				0, 0, 0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		// row 3 has an explicitly stored zero
+		csr := CreateCSR(4, 4, data).(*CSR)
+		csr.Set(3, 1, 1)
+		csr.Set(3, 1, 0)
+
+		norms := test.normalize(csr)
+		if !floats.EqualApprox(test.norms, norms, 1e-14) {
+			t.Logf("Expected norms %v but received %v\n", test.norms, norms)
+			t.Fail()
+		}
+		if expected := mat.NewDense(4, 4, test.expected); !mat.EqualApprox(expected, csr, 1e-14) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csr))
+			t.Fail()
+		}
+		for i := 0; i < 4; i++ {
+			if math.IsNaN(csr.At(i, 1)) {
+				t.Logf("Expected all-zero row %d to be untouched but received NaN\n", i)
+				t.Fail()
+			}
+		}
+
+		// the returned norms should invert the normalisation
+		csr.ScaleRows(norms)
+		if expected := mat.NewDense(4, 4, data); !mat.EqualApprox(expected, csr, 1e-14) {
+			t.Logf("Expected inverted normalisation:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csr))
+			t.Fail()
+		}
+	}
+}
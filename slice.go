@@ -0,0 +1,171 @@
+package sparse
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// SelectRows returns a new CSR matrix containing only the rows of the receiver for which
+// the corresponding element of mask is true, in their original order, with the
+// remaining rows compacted together.  The returned matrix will not share underlying
+// storage with the receiver nor is the receiver modified by this call.  SelectRows will
+// panic if the length of mask does not equal the number of rows in the receiver.
+func (c *CSR) SelectRows(mask []bool) *CSR {
+	if len(mask) != c.matrix.I {
+		panic(mat.ErrShape)
+	}
+
+	var r, nnz int
+	for i, keep := range mask {
+		if keep {
+			r++
+			nnz += c.matrix.Indptr[i+1] - c.matrix.Indptr[i]
+		}
+	}
+
+	indptr := make([]int, 1, r+1)
+	ind := make([]int, 0, nnz)
+	data := make([]float64, 0, nnz)
+	for i, keep := range mask {
+		if !keep {
+			continue
+		}
+		begin, end := c.matrix.Indptr[i], c.matrix.Indptr[i+1]
+		ind = append(ind, c.matrix.Ind[begin:end]...)
+		data = append(data, c.matrix.Data[begin:end]...)
+		indptr = append(indptr, len(ind))
+	}
+
+	return NewCSR(r, c.matrix.J, indptr, ind, data)
+}
+
+// SelectCols returns a new CSR matrix containing only the columns of the receiver for
+// which the corresponding element of mask is true, in their original order, with the
+// remaining columns compacted together.  The returned matrix will not share underlying
+// storage with the receiver nor is the receiver modified by this call.  SelectCols will
+// panic if the length of mask does not equal the number of columns in the receiver.
+func (c *CSR) SelectCols(mask []bool) *CSR {
+	if len(mask) != c.matrix.J {
+		panic(mat.ErrShape)
+	}
+
+	// map each column to its new index or -1 if the column is not selected
+	newIdx := getInts(c.matrix.J, false)
+	defer putInts(newIdx)
+	cols := 0
+	for j, keep := range mask {
+		if keep {
+			newIdx[j] = cols
+			cols++
+		} else {
+			newIdx[j] = -1
+		}
+	}
+
+	indptr := make([]int, c.matrix.I+1)
+	ind := make([]int, 0, c.NNZ())
+	data := make([]float64, 0, c.NNZ())
+	for i := 0; i < c.matrix.I; i++ {
+		for k := c.matrix.Indptr[i]; k < c.matrix.Indptr[i+1]; k++ {
+			if j := newIdx[c.matrix.Ind[k]]; j >= 0 {
+				ind = append(ind, j)
+				data = append(data, c.matrix.Data[k])
+			}
+		}
+		indptr[i+1] = len(ind)
+	}
+
+	return NewCSR(c.matrix.I, cols, indptr, ind, data)
+}
+
+// DiagonalBlock returns the diagonal block of the square, block structured, receiver
+// with the specified index where each block is blockSize x blockSize i.e. the submatrix
+// spanning rows and columns blockIndex*blockSize to (blockIndex+1)*blockSize-1.  If the
+// dimensions of the receiver are not a multiple of blockSize, the final block is
+// truncated to the remaining rows and columns.  This is intended for the repeated
+// application of block (e.g. block Jacobi) preconditioners.
+//
+// Where all elements stored in the block rows lie within the block columns (as for a
+// block diagonal matrix), the rows of the block are contiguous in the receiver and the
+// returned matrix shares the value storage of the receiver so that changes to the
+// values of one will be reflected in the other (although inserting new elements into
+// the block will reallocate its storage rather than overwrite the receiver).  The column
+// indices are also shared for the first block but, as they must be offset to the block
+// columns, are copied for all other blocks.  Where the block rows contain elements
+// outside the block columns, the elements of the block are instead copied into new
+// storage.  DiagonalBlock will panic if the receiver is not square, blockSize is not
+// positive or blockIndex is out of range.
+func (c *CSR) DiagonalBlock(blockIndex, blockSize int) *CSR {
+	n := c.matrix.I
+	if n != c.matrix.J {
+		panic(mat.ErrShape)
+	}
+	if blockSize <= 0 || blockIndex < 0 || blockIndex*blockSize >= n {
+		panic(mat.ErrIndexOutOfRange)
+	}
+
+	start := blockIndex * blockSize
+	end := start + blockSize
+	if end > n {
+		end = n
+	}
+	size := end - start
+
+	begin, finish := c.matrix.Indptr[start], c.matrix.Indptr[end]
+	for k := begin; k < finish; k++ {
+		if j := c.matrix.Ind[k]; j < start || j >= end {
+			return c.slice(start, end, start, end)
+		}
+	}
+
+	indptr := make([]int, size+1)
+	for i := range indptr {
+		indptr[i] = c.matrix.Indptr[start+i] - begin
+	}
+	ind := c.matrix.Ind[begin:finish:finish]
+	if start > 0 {
+		ind = make([]int, finish-begin)
+		for k, j := range c.matrix.Ind[begin:finish] {
+			ind[k] = j - start
+		}
+	}
+	return NewCSR(size, size, indptr, ind, c.matrix.Data[begin:finish:finish])
+}
+
+// Slice returns a new CSR matrix containing the submatrix of the receiver spanning rows
+// [i, k) and columns [j, l), following the semantics of the Gonum mat.Slicer interface
+// although, unlike the Gonum dense types, the returned matrix does not share underlying
+// storage with the receiver.  The elements of each row in the range are copied from the
+// receiver keeping only those with column indices within [j, l) which are remapped by
+// subtracting j.  Slice will panic with mat.ErrRowAccess or mat.ErrColAccess if the
+// row or column bounds are out of range or k < i or l < j respectively and with
+// mat.ErrZeroLength if the resulting submatrix would have zero rows or columns.
+func (c *CSR) Slice(i, k, j, l int) mat.Matrix {
+	if i < 0 || k > c.matrix.I || k < i {
+		panic(mat.ErrRowAccess)
+	}
+	if j < 0 || l > c.matrix.J || l < j {
+		panic(mat.ErrColAccess)
+	}
+	if i == k || j == l {
+		panic(mat.ErrZeroLength)
+	}
+	return c.slice(i, k, j, l)
+}
+
+// slice returns a new CSR matrix containing a copy of the elements of the receiver in
+// rows [i, k) and columns [j, l) without checking the bounds.
+func (c *CSR) slice(i, k, j, l int) *CSR {
+	indptr := make([]int, k-i+1)
+	var ind []int
+	var data []float64
+	for r := i; r < k; r++ {
+		for p := c.matrix.Indptr[r]; p < c.matrix.Indptr[r+1]; p++ {
+			if col := c.matrix.Ind[p]; col >= j && col < l {
+				ind = append(ind, col-j)
+				data = append(data, c.matrix.Data[p])
+			}
+		}
+		indptr[r-i+1] = len(ind)
+	}
+	return NewCSR(k-i, l-j, indptr, ind, data)
+}
@@ -0,0 +1,266 @@
+package sparse
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCSRSelectRowsCols(t *testing.T) {
+	var tests = []struct {
+		r, c         int
+		data         []float64
+		rowMask      []bool
+		colMask      []bool
+		er, ec       int
+		expectedRows []float64
+		expectedCols []float64
+	}{
+		{
+			r: 4, c: 5,
+			data: []float64{
+				1, 0, 2, 0, 3,
+				0, 4, 0, 5, 0,
+				6, 0, 0, 0, 7,
+				0, 0, 8, 9, 0,
+			},
+			rowMask: []bool{true, false, true, false},
+			colMask: []bool{true, false, true, false, true},
+			er:      2, ec: 3,
+			expectedRows: []float64{
+				1, 0, 2, 0, 3,
+				6, 0, 0, 0, 7,
+			},
+			expectedCols: []float64{
+				1, 2, 3,
+				0, 0, 0,
+				6, 0, 7,
+				0, 8, 0,
+			},
+		},
+		{
+			r: 3, c: 4,
+			data: []float64{
+				1, 0, 2, 0,
+				0, 4, 0, 5,
+				6, 0, 0, 0,
+			},
+			rowMask: []bool{false, true, false},
+			colMask: []bool{false, true, false, true},
+			er:      1, ec: 2,
+			expectedRows: []float64{
+				0, 4, 0, 5,
+			},
+			expectedCols: []float64{
+				0, 0,
+				4, 5,
+				0, 0,
+			},
+		},
+		{
+			r: 2, c: 2,
+			data: []float64{
+				1, 2,
+				3, 4,
+			},
+			rowMask: []bool{false, false},
+			colMask: []bool{false, false},
+			er:      0, ec: 0,
+			expectedRows: nil,
+			expectedCols: nil,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		csr := CreateCSR(test.r, test.c, test.data).(*CSR)
+
+		rows := csr.SelectRows(test.rowMask)
+		if r, c := rows.Dims(); r != test.er || c != test.c {
+			t.Errorf("Expected SelectRows dimensions %dx%d but received %dx%d", test.er, test.c, r, c)
+		} else if test.er > 0 && !mat.Equal(mat.NewDense(test.er, test.c, test.expectedRows), rows) {
+			t.Logf("SelectRows: Expected:\n%v\n but received:\n%v\n", mat.Formatted(mat.NewDense(test.er, test.c, test.expectedRows)), mat.Formatted(rows))
+			t.Fail()
+		}
+
+		cols := csr.SelectCols(test.colMask)
+		if r, c := cols.Dims(); r != test.r || c != test.ec {
+			t.Errorf("Expected SelectCols dimensions %dx%d but received %dx%d", test.r, test.ec, r, c)
+		} else if test.ec > 0 && !mat.Equal(mat.NewDense(test.r, test.ec, test.expectedCols), cols) {
+			t.Logf("SelectCols: Expected:\n%v\n but received:\n%v\n", mat.Formatted(mat.NewDense(test.r, test.ec, test.expectedCols)), mat.Formatted(cols))
+			t.Fail()
+		}
+	}
+}
+
+func TestFailCSRSelectRowsCols(t *testing.T) {
+	csr := CreateCSR(2, 3, nil).(*CSR)
+
+	for ti, fn := range []func(){
+		func() { csr.SelectRows([]bool{true}) },
+		func() { csr.SelectCols([]bool{true, false}) },
+	} {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic for mask of incorrect length")
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+func TestCSRDiagonalBlock(t *testing.T) {
+	blockDiagonal := CreateCSR(5, 5, []float64{
+		1, 2, 0, 0, 0,
+		3, 4, 0, 0, 0,
+		0, 0, 5, 6, 0,
+		0, 0, 7, 8, 0,
+		0, 0, 0, 0, 9,
+	}).(*CSR)
+
+	coupled := CreateCSR(4, 4, []float64{
+		1, 2, 0, 5,
+		3, 4, 6, 0,
+		0, 7, 8, 9,
+		1, 0, 2, 3,
+	}).(*CSR)
+
+	var tests = []struct {
+		a          *CSR
+		blockIndex int
+		blockSize  int
+		expected   []float64
+		shared     bool
+	}{
+		{a: blockDiagonal, blockIndex: 0, blockSize: 2, expected: []float64{1, 2, 3, 4}, shared: true},
+		{a: blockDiagonal, blockIndex: 1, blockSize: 2, expected: []float64{5, 6, 7, 8}, shared: true},
+		{a: blockDiagonal, blockIndex: 2, blockSize: 2, expected: []float64{9}, shared: true},
+		{a: coupled, blockIndex: 0, blockSize: 2, expected: []float64{1, 2, 3, 4}},
+		{a: coupled, blockIndex: 1, blockSize: 2, expected: []float64{8, 9, 2, 3}},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		block := test.a.DiagonalBlock(test.blockIndex, test.blockSize)
+
+		size := int(math.Sqrt(float64(len(test.expected))))
+		expected := mat.NewDense(size, size, test.expected)
+		if !mat.Equal(expected, block) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(block))
+			t.Fail()
+		}
+
+		shared := block.NNZ() > 0 && &block.matrix.Data[0] == &test.a.matrix.Data[test.a.matrix.Indptr[test.blockIndex*test.blockSize]]
+		if shared != test.shared {
+			t.Errorf("Expected shared storage to be %v but was %v", test.shared, shared)
+		}
+	}
+}
+
+func TestFailCSRDiagonalBlock(t *testing.T) {
+	var tests = []struct {
+		a          *CSR
+		blockIndex int
+		blockSize  int
+	}{
+		{a: CreateCSR(4, 4, nil).(*CSR), blockIndex: 2, blockSize: 2},
+		{a: CreateCSR(4, 4, nil).(*CSR), blockIndex: -1, blockSize: 2},
+		{a: CreateCSR(4, 4, nil).(*CSR), blockIndex: 0, blockSize: 0},
+		{a: CreateCSR(4, 3, nil).(*CSR), blockIndex: 0, blockSize: 1},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("Expected panic but received none")
+				}
+			}()
+			test.a.DiagonalBlock(test.blockIndex, test.blockSize)
+		}()
+	}
+}
+
+func TestCSRSlice(t *testing.T) {
+	data := []float64{
+		1, 0, 2, 0, 3,
+		0, 4, 0, 0, 0,
+		5, 0, 6, 7, 0,
+		0, 0, 0, 8, 9,
+	}
+	dense := mat.NewDense(4, 5, data)
+
+	var tests = []struct {
+		i, k, j, l int
+	}{
+		{0, 4, 0, 5},
+		{1, 3, 1, 4},
+		{0, 1, 0, 5},
+		{2, 4, 3, 5},
+		{3, 4, 0, 1},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		csr := CreateCSR(4, 5, data).(*CSR)
+		expected := dense.Slice(test.i, test.k, test.j, test.l)
+		result := csr.Slice(test.i, test.k, test.j, test.l)
+
+		if r, c := result.Dims(); r != test.k-test.i || c != test.l-test.j {
+			t.Logf("Expected dimensions %dx%d but received %dx%d\n", test.k-test.i, test.l-test.j, r, c)
+			t.Fail()
+		}
+		if !mat.Equal(expected, result) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+			t.Fail()
+		}
+
+		// the slice must not share storage with the receiver
+		sliced := result.(*CSR)
+		if sliced.NNZ() > 0 {
+			sliced.matrix.Data[0] = -100
+			if !mat.Equal(dense, csr) {
+				t.Logf("Modifying slice modified the receiver\n")
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestFailCSRSlice(t *testing.T) {
+	var tests = []struct {
+		i, k, j, l int
+		err        error
+	}{
+		{-1, 2, 0, 2, mat.ErrRowAccess},
+		{0, 4, 0, 2, mat.ErrRowAccess},
+		{2, 1, 0, 2, mat.ErrRowAccess},
+		{0, 2, -1, 2, mat.ErrColAccess},
+		{0, 2, 0, 5, mat.ErrColAccess},
+		{0, 2, 3, 2, mat.ErrColAccess},
+		{1, 1, 0, 2, mat.ErrZeroLength},
+		{0, 2, 2, 2, mat.ErrZeroLength},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+		func() {
+			defer func() {
+				if r := recover(); r != test.err {
+					t.Errorf("Expected panic with %v for Slice(%d, %d, %d, %d) but received %v", test.err, test.i, test.k, test.j, test.l, r)
+				}
+			}()
+			CreateCSR(3, 4, nil).(*CSR).Slice(test.i, test.k, test.j, test.l)
+		}()
+	}
+}
@@ -0,0 +1,284 @@
+package sparse
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	inA = 1 << iota
+	inB
+)
+
+// PatternAnd returns a new CSR matrix representing the intersection of the sparsity
+// patterns of matrices a and b i.e. containing an element (with value 1) at every
+// coordinate where both a and b have a stored non-zero value.  Explicitly stored zero
+// values are not considered part of the sparsity pattern.  PatternAnd will panic if
+// a and b are not the same shape.
+func PatternAnd(a, b *CSR) *CSR {
+	return combinePatterns(a, b, func(flags int) bool { return flags == inA|inB })
+}
+
+// PatternOr returns a new CSR matrix representing the union of the sparsity patterns
+// of matrices a and b i.e. containing an element (with value 1) at every coordinate
+// where either a or b (or both) have a stored non-zero value.  Explicitly stored zero
+// values are not considered part of the sparsity pattern.  PatternOr will panic if
+// a and b are not the same shape.
+func PatternOr(a, b *CSR) *CSR {
+	return combinePatterns(a, b, func(flags int) bool { return flags != 0 })
+}
+
+// PatternXor returns a new CSR matrix representing the symmetric difference of the
+// sparsity patterns of matrices a and b i.e. containing an element (with value 1) at
+// every coordinate where exactly one of a and b has a stored non-zero value.  This is
+// useful for comparing how the structures of two matrices differ.  Explicitly stored
+// zero values are not considered part of the sparsity pattern.  PatternXor will panic
+// if a and b are not the same shape.
+func PatternXor(a, b *CSR) *CSR {
+	return combinePatterns(a, b, func(flags int) bool { return flags == inA || flags == inB })
+}
+
+// combinePatterns returns a new CSR matrix with an element of value 1 at each coordinate
+// for which keep returns true when passed flags indicating whether a (inA) and/or b
+// (inB) contain a stored non-zero value at that coordinate.  The column indices of each
+// row of the returned matrix are sorted.
+func combinePatterns(a, b *CSR, keep func(flags int) bool) *CSR {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(mat.ErrShape)
+	}
+
+	flags := getInts(ac, true)
+	defer putInts(flags)
+	var cols []int
+
+	indptr := make([]int, ar+1)
+	var ind []int
+
+	for i := 0; i < ar; i++ {
+		cols = cols[:0]
+		for k := a.matrix.Indptr[i]; k < a.matrix.Indptr[i+1]; k++ {
+			if j := a.matrix.Ind[k]; a.matrix.Data[k] != 0 {
+				if flags[j] == 0 {
+					cols = append(cols, j)
+				}
+				flags[j] |= inA
+			}
+		}
+		for k := b.matrix.Indptr[i]; k < b.matrix.Indptr[i+1]; k++ {
+			if j := b.matrix.Ind[k]; b.matrix.Data[k] != 0 {
+				if flags[j] == 0 {
+					cols = append(cols, j)
+				}
+				flags[j] |= inB
+			}
+		}
+		sort.Ints(cols)
+		for _, j := range cols {
+			if keep(flags[j]) {
+				ind = append(ind, j)
+			}
+			flags[j] = 0
+		}
+		indptr[i+1] = len(ind)
+	}
+
+	data := make([]float64, len(ind))
+	for k := range data {
+		data[k] = 1
+	}
+
+	return NewCSR(ar, ac, indptr, ind, data)
+}
+
+// StructuralMul returns a new CSR matrix representing the sparsity pattern of the matrix
+// product a * b i.e. containing an element (with value 1) at every coordinate (i, k) for
+// which there is some j where both a(i, j) and b(j, k) are stored non-zero values.  This
+// is symbolic multiplication: no floating point values are accumulated and so the result
+// is the structure of the numeric product ignoring any cancellation, where products sum
+// to exactly zero, that would otherwise lead to elements being zero.  For an adjacency
+// matrix a, StructuralMul(a, a) records which nodes are reachable by walks of length 2
+// and so may be used for transitive closure style computations.  Explicitly stored zero
+// values are not considered part of the sparsity pattern of a or b.  The column indices
+// of each row of the result are sorted.  StructuralMul will panic if the number of
+// columns of a does not equal the number of rows of b.
+func StructuralMul(a, b *CSR) *CSR {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(mat.ErrShape)
+	}
+
+	seen := getInts(bc, true)
+	defer putInts(seen)
+
+	indptr := make([]int, ar+1)
+	var ind []int
+
+	for i := 0; i < ar; i++ {
+		begin := len(ind)
+		for k := a.matrix.Indptr[i]; k < a.matrix.Indptr[i+1]; k++ {
+			if a.matrix.Data[k] == 0 {
+				continue
+			}
+			j := a.matrix.Ind[k]
+			for p := b.matrix.Indptr[j]; p < b.matrix.Indptr[j+1]; p++ {
+				if col := b.matrix.Ind[p]; b.matrix.Data[p] != 0 && seen[col] == 0 {
+					seen[col] = 1
+					ind = append(ind, col)
+				}
+			}
+		}
+		sort.Ints(ind[begin:])
+		for _, col := range ind[begin:] {
+			seen[col] = 0
+		}
+		indptr[i+1] = len(ind)
+	}
+
+	data := make([]float64, len(ind))
+	for k := range data {
+		data[k] = 1
+	}
+
+	return NewCSR(ar, bc, indptr, ind, data)
+}
+
+// EqualStructural returns true if the receiver and matrix b are the same size and
+// contain the same non-zero values at the same coordinates.  Explicitly stored zero
+// values (for example left behind by arithmetic operations where values cancel) are
+// treated as absent, so a matrix with a stored zero at (i, j) is considered equal to
+// one without an element stored at (i, j).  Values are compared exactly.  If b is not
+// a CSR matrix, it is first converted to CSR format.
+func (c *CSR) EqualStructural(b mat.Matrix) bool {
+	br, bc := b.Dims()
+	if br != c.matrix.I || bc != c.matrix.J {
+		return false
+	}
+	other := csrOf(b)
+
+	row := getFloats(c.matrix.J, true)
+	defer putFloats(row)
+
+	for i := 0; i < c.matrix.I; i++ {
+		// scatter the non-zero values of row i of b then check each non-zero value of
+		// the receiver matches, clearing the matched values as we go
+		for k := other.matrix.Indptr[i]; k < other.matrix.Indptr[i+1]; k++ {
+			row[other.matrix.Ind[k]] += other.matrix.Data[k]
+		}
+		equal := true
+		for k := c.matrix.Indptr[i]; k < c.matrix.Indptr[i+1]; k++ {
+			j := c.matrix.Ind[k]
+			if v := c.matrix.Data[k]; v != 0 {
+				if row[j] != v {
+					equal = false
+				}
+				row[j] = 0
+			}
+		}
+		// any remaining non-zero values in b are absent from the receiver
+		for k := other.matrix.Indptr[i]; k < other.matrix.Indptr[i+1]; k++ {
+			j := other.matrix.Ind[k]
+			if row[j] != 0 {
+				equal = false
+			}
+			row[j] = 0
+		}
+		if !equal {
+			return false
+		}
+	}
+	return true
+}
+
+// SameStructure returns true if matrices a and b have exactly the same sparsity structure
+// i.e. the same dimensions and identical row index pointers and column indices, ignoring
+// the stored values.  Explicitly stored zero values are considered part of the structure.
+// When SameStructure returns true, element-wise operations on a and b reduce to
+// operations on their Data slices, element by element (see AddSameStructure).  As the
+// same elements may be stored in a different order, the column indices of a and b must be
+// sorted (see SortIndices) for the comparison to be meaningful and SameStructure will
+// panic with ErrUnsortedIndices if they are not.
+func SameStructure(a, b *CSR) bool {
+	if !a.HasSortedIndices() || !b.HasSortedIndices() {
+		panic(ErrUnsortedIndices)
+	}
+	if a.matrix.I != b.matrix.I || a.matrix.J != b.matrix.J {
+		return false
+	}
+	if a.matrix.I == 0 {
+		return true
+	}
+	return equalInts(a.matrix.Indptr, b.matrix.Indptr) && equalInts(a.matrix.Ind, b.matrix.Ind)
+}
+
+// equalInts returns true if x and y are the same length and contain the same values.
+func equalInts(x, y []int) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	if len(x) == 0 || &x[0] == &y[0] {
+		return true
+	}
+	for i, v := range x {
+		if v != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// DetectToeplitz returns true if the matrix a is a (possibly banded) Toeplitz matrix i.e.
+// every diagonal of the matrix is constant, along with the value of each diagonal.  The
+// values are returned in a slice of length r+c-1 (where a is r x c) indexed by the offset
+// of the diagonal from the main diagonal plus r-1 so that element r-1 holds the value
+// of the main diagonal, elements above r-1 the super diagonals and below r-1 the sub
+// diagonals.  Diagonals containing no non-zero elements have a value of 0 so, for a
+// banded Toeplitz matrix, only the values within the band will be non-zero.  Such
+// matrices arise frequently from the discretisation of constant coefficient operators
+// and may be represented compactly, and multiplied quickly, using just the diagonal
+// values.  Checking stops as soon as a diagonal is found not to be constant in which
+// case false and nil are returned.  Empty matrices are trivially Toeplitz and, having no
+// diagonals, true and nil are returned.
+func DetectToeplitz(a *CSR) (bool, []float64) {
+	r, c := a.Dims()
+	if r == 0 || c == 0 {
+		return true, nil
+	}
+	values := make([]float64, r+c-1)
+	counts := getInts(r+c-1, true)
+	defer putInts(counts)
+
+	for i := 0; i < r; i++ {
+		for k := a.matrix.Indptr[i]; k < a.matrix.Indptr[i+1]; k++ {
+			d := a.matrix.Ind[k] - i + r - 1
+			v := a.matrix.Data[k]
+			if counts[d] > 0 && v != values[d] {
+				return false, nil
+			}
+			values[d] = v
+			counts[d]++
+		}
+	}
+
+	// every element of diagonals with non-zero values must be stored
+	for d, v := range values {
+		if v == 0 {
+			continue
+		}
+		offset := d - (r - 1)
+		var length int
+		if offset >= 0 {
+			length = min(r, c-offset)
+		} else {
+			length = min(r+offset, c)
+		}
+		if counts[d] != length {
+			return false, nil
+		}
+	}
+
+	return true, values
+}
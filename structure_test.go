@@ -0,0 +1,462 @@
+package sparse
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestPatternSetOperations(t *testing.T) {
+	var tests = []struct {
+		r, c    int
+		a, b    []float64
+		zeroInA int
+		and     []float64
+		or      []float64
+		xor     []float64
+	}{
+		{
+			r: 3, c: 4,
+			a: []float64{
+				1, 0, 2, 0,
+				0, 0, 3, 0,
+				4, 5, 0, 0,
+			},
+			b: []float64{
+				7, 0, 0, 8,
+				0, 0, 0, 0,
+				0, 9, 0, -1,
+			},
+			and: []float64{
+				1, 0, 0, 0,
+				0, 0, 0, 0,
+				0, 1, 0, 0,
+			},
+			or: []float64{
+				1, 0, 1, 1,
+				0, 0, 1, 0,
+				1, 1, 0, 1,
+			},
+			xor: []float64{
+				0, 0, 1, 1,
+				0, 0, 1, 0,
+				1, 0, 0, 1,
+			},
+		},
+		{
+			r: 2, c: 3,
+			a: []float64{
+				1, 2, 3,
+				0, 0, 0,
+			},
+			b: []float64{
+				0, 2, 0,
+				0, 0, 0,
+			},
+			zeroInA: 1,
+			and: []float64{
+				0, 1, 0,
+				0, 0, 0,
+			},
+			or: []float64{
+				1, 1, 1,
+				0, 0, 0,
+			},
+			xor: []float64{
+				1, 0, 1,
+				0, 0, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := CreateCSR(test.r, test.c, test.a).(*CSR)
+		b := CreateCSR(test.r, test.c, test.b).(*CSR)
+		if test.zeroInA > 0 {
+			// explicitly stored zeros (in column zeroInA of the last row) should
+			// not form part of the pattern
+			a.matrix.Indptr[test.r]++
+			a.matrix.Ind = append(a.matrix.Ind, test.zeroInA)
+			a.matrix.Data = append(a.matrix.Data, 0)
+		}
+
+		results := []struct {
+			name     string
+			result   *CSR
+			expected []float64
+		}{
+			{"And", PatternAnd(a, b), test.and},
+			{"Or", PatternOr(a, b), test.or},
+			{"Xor", PatternXor(a, b), test.xor},
+		}
+
+		for _, res := range results {
+			expected := mat.NewDense(test.r, test.c, res.expected)
+			if !mat.Equal(expected, res.result) {
+				t.Logf("%s: Expected:\n%v\n but received:\n%v\n", res.name, mat.Formatted(expected), mat.Formatted(res.result))
+				t.Fail()
+			}
+			nnz := 0
+			for _, v := range res.expected {
+				if v != 0 {
+					nnz++
+				}
+			}
+			if res.result.NNZ() != nnz {
+				t.Logf("%s: Expected NNZ of %d but received %d", res.name, nnz, res.result.NNZ())
+				t.Fail()
+			}
+			for i := 0; i < test.r; i++ {
+				raw := res.result.RawMatrix()
+				if !sort.IntsAreSorted(raw.Ind[raw.Indptr[i]:raw.Indptr[i+1]]) {
+					t.Logf("%s: Expected sorted column indices for row %d", res.name, i)
+					t.Fail()
+				}
+			}
+		}
+	}
+}
+
+func TestStructuralMul(t *testing.T) {
+	var tests = []struct {
+		ar, ac, bc int
+		a, b       []float64
+		expected   []float64
+	}{
+		{
+			ar: 3, ac: 3, bc: 4,
+			a: []float64{
+				1, 0, 2,
+				0, 0, 0,
+				0, 3, 0,
+			},
+			b: []float64{
+				1, 0, 0, 1,
+				0, 0, 5, 0,
+				0, 2, 0, 0,
+			},
+			expected: []float64{
+				1, 1, 0, 1,
+				0, 0, 0, 0,
+				0, 0, 1, 0,
+			},
+		},
+		{
+			// numeric product cancels to zero at (0, 0)
+			ar: 1, ac: 2, bc: 1,
+			a: []float64{
+				1, 1,
+			},
+			b: []float64{
+				1,
+				-1,
+			},
+			expected: []float64{
+				1,
+			},
+		},
+		{
+			// reachability in a cycle 0 -> 1 -> 2 -> 0
+			ar: 3, ac: 3, bc: 3,
+			a: []float64{
+				0, 2, 0,
+				0, 0, 3,
+				4, 0, 0,
+			},
+			b: []float64{
+				0, 2, 0,
+				0, 0, 3,
+				4, 0, 0,
+			},
+			expected: []float64{
+				0, 0, 1,
+				1, 0, 0,
+				0, 1, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := CreateCSR(test.ar, test.ac, test.a).(*CSR)
+		b := CreateCSR(test.ac, test.bc, test.b).(*CSR)
+
+		result := StructuralMul(a, b)
+
+		expected := mat.NewDense(test.ar, test.bc, test.expected)
+		if !mat.Equal(expected, result) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+			t.Fail()
+		}
+		raw := result.RawMatrix()
+		for i := 0; i < test.ar; i++ {
+			if !sort.IntsAreSorted(raw.Ind[raw.Indptr[i]:raw.Indptr[i+1]]) {
+				t.Logf("Expected sorted column indices for row %d", i)
+				t.Fail()
+			}
+		}
+	}
+
+	// explicitly stored zeros are not part of the pattern
+	a := NewCSR(1, 2, []int{0, 2}, []int{0, 1}, []float64{0, 1})
+	b := NewCSR(2, 2, []int{0, 1, 2}, []int{0, 1}, []float64{1, 1})
+	if result := StructuralMul(a, b); result.NNZ() != 1 || result.At(0, 1) != 1 {
+		t.Errorf("Expected single element at (0, 1) but received:\n%v", mat.Formatted(result))
+	}
+
+	defer func() {
+		if r := recover(); r != mat.ErrShape {
+			t.Errorf("Expected panic with mat.ErrShape but received %v", r)
+		}
+	}()
+	StructuralMul(CreateCSR(2, 3, nil).(*CSR), CreateCSR(2, 3, nil).(*CSR))
+}
+
+func TestCSREqualStructural(t *testing.T) {
+	compact := CreateCSR(3, 4, []float64{
+		1, 0, 0, 7,
+		0, 0, 0, 0,
+		3, 0, 3, 6,
+	}).(*CSR)
+
+	var tests = []struct {
+		desc     string
+		b        mat.Matrix
+		expected bool
+	}{
+		{
+			desc:     "identical",
+			b:        compact,
+			expected: true,
+		},
+		{
+			desc: "explicit zeros",
+			b: NewCSR(3, 4,
+				[]int{0, 3, 5, 8},
+				[]int{0, 2, 3, 1, 3, 0, 2, 3},
+				[]float64{1, 0, 7, 0, 0, 3, 3, 6}),
+			expected: true,
+		},
+		{
+			desc: "dense",
+			b: mat.NewDense(3, 4, []float64{
+				1, 0, 0, 7,
+				0, 0, 0, 0,
+				3, 0, 3, 6,
+			}),
+			expected: true,
+		},
+		{
+			desc: "different value",
+			b: NewCSR(3, 4,
+				[]int{0, 3, 3, 6},
+				[]int{0, 2, 3, 0, 2, 3},
+				[]float64{1, 0, 7, 3, 3, 5}),
+			expected: false,
+		},
+		{
+			desc: "missing element",
+			b: NewCSR(3, 4,
+				[]int{0, 2, 2, 4},
+				[]int{0, 3, 0, 2},
+				[]float64{1, 7, 3, 3}),
+			expected: false,
+		},
+		{
+			desc: "additional element",
+			b: NewCSR(3, 4,
+				[]int{0, 2, 3, 6},
+				[]int{0, 3, 1, 0, 2, 3},
+				[]float64{1, 7, 2, 3, 3, 6}),
+			expected: false,
+		},
+		{
+			desc:     "different shape",
+			b:        CreateCSR(4, 3, nil),
+			expected: false,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		if result := compact.EqualStructural(test.b); result != test.expected {
+			t.Errorf("Expected %v but received %v", test.expected, result)
+		}
+		if b, ok := test.b.(*CSR); ok {
+			if result := b.EqualStructural(compact); result != test.expected {
+				t.Errorf("Expected %v comparing in reverse but received %v", test.expected, result)
+			}
+		}
+	}
+}
+
+func TestDetectToeplitz(t *testing.T) {
+	var tests = []struct {
+		desc     string
+		a        *CSR
+		toeplitz bool
+		values   []float64
+	}{
+		{
+			desc:     "tridiagonal",
+			a:        laplacian1D(4),
+			toeplitz: true,
+			values:   []float64{0, 0, -1, 2, -1, 0, 0},
+		},
+		{
+			desc: "rectangular",
+			a: CreateCSR(2, 4, []float64{
+				1, 2, 3, 0,
+				5, 1, 2, 3,
+			}).(*CSR),
+			toeplitz: true,
+			values:   []float64{5, 1, 2, 3, 0},
+		},
+		{
+			desc: "non-constant diagonal",
+			a: CreateCSR(3, 3, []float64{
+				2, -1, 0,
+				-1, 3, -1,
+				0, -1, 2,
+			}).(*CSR),
+			toeplitz: false,
+		},
+		{
+			desc: "incomplete diagonal",
+			a: CreateCSR(3, 3, []float64{
+				2, -1, 0,
+				0, 2, -1,
+				0, 0, 2,
+			}).(*CSR),
+			toeplitz: true,
+			values:   []float64{0, 0, 2, -1, 0},
+		},
+		{
+			desc: "missing diagonal element",
+			a: CreateCSR(3, 3, []float64{
+				2, 0, 0,
+				0, 2, 0,
+				0, 0, 0,
+			}).(*CSR),
+			toeplitz: false,
+		},
+		{
+			desc:     "empty",
+			a:        &CSR{},
+			toeplitz: true,
+		},
+		{
+			desc:     "no rows",
+			a:        NewCSR(0, 3, []int{0}, nil, nil),
+			toeplitz: true,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		toeplitz, values := DetectToeplitz(test.a)
+		if toeplitz != test.toeplitz {
+			t.Errorf("Expected %v but received %v", test.toeplitz, toeplitz)
+			continue
+		}
+		if !reflect.DeepEqual(test.values, values) {
+			t.Errorf("Expected diagonal values %v but received %v", test.values, values)
+		}
+	}
+}
+
+func TestSameStructure(t *testing.T) {
+	a := CreateCSR(3, 4, []float64{
+		1, 0, 0, 7,
+		0, 0, 0, 0,
+		3, 0, 3, 6,
+	}).(*CSR)
+	a.SortIndices()
+
+	var tests = []struct {
+		desc     string
+		b        *CSR
+		expected bool
+	}{
+		{
+			desc:     "identical",
+			b:        a,
+			expected: true,
+		},
+		{
+			desc: "different values",
+			b: NewCSR(3, 4,
+				[]int{0, 2, 2, 5},
+				[]int{0, 3, 0, 2, 3},
+				[]float64{-1, 2, 0, 4, 5}),
+			expected: true,
+		},
+		{
+			desc: "element in different row",
+			b: NewCSR(3, 4,
+				[]int{0, 2, 3, 5},
+				[]int{0, 3, 0, 2, 3},
+				[]float64{1, 7, 3, 3, 6}),
+			expected: false,
+		},
+		{
+			desc: "element in different column",
+			b: NewCSR(3, 4,
+				[]int{0, 2, 2, 5},
+				[]int{0, 3, 0, 1, 3},
+				[]float64{1, 7, 3, 3, 6}),
+			expected: false,
+		},
+		{
+			desc: "missing element",
+			b: NewCSR(3, 4,
+				[]int{0, 2, 2, 4},
+				[]int{0, 3, 0, 2},
+				[]float64{1, 7, 3, 3}),
+			expected: false,
+		},
+		{
+			desc: "different shape",
+			b: NewCSR(3, 5,
+				[]int{0, 2, 2, 5},
+				[]int{0, 3, 0, 2, 3},
+				[]float64{1, 7, 3, 3, 6}),
+			expected: false,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		if result := SameStructure(a, test.b); result != test.expected {
+			t.Errorf("Expected %v but received %v", test.expected, result)
+		}
+		if result := SameStructure(test.b, a); result != test.expected {
+			t.Errorf("Expected %v comparing in reverse but received %v", test.expected, result)
+		}
+	}
+
+	unsorted := NewCSR(3, 4,
+		[]int{0, 2, 2, 5},
+		[]int{3, 0, 0, 2, 3},
+		[]float64{7, 1, 3, 3, 6})
+	func() {
+		defer func() {
+			if r := recover(); r != ErrUnsortedIndices {
+				t.Errorf("Expected panic with %v but received %v", ErrUnsortedIndices, r)
+			}
+		}()
+		SameStructure(a, unsorted)
+	}()
+	unsorted.SortIndices()
+	if !SameStructure(a, unsorted) {
+		t.Errorf("Expected matrices to have the same structure once sorted")
+	}
+}
@@ -0,0 +1,202 @@
+package sparse
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Sparser       = (*SymmetricCSR)(nil)
+	_ mat.Symmetric = (*SymmetricCSR)(nil)
+)
+
+// IsSymmetric returns true if the receiver is square and both structurally and
+// numerically symmetric i.e. A[i, j] == A[j, i] for all i and j.  Values are compared
+// exactly and explicitly stored zero values are treated as zero so a stored zero at
+// A[i, j] matches an absent element at A[j, i].  Non-square matrices are never
+// symmetric.
+func (c *CSR) IsSymmetric() bool {
+	r, cols := c.Dims()
+	if r != cols {
+		return false
+	}
+
+	t := c.ToCSC()
+	work := getFloats(cols, true)
+	defer putFloats(work)
+
+	for i := 0; i < r; i++ {
+		begin, end := c.matrix.Indptr[i], c.matrix.Indptr[i+1]
+		tbegin, tend := t.matrix.Indptr[i], t.matrix.Indptr[i+1]
+		for k := begin; k < end; k++ {
+			work[c.matrix.Ind[k]] += c.matrix.Data[k]
+		}
+		for k := tbegin; k < tend; k++ {
+			work[t.matrix.Ind[k]] -= t.matrix.Data[k]
+		}
+		symmetric := true
+		for k := begin; k < end; k++ {
+			j := c.matrix.Ind[k]
+			if work[j] != 0 {
+				symmetric = false
+			}
+			work[j] = 0
+		}
+		for k := tbegin; k < tend; k++ {
+			j := t.matrix.Ind[k]
+			if work[j] != 0 {
+				symmetric = false
+			}
+			work[j] = 0
+		}
+		if !symmetric {
+			return false
+		}
+	}
+	return true
+}
+
+// SymmetricCSR is a symmetric sparse matrix implementing the Gonum mat.Symmetric
+// interface so that it may be passed to Gonum routines expecting a symmetric matrix.
+// Only the lower triangle of the matrix (including the main diagonal) is stored, as a
+// CSR matrix, with elements above the diagonal implied by symmetry i.e. At(i, j) for
+// i < j returns the stored value for element j, i.  This halves the storage required
+// for the off-diagonal elements compared to storing the full matrix.  The stored lower
+// triangle is in the form expected by SymSpMV.
+type SymmetricCSR struct {
+	lower *CSR
+}
+
+// NewSymmetricCSR creates a new SymmetricCSR matrix from the lower triangle (including
+// the main diagonal) of the square matrix a.  Elements of a above the main diagonal are
+// ignored, so a need not itself be symmetric, in which case the returned matrix
+// reflects the lower triangle of a.  The returned matrix does not share storage with a.
+// NewSymmetricCSR will panic if a is not square.
+func NewSymmetricCSR(a *CSR) *SymmetricCSR {
+	r, c := a.Dims()
+	if r != c {
+		panic(mat.ErrSquare)
+	}
+
+	indptr := make([]int, r+1)
+	var ind []int
+	var data []float64
+	for i := 0; i < r; i++ {
+		for k := a.matrix.Indptr[i]; k < a.matrix.Indptr[i+1]; k++ {
+			if j := a.matrix.Ind[k]; j <= i {
+				ind = append(ind, j)
+				data = append(data, a.matrix.Data[k])
+			}
+		}
+		indptr[i+1] = len(ind)
+	}
+
+	return &SymmetricCSR{lower: NewCSR(r, c, indptr, ind, data)}
+}
+
+// Dims returns the size of the matrix as the number of rows and columns
+func (s *SymmetricCSR) Dims() (int, int) {
+	return s.lower.Dims()
+}
+
+// Symmetric returns the number of rows/columns in the matrix.
+func (s *SymmetricCSR) Symmetric() int {
+	r, _ := s.lower.Dims()
+	return r
+}
+
+// At returns the element of the matrix located at row i and column j.  At will panic
+// if specified values for i or j fall outside the dimensions of the matrix.
+func (s *SymmetricCSR) At(i, j int) float64 {
+	if i < j {
+		i, j = j, i
+	}
+	return s.lower.At(i, j)
+}
+
+// T returns the receiver as a symmetric matrix is its own transpose.
+func (s *SymmetricCSR) T() mat.Matrix {
+	return s
+}
+
+// NNZ returns the Number of Non Zero elements in the full matrix i.e. counting both
+// off-diagonal elements of each symmetric pair.  This is not the number of elements
+// stored in the lower triangle.
+func (s *SymmetricCSR) NNZ() int {
+	var nnz int
+	s.lower.DoNonZero(func(i, j int, v float64) {
+		if i == j {
+			nnz++
+		} else {
+			nnz += 2
+		}
+	})
+	return nnz
+}
+
+// DoNonZero calls the function fn for each of the stored non-zero elements of the full
+// matrix, calling fn twice (once for each triangle) for each stored off-diagonal
+// element.  The order of visiting is undefined.
+func (s *SymmetricCSR) DoNonZero(fn func(i, j int, v float64)) {
+	s.lower.DoNonZero(func(i, j int, v float64) {
+		fn(i, j, v)
+		if i != j {
+			fn(j, i, v)
+		}
+	})
+}
+
+// Lower returns the lower triangle (including the main diagonal) of the matrix as
+// stored by the receiver and may be passed directly to SymSpMV.  The returned CSR
+// matrix shares storage with the receiver.
+func (s *SymmetricCSR) Lower() *CSR {
+	return s.lower
+}
+
+// ToCSR returns a CSR sparse format version of the full matrix i.e. with both the upper
+// and lower triangles explicitly stored.  The returned matrix does not share storage
+// with the receiver.
+func (s *SymmetricCSR) ToCSR() *CSR {
+	r, c := s.Dims()
+	coo := NewCOO(r, c, nil, nil, nil)
+	s.DoNonZero(func(i, j int, v float64) {
+		coo.Set(i, j, v)
+	})
+	return coo.ToCSR()
+}
+
+// SymSpMV returns the product A * x of the symmetric matrix A and the vector x, where A is
+// represented by its lower triangle (including the main diagonal) stored in the CSR matrix
+// lower.  The elements of the upper triangle of A are implied by symmetry and so each stored
+// off-diagonal element lower(i, j) contributes to both row i (as A(i, j)) and row j (as
+// A(j, i)) of the product whereas each diagonal element contributes only once.  Storing
+// only one triangle roughly halves the storage required for symmetric matrices and the
+// product is still computed in a single O(NNZ) pass over the stored elements.  Only the
+// lower triangle of lower is referenced - any elements stored above the main diagonal are
+// ignored.  SymSpMV will panic with mat.ErrSquare if lower is not square or with
+// mat.ErrShape if len(x) does not equal the dimension of lower.
+func SymSpMV(lower *CSR, x []float64) []float64 {
+	n, c := lower.Dims()
+	if n != c {
+		panic(mat.ErrSquare)
+	}
+	if len(x) != n {
+		panic(mat.ErrShape)
+	}
+
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		var sum float64
+		for k := lower.matrix.Indptr[i]; k < lower.matrix.Indptr[i+1]; k++ {
+			j, v := lower.matrix.Ind[k], lower.matrix.Data[k]
+			switch {
+			case j < i:
+				sum += v * x[j]
+				y[j] += v * x[i]
+			case j == i:
+				sum += v * x[i]
+			}
+		}
+		y[i] += sum
+	}
+	return y
+}
@@ -0,0 +1,223 @@
+package sparse
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCSRIsSymmetric(t *testing.T) {
+	var tests = []struct {
+		m        *CSR
+		expected bool
+	}{
+		{
+			m: CreateCSR(3, 3, []float64{
+				1, 2, 0,
+				2, 0, 3,
+				0, 3, 4,
+			}).(*CSR),
+			expected: true,
+		},
+		{
+			m: CreateCSR(3, 3, []float64{
+				1, 2, 0,
+				2, 0, 3,
+				0, 5, 4,
+			}).(*CSR),
+			expected: false,
+		},
+		{
+			m: CreateCSR(3, 3, []float64{
+				1, 2, 0,
+				0, 0, 3,
+				0, 3, 4,
+			}).(*CSR),
+			expected: false,
+		},
+		{
+			m: CreateCSR(2, 3, []float64{
+				1, 0, 0,
+				0, 1, 0,
+			}).(*CSR),
+			expected: false,
+		},
+		{
+			// explicit zero at (0, 1) with no stored element at (1, 0)
+			m:        NewCSR(2, 2, []int{0, 2, 3}, []int{1, 0, 1}, []float64{0, 1, 1}),
+			expected: true,
+		},
+		{
+			// duplicate entries at (0, 1) summing to the value at (1, 0)
+			m:        NewCSR(2, 2, []int{0, 2, 3}, []int{1, 1, 0}, []float64{1, 2, 3}),
+			expected: true,
+		},
+		{
+			m:        NewCSR(2, 2, []int{0, 1, 2}, []int{1, 0}, []float64{math.NaN(), math.NaN()}),
+			expected: false,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		if sym := test.m.IsSymmetric(); sym != test.expected {
+			t.Logf("Expected IsSymmetric() %t but received %t for:\n%v\n", test.expected, sym, mat.Formatted(test.m))
+			t.Fail()
+		}
+	}
+}
+
+func TestSymmetricCSR(t *testing.T) {
+	full := []float64{
+		4, 1, 0, 2,
+		1, 3, 0, 0,
+		0, 0, 5, 1,
+		2, 0, 1, 6,
+	}
+	expected := mat.NewSymDense(4, full)
+
+	// upper triangle values should be ignored
+	a := CreateCSR(4, 4, []float64{
+		4, 9, 0, 9,
+		1, 3, 9, 0,
+		0, 0, 5, 0,
+		2, 0, 1, 6,
+	}).(*CSR)
+	sym := NewSymmetricCSR(a)
+
+	if n := sym.Symmetric(); n != 4 {
+		t.Errorf("Expected Symmetric() 4 but received %d", n)
+	}
+	if !mat.Equal(expected, sym) {
+		t.Errorf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(sym))
+	}
+	if !mat.Equal(expected, sym.T()) {
+		t.Errorf("Expected transpose:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(sym.T()))
+	}
+	if nnz := sym.NNZ(); nnz != 10 {
+		t.Errorf("Expected NNZ() 10 but received %d", nnz)
+	}
+	if nnz := sym.Lower().NNZ(); nnz != 7 {
+		t.Errorf("Expected 7 stored elements but received %d", nnz)
+	}
+
+	x := []float64{1, 2, 3, 4}
+	y := SymSpMV(sym.Lower(), x)
+	var yExpected mat.VecDense
+	yExpected.MulVec(expected, mat.NewVecDense(4, x))
+	if !mat.Equal(&yExpected, mat.NewVecDense(4, y)) {
+		t.Errorf("Expected SymSpMV of stored triangle %v but received %v", yExpected.RawVector().Data, y)
+	}
+
+	csr := sym.ToCSR()
+	if !mat.Equal(expected, csr) || !csr.IsSymmetric() {
+		t.Errorf("Expected full matrix:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(csr))
+	}
+
+	dense := mat.NewDense(4, 4, nil)
+	sym.DoNonZero(func(i, j int, v float64) {
+		dense.Set(i, j, dense.At(i, j)+v)
+	})
+	if !mat.Equal(expected, dense) {
+		t.Errorf("Expected DoNonZero to visit:\n%v\n but visited:\n%v\n", mat.Formatted(expected), mat.Formatted(dense))
+	}
+
+	// use with Gonum routines accepting mat.Symmetric
+	s := mat.NewSymDense(4, nil)
+	s.CopySym(sym)
+	if !mat.Equal(expected, s) {
+		t.Errorf("Expected CopySym:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(s))
+	}
+
+	var eig, eigExpected mat.EigenSym
+	if !eig.Factorize(sym, false) || !eigExpected.Factorize(expected, false) {
+		t.Fatalf("Failed to factorize symmetric matrix")
+	}
+	values, expectedValues := eig.Values(nil), eigExpected.Values(nil)
+	for i := range values {
+		if math.Abs(values[i]-expectedValues[i]) > 1e-12 {
+			t.Errorf("Expected eigenvalues %v but received %v", expectedValues, values)
+			break
+		}
+	}
+}
+
+func TestNewSymmetricCSRNonSquare(t *testing.T) {
+	defer func() {
+		if r := recover(); r != mat.ErrSquare {
+			t.Errorf("Expected panic %v but received %v", mat.ErrSquare, r)
+		}
+	}()
+	NewSymmetricCSR(CreateCSR(2, 3, nil).(*CSR))
+}
+
+func TestSymSpMV(t *testing.T) {
+	var tests = []struct {
+		n     int
+		lower []float64
+	}{
+		{
+			n: 4,
+			lower: []float64{
+				4, 0, 0, 0,
+				1, 5, 0, 0,
+				0, 3, 6, 0,
+				2, 0, -1, 7,
+			},
+		},
+		{
+			// diagonal only
+			n: 3,
+			lower: []float64{
+				1, 0, 0,
+				0, 2, 0,
+				0, 0, 3,
+			},
+		},
+		{
+			// elements above the diagonal are ignored
+			n: 3,
+			lower: []float64{
+				1, 9, 9,
+				2, 3, 9,
+				4, 5, 6,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		full := mat.NewSymDense(test.n, nil)
+		for i := 0; i < test.n; i++ {
+			for j := 0; j <= i; j++ {
+				full.SetSym(i, j, test.lower[i*test.n+j])
+			}
+		}
+		x := make([]float64, test.n)
+		for i := range x {
+			x[i] = float64(i+1) - 0.5
+		}
+		var expected mat.VecDense
+		expected.MulVec(full, mat.NewVecDense(test.n, x))
+
+		result := SymSpMV(CreateCSR(test.n, test.n, test.lower).(*CSR), x)
+
+		for i, v := range result {
+			if math.Abs(v-expected.AtVec(i)) > 1e-12 {
+				t.Logf("Expected %v but received %v\n", expected.RawVector().Data, result)
+				t.Fail()
+				break
+			}
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != mat.ErrShape {
+			t.Errorf("Expected panic with mat.ErrShape but received %v", r)
+		}
+	}()
+	SymSpMV(CreateCSR(3, 3, nil).(*CSR), make([]float64, 2))
+}
@@ -0,0 +1,128 @@
+package sparse
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// COOTensor is a COOrdinate format sparse 3rd order tensor (3 dimensional array) storing
+// the indices and values of each non-zero element as (i, j, k, v) quadruples.  It extends
+// the coordinate concept of the COO matrix format by one dimension and is intended as a
+// lightweight container for sparse 3 dimensional data, for example slices of volumetric
+// data, which may be unfolded into sparse matrices (see MatricizeMode) for processing
+// with the matrix types and operations in this package.  As with COO, duplicate elements
+// are permitted and are summed together.
+type COOTensor struct {
+	dims [3]int
+	ind  [3][]int
+	data []float64
+}
+
+// NewCOOTensor creates a new COOrdinate format sparse tensor of the specified dimensions
+// d0 * d1 * d2 with the specified slices containing either nil or containing the indices
+// of the non-zero elements along each of the 3 dimensions and the non-zero data values
+// themselves respectively.  If not nil, the supplied slices will be used as the backing
+// storage to the tensor so changes to values of the slices will be reflected in the
+// created tensor and vice versa.
+func NewCOOTensor(d0, d1, d2 int, i, j, k []int, data []float64) *COOTensor {
+	if d0 < 0 || d1 < 0 || d2 < 0 {
+		panic(mat.ErrIndexOutOfRange)
+	}
+
+	t := &COOTensor{dims: [3]int{d0, d1, d2}}
+
+	if i != nil || j != nil || k != nil || data != nil {
+		if i == nil || j == nil || k == nil || data == nil ||
+			len(i) != len(data) || len(j) != len(data) || len(k) != len(data) {
+			panic(mat.ErrShape)
+		}
+		t.ind = [3][]int{i, j, k}
+		t.data = data
+	}
+
+	return t
+}
+
+// Dims returns the size of the tensor along each of its 3 dimensions.
+func (t *COOTensor) Dims() (int, int, int) {
+	return t.dims[0], t.dims[1], t.dims[2]
+}
+
+// NNZ returns the Number of Non Zero elements stored in the sparse tensor.
+func (t *COOTensor) NNZ() int {
+	return len(t.data)
+}
+
+// At returns the element of the tensor located at index (i, j, k).  At will panic if
+// any of the specified indices fall outside the dimensions of the tensor.  Any duplicate
+// values will be summed together.
+func (t *COOTensor) At(i, j, k int) float64 {
+	t.checkIndex(i, j, k)
+
+	result := 0.0
+	for n, v := range t.data {
+		if t.ind[0][n] == i && t.ind[1][n] == j && t.ind[2][n] == k {
+			result += v
+		}
+	}
+	return result
+}
+
+// Set sets the element of the tensor located at index (i, j, k) to equal the specified
+// value, v.  Set will panic if any of the specified indices fall outside the dimensions
+// of the tensor.  Duplicate values are allowed and will be added.
+func (t *COOTensor) Set(i, j, k int, v float64) {
+	t.checkIndex(i, j, k)
+
+	t.ind[0] = append(t.ind[0], i)
+	t.ind[1] = append(t.ind[1], j)
+	t.ind[2] = append(t.ind[2], k)
+	t.data = append(t.data, v)
+}
+
+// DoNonZero calls the function fn for each of the non-zero elements of the receiver.
+// The function fn takes the indices and the value of each element.  The order of
+// visiting to each non-zero element is not guaranteed.
+func (t *COOTensor) DoNonZero(fn func(i, j, k int, v float64)) {
+	for n, v := range t.data {
+		fn(t.ind[0][n], t.ind[1][n], t.ind[2][n], v)
+	}
+}
+
+// MatricizeMode unfolds (matricizes) the tensor along the specified mode (0, 1 or 2)
+// returning the resulting sparse matrix.  The mode-n unfolding arranges the mode-n fibres
+// of the tensor as the columns of the matrix so the result has d_n rows and the product
+// of the other 2 dimensions as columns.  Following the standard convention (Kolda &
+// Bader), element (i0, i1, i2) of the tensor maps to row i_n and column
+// i_p + i_q * d_p of the matrix, where p < q are the remaining modes, so that the lower
+// of the remaining indices varies fastest.  Duplicate elements are summed.  The returned
+// matrix will not share underlying storage with the receiver.  MatricizeMode will panic
+// if mode is not 0, 1 or 2.
+func (t *COOTensor) MatricizeMode(mode int) *CSR {
+	var p, q int
+	switch mode {
+	case 0:
+		p, q = 1, 2
+	case 1:
+		p, q = 0, 2
+	case 2:
+		p, q = 0, 1
+	default:
+		panic(mat.ErrIndexOutOfRange)
+	}
+
+	nnz := len(t.data)
+	rows := make([]int, nnz)
+	cols := make([]int, nnz)
+	for n := 0; n < nnz; n++ {
+		rows[n] = t.ind[mode][n]
+		cols[n] = t.ind[p][n] + t.ind[q][n]*t.dims[p]
+	}
+
+	return NewCOO(t.dims[mode], t.dims[p]*t.dims[q], rows, cols, t.data).ToCSR()
+}
+
+func (t *COOTensor) checkIndex(i, j, k int) {
+	if i < 0 || i >= t.dims[0] || j < 0 || j >= t.dims[1] || k < 0 || k >= t.dims[2] {
+		panic(mat.ErrIndexOutOfRange)
+	}
+}
@@ -0,0 +1,99 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCOOTensorMatricizeMode(t *testing.T) {
+	// 3 x 4 x 2 tensor with frontal slices
+	// X(:, :, 0) = [1 4 7 10; 2 5 8 11; 3 6 9 12]
+	// X(:, :, 1) = [13 16 19 22; 14 17 20 23; 15 18 21 24]
+	// as per the worked example in Kolda & Bader "Tensor Decompositions and Applications"
+	tensor := NewCOOTensor(3, 4, 2, nil, nil, nil, nil)
+	v := 1.0
+	for k := 0; k < 2; k++ {
+		for j := 0; j < 4; j++ {
+			for i := 0; i < 3; i++ {
+				// leave some elements zero to exercise sparsity
+				if int(v)%5 != 0 {
+					tensor.Set(i, j, k, v)
+				}
+				v++
+			}
+		}
+	}
+
+	sparsify := func(data []float64) []float64 {
+		for i, v := range data {
+			if int(v)%5 == 0 {
+				data[i] = 0
+			}
+		}
+		return data
+	}
+
+	var tests = []struct {
+		mode     int
+		r, c     int
+		expected []float64
+	}{
+		{
+			mode: 0,
+			r:    3, c: 8,
+			expected: sparsify([]float64{
+				1, 4, 7, 10, 13, 16, 19, 22,
+				2, 5, 8, 11, 14, 17, 20, 23,
+				3, 6, 9, 12, 15, 18, 21, 24,
+			}),
+		},
+		{
+			mode: 1,
+			r:    4, c: 6,
+			expected: sparsify([]float64{
+				1, 2, 3, 13, 14, 15,
+				4, 5, 6, 16, 17, 18,
+				7, 8, 9, 19, 20, 21,
+				10, 11, 12, 22, 23, 24,
+			}),
+		},
+		{
+			mode: 2,
+			r:    2, c: 12,
+			expected: sparsify([]float64{
+				1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12,
+				13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24,
+			}),
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		result := tensor.MatricizeMode(test.mode)
+
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		if !mat.Equal(expected, result) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+			t.Fail()
+		}
+	}
+}
+
+func TestCOOTensorAt(t *testing.T) {
+	tensor := NewCOOTensor(2, 3, 4, []int{0, 1, 1}, []int{2, 0, 0}, []int{3, 1, 1}, []float64{5, 2, 3})
+
+	if r, c, d := tensor.Dims(); r != 2 || c != 3 || d != 4 {
+		t.Errorf("Expected dimensions 2x3x4 but received %dx%dx%d", r, c, d)
+	}
+	if v := tensor.At(0, 2, 3); v != 5 {
+		t.Errorf("Expected 5 at (0, 2, 3) but received %v", v)
+	}
+	if v := tensor.At(1, 0, 1); v != 5 {
+		t.Errorf("Expected duplicates to be summed to 5 at (1, 0, 1) but received %v", v)
+	}
+	if v := tensor.At(1, 1, 1); v != 0 {
+		t.Errorf("Expected 0 at (1, 1, 1) but received %v", v)
+	}
+}
@@ -0,0 +1,124 @@
+package sparse
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// SolveLower solves the system of linear equations L * x = b for x by forward substitution,
+// where l is a square, lower triangular, sparse matrix, returning x as a new slice.  As CSR
+// stores each row contiguously, each element of x is computed from the stored elements of
+// the corresponding row of l in a single pass, in O(NNZ) in total.  Triangular solves are
+// the kernels of sparse direct solvers, applied after factorising a matrix into triangular
+// factors e.g. with a Cholesky or LU factorisation.  Duplicate elements of l are summed.
+// SolveLower will panic with mat.ErrSquare if l is not square, mat.ErrShape if len(b) does
+// not equal the dimension of l, mat.ErrTriangle if l has a non-zero element above the main
+// diagonal or mat.ErrSingular if a diagonal element of l is zero.
+func SolveLower(l *CSR, b []float64) []float64 {
+	n := checkTriangularSystem(l, b)
+	x := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = solveTriangularRow(l, i, x, b[i], false)
+	}
+	return x
+}
+
+// SolveUpper solves the system of linear equations U * x = b for x by back substitution,
+// where u is a square, upper triangular, sparse matrix, returning x as a new slice.  Each
+// element of x is computed, starting from the last, from the stored elements of the
+// corresponding row of u in a single pass, in O(NNZ) in total.  Duplicate elements of u are
+// summed.  SolveUpper will panic with mat.ErrSquare if u is not square, mat.ErrShape if
+// len(b) does not equal the dimension of u, mat.ErrTriangle if u has a non-zero element
+// below the main diagonal or mat.ErrSingular if a diagonal element of u is zero.
+func SolveUpper(u *CSR, b []float64) []float64 {
+	n := checkTriangularSystem(u, b)
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		x[i] = solveTriangularRow(u, i, x, b[i], true)
+	}
+	return x
+}
+
+// checkTriangularSystem panics if a is not square or b is not the same length as the
+// dimension of a, returning the dimension.
+func checkTriangularSystem(a *CSR, b []float64) int {
+	r, c := a.Dims()
+	if r != c {
+		panic(mat.ErrSquare)
+	}
+	if len(b) != r {
+		panic(mat.ErrShape)
+	}
+	return r
+}
+
+// solveTriangularRow solves for element i of x given the row i of the triangular matrix a,
+// the right hand side bi and the previously solved elements of x (those below i if upper,
+// otherwise above i).
+func solveTriangularRow(a *CSR, i int, x []float64, bi float64, upper bool) float64 {
+	var diag float64
+	sum := bi
+	for k := a.matrix.Indptr[i]; k < a.matrix.Indptr[i+1]; k++ {
+		j, v := a.matrix.Ind[k], a.matrix.Data[k]
+		switch {
+		case j == i:
+			diag += v
+		case (j > i) == upper:
+			sum -= v * x[j]
+		case v != 0:
+			panic(mat.ErrTriangle)
+		}
+	}
+	if diag == 0 {
+		panic(mat.ErrSingular)
+	}
+	return sum / diag
+}
+
+// Triu returns a new CSR matrix containing only the stored elements of the receiver on and
+// above the k-th diagonal, with all other elements zero.  As with numpy's triu, k = 0 refers
+// to the main diagonal, k > 0 to diagonals above the main diagonal and k < 0 to diagonals
+// below it, so element (i, j) is retained if j - i >= k.  The returned matrix does not share
+// storage with the receiver and its backing storage is allocated to exactly the required size.
+func (c *CSR) Triu(k int) *CSR {
+	return c.triangle(func(i, j int) bool { return j-i >= k })
+}
+
+// Tril returns a new CSR matrix containing only the stored elements of the receiver on and
+// below the k-th diagonal, with all other elements zero.  As with numpy's tril, k = 0 refers
+// to the main diagonal, k > 0 to diagonals above the main diagonal and k < 0 to diagonals
+// below it, so element (i, j) is retained if j - i <= k.  For example, Tril(0) extracts the
+// lower triangle of a symmetric matrix for use with SymSpMV.  The returned matrix does not
+// share storage with the receiver and its backing storage is allocated to exactly the
+// required size.
+func (c *CSR) Tril(k int) *CSR {
+	return c.triangle(func(i, j int) bool { return j-i <= k })
+}
+
+// triangle returns a new CSR matrix containing the stored elements (i, j) of the receiver
+// for which keep(i, j) returns true, preserving their order within each row.
+func (c *CSR) triangle(keep func(i, j int) bool) *CSR {
+	nnz := 0
+	for i := 0; i < c.matrix.I; i++ {
+		for p := c.matrix.Indptr[i]; p < c.matrix.Indptr[i+1]; p++ {
+			if keep(i, c.matrix.Ind[p]) {
+				nnz++
+			}
+		}
+	}
+
+	indptr := make([]int, c.matrix.I+1)
+	ind := make([]int, 0, nnz)
+	data := make([]float64, 0, nnz)
+	for i := 0; i < c.matrix.I; i++ {
+		for p := c.matrix.Indptr[i]; p < c.matrix.Indptr[i+1]; p++ {
+			if j := c.matrix.Ind[p]; keep(i, j) {
+				ind = append(ind, j)
+				data = append(data, c.matrix.Data[p])
+			}
+		}
+		indptr[i+1] = len(ind)
+	}
+	t := NewCSR(c.matrix.I, c.matrix.J, indptr, ind, data)
+	t.sorted = c.sorted
+	return t
+}
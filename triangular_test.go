@@ -0,0 +1,184 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestSolveTriangular(t *testing.T) {
+	var tests = []struct {
+		desc  string
+		n     int
+		data  []float64
+		upper bool
+		b     []float64
+	}{
+		{
+			desc: "lower",
+			n:    4,
+			data: []float64{
+				2, 0, 0, 0,
+				1, 3, 0, 0,
+				0, 0, 4, 0,
+				-1, 2, 0, 5,
+			},
+			b: []float64{2, 7, 8, 6},
+		},
+		{
+			desc: "upper",
+			n:    4,
+			data: []float64{
+				2, 1, 0, -1,
+				0, 3, 0, 2,
+				0, 0, 4, 0,
+				0, 0, 0, 5,
+			},
+			upper: true,
+			b:     []float64{2, 7, 8, 5},
+		},
+		{
+			desc: "diagonal",
+			n:    3,
+			data: []float64{
+				2, 0, 0,
+				0, 4, 0,
+				0, 0, 8,
+			},
+			b: []float64{1, 1, 1},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		a := CreateCSR(test.n, test.n, test.data).(*CSR)
+
+		var x []float64
+		if test.upper {
+			x = SolveUpper(a, test.b)
+		} else {
+			x = SolveLower(a, test.b)
+		}
+
+		// check A * x = b
+		result := make([]float64, test.n)
+		a.MulVecTo(result, false, x)
+		if !floats.EqualApprox(result, test.b, 1e-12) {
+			t.Logf("Expected A * x = %v but received %v for x = %v\n", test.b, result, x)
+			t.Fail()
+		}
+
+		var dense mat.VecDense
+		if err := dense.SolveVec(mat.NewDense(test.n, test.n, test.data), mat.NewVecDense(test.n, test.b)); err != nil {
+			t.Fatalf("Unexpected error solving dense system: %v", err)
+		}
+		if !floats.EqualApprox(x, dense.RawVector().Data, 1e-12) {
+			t.Logf("Expected %v but received %v\n", dense.RawVector().Data, x)
+			t.Fail()
+		}
+	}
+}
+
+func TestSolveTriangularUnsorted(t *testing.T) {
+	// lower triangular with unsorted column indices and an explicit zero above the diagonal
+	l := NewCSR(3, 3, []int{0, 1, 4, 6}, []int{0, 1, 2, 0, 2, 0}, []float64{2, 3, 0, 1, 4, 1})
+	x := SolveLower(l, []float64{2, 4, 5})
+	if expected := []float64{1, 1, 1}; !floats.EqualApprox(x, expected, 1e-12) {
+		t.Errorf("Expected %v but received %v", expected, x)
+	}
+}
+
+func TestSolveTriangularPanics(t *testing.T) {
+	lower := CreateCSR(2, 2, []float64{1, 0, 1, 1}).(*CSR)
+	upper := CreateCSR(2, 2, []float64{1, 1, 0, 1}).(*CSR)
+	singular := CreateCSR(2, 2, []float64{1, 0, 1, 0}).(*CSR)
+
+	var tests = []struct {
+		fn       func()
+		expected interface{}
+	}{
+		{fn: func() { SolveLower(CreateCSR(2, 3, nil).(*CSR), []float64{1, 1}) }, expected: mat.ErrSquare},
+		{fn: func() { SolveUpper(upper, []float64{1, 1, 1}) }, expected: mat.ErrShape},
+		{fn: func() { SolveLower(upper, []float64{1, 1}) }, expected: mat.ErrTriangle},
+		{fn: func() { SolveUpper(lower, []float64{1, 1}) }, expected: mat.ErrTriangle},
+		{fn: func() { SolveLower(singular, []float64{1, 1}) }, expected: mat.ErrSingular},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		func() {
+			defer func() {
+				if r := recover(); r != test.expected {
+					t.Logf("Expected panic with %v but received %v\n", test.expected, r)
+					t.Fail()
+				}
+			}()
+			test.fn()
+		}()
+	}
+}
+
+func TestCSRTriuTril(t *testing.T) {
+	data := []float64{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+	}
+	for ti, k := range []int{-3, -1, 0, 1, 2, 4} {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		a := CreateCSR(3, 4, data).(*CSR)
+		upper := mat.NewDense(3, 4, nil)
+		lower := mat.NewDense(3, 4, nil)
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 4; j++ {
+				if j-i >= k {
+					upper.Set(i, j, data[i*4+j])
+				}
+				if j-i <= k {
+					lower.Set(i, j, data[i*4+j])
+				}
+			}
+		}
+
+		triu := a.Triu(k)
+		if !mat.Equal(upper, triu) {
+			t.Logf("Triu(%d) expected:\n%v\n but received:\n%v\n", k, mat.Formatted(upper), mat.Formatted(triu))
+			t.Fail()
+		}
+		tril := a.Tril(k)
+		if !mat.Equal(lower, tril) {
+			t.Logf("Tril(%d) expected:\n%v\n but received:\n%v\n", k, mat.Formatted(lower), mat.Formatted(tril))
+			t.Fail()
+		}
+		if triu.NNZ()+tril.NNZ()-a.Triu(k).Tril(k).NNZ() != a.NNZ() {
+			t.Logf("Expected Triu(%d) and Tril(%d) to partition the stored elements\n", k, k)
+			t.Fail()
+		}
+
+		// check the receiver is unmodified and storage is not shared
+		triu.Set(2, 3, 100)
+		if a.At(2, 3) != 12 {
+			t.Logf("Expected receiver to be unmodified but received %v\n", a.At(2, 3))
+			t.Fail()
+		}
+	}
+}
+
+func TestCSRTrilSymSpMV(t *testing.T) {
+	sym := CreateCSR(3, 3, []float64{
+		4, 1, 0,
+		1, 3, 2,
+		0, 2, 5,
+	}).(*CSR)
+	x := []float64{1, 2, 3}
+
+	expected := make([]float64, 3)
+	sym.MulVecTo(expected, false, x)
+	if result := SymSpMV(sym.Tril(0), x); !floats.Equal(expected, result) {
+		t.Errorf("Expected %v but received %v", expected, result)
+	}
+}
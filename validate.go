@@ -0,0 +1,133 @@
+package sparse
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Validate checks the internal structure of the CSR matrix c for consistency, returning
+// a list of all problems found or nil if the matrix is well formed.  The following
+// defects are detected:
+//   - the length of the row index pointers (Indptr) is not rows+1
+//   - the first row index pointer is not zero
+//   - the row index pointers are not monotonically non-decreasing
+//   - the lengths of the column indices and data slices do not match the final row
+//     index pointer
+//   - column indices out of range of the matrix columns
+//   - column indices not sorted in ascending order within a row
+//   - duplicate column indices within a row
+//
+// This is particularly useful for checking matrices created directly from raw slices
+// (e.g. with NewCSR) ingested from untrusted sources before they are used.  See Repair
+// for correcting detected defects.
+func Validate(c *CSR) []error {
+	var errs []error
+	r, cols := c.matrix.I, c.matrix.J
+	indptr, ind, data := c.matrix.Indptr, c.matrix.Ind, c.matrix.Data
+
+	if err := checkIndptrLen(r, indptr, "row"); err != nil {
+		return append(errs, err)
+	}
+	if err := checkIndptrStart(indptr); err != nil {
+		errs = append(errs, err)
+	}
+	if err := checkIndptrOrder(indptr, "row"); err != nil {
+		return append(errs, err)
+	}
+	if err := checkDataLen(ind, data); err != nil {
+		errs = append(errs, err)
+	}
+	if err := checkIndptrEnd(indptr, len(ind)); err != nil {
+		errs = append(errs, err)
+	}
+
+	// marker records the last row (offset by 1) in which each column was seen
+	marker := getInts(cols, true)
+	defer putInts(marker)
+
+	for i := 0; i < r; i++ {
+		lo, hi := clampRange(indptr[i], indptr[i+1], len(ind))
+		for k := lo; k < hi; k++ {
+			j := ind[k]
+			if err := checkMinorIndex(i, j, cols, "row", "column"); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if marker[j] == i+1 {
+				errs = append(errs, fmt.Errorf("sparse: duplicate column index %d in row %d", j, i))
+			}
+			marker[j] = i + 1
+			if k > lo {
+				if prev := ind[k-1]; j < prev {
+					errs = append(errs, fmt.Errorf("sparse: column indices not sorted in row %d (%d follows %d)", i, j, prev))
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// Repair returns a new CSR matrix correcting the structural defects of c detected by
+// Validate.  The column indices of each row are sorted, the values of duplicate
+// elements within a row are summed and elements with out of range column indices are
+// dropped.  Where the row index pointers of c are inconsistent with the lengths of the
+// column indices and data slices, only the elements of each row lying within the
+// bounds of both slices are retained, and rows whose index pointers are missing or
+// decreasing are treated as empty.  The returned matrix will not share underlying
+// storage with c nor is c modified by this call.
+func Repair(c *CSR) *CSR {
+	r, cols := c.matrix.I, c.matrix.J
+	indptr, ind, data := c.matrix.Indptr, c.matrix.Ind, c.matrix.Data
+
+	n := len(ind)
+	if len(data) < n {
+		n = len(data)
+	}
+
+	ia := make([]int, r+1)
+	ja := make([]int, 0, n)
+	d := make([]float64, 0, n)
+
+	var pairs []indexPair
+	for i := 0; i < r; i++ {
+		pairs = pairs[:0]
+		if i+1 < len(indptr) {
+			lo, hi := clampRange(indptr[i], indptr[i+1], n)
+			for k := lo; k < hi; k++ {
+				if ind[k] >= 0 && ind[k] < cols {
+					pairs = append(pairs, indexPair{index: ind[k], value: data[k]})
+				}
+			}
+		}
+		sort.SliceStable(pairs, func(a, b int) bool {
+			return pairs[a].index < pairs[b].index
+		})
+		for k, p := range pairs {
+			if k > 0 && p.index == pairs[k-1].index {
+				d[len(d)-1] += p.value
+				continue
+			}
+			ja = append(ja, p.index)
+			d = append(d, p.value)
+		}
+		ia[i+1] = len(ja)
+	}
+
+	return NewCSR(r, cols, ia, ja, d)
+}
+
+// clampRange clamps the range [lo, hi) to lie within [0, n) returning an empty range
+// if hi < lo.
+func clampRange(lo, hi, n int) (int, int) {
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > n {
+		hi = n
+	}
+	if hi < lo {
+		hi = lo
+	}
+	return lo, hi
+}
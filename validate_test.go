@@ -0,0 +1,151 @@
+package sparse
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestValidateRepair(t *testing.T) {
+	var tests = []struct {
+		desc     string
+		r, c     int
+		indptr   []int
+		ind      []int
+		data     []float64
+		errs     int
+		expected []float64
+	}{
+		{
+			desc: "valid",
+			r:    2, c: 3,
+			indptr: []int{0, 2, 3},
+			ind:    []int{0, 2, 1},
+			data:   []float64{1, 2, 3},
+			errs:   0,
+			expected: []float64{
+				1, 0, 2,
+				0, 3, 0,
+			},
+		},
+		{
+			desc: "unsorted indices",
+			r:    2, c: 3,
+			indptr: []int{0, 2, 3},
+			ind:    []int{2, 0, 1},
+			data:   []float64{2, 1, 3},
+			errs:   1,
+			expected: []float64{
+				1, 0, 2,
+				0, 3, 0,
+			},
+		},
+		{
+			desc: "duplicate indices",
+			r:    2, c: 3,
+			indptr: []int{0, 3, 4},
+			ind:    []int{0, 2, 2, 1},
+			data:   []float64{1, 2, 5, 3},
+			errs:   1,
+			expected: []float64{
+				1, 0, 7,
+				0, 3, 0,
+			},
+		},
+		{
+			desc: "non-adjacent duplicate indices",
+			r:    2, c: 3,
+			indptr: []int{0, 3, 4},
+			ind:    []int{1, 0, 1, 2},
+			data:   []float64{1, 2, 5, 3},
+			errs:   2,
+			expected: []float64{
+				2, 6, 0,
+				0, 0, 3,
+			},
+		},
+		{
+			desc: "out of range column",
+			r:    2, c: 3,
+			indptr: []int{0, 2, 4},
+			ind:    []int{0, 3, 1, -1},
+			data:   []float64{1, 2, 3, 4},
+			errs:   2,
+			expected: []float64{
+				1, 0, 0,
+				0, 3, 0,
+			},
+		},
+		{
+			desc: "non-monotonic indptr",
+			r:    3, c: 3,
+			indptr: []int{0, 2, 1, 3},
+			ind:    []int{0, 1, 2},
+			data:   []float64{1, 2, 3},
+			errs:   1,
+			expected: []float64{
+				1, 2, 0,
+				0, 0, 0,
+				0, 2, 3,
+			},
+		},
+		{
+			desc: "wrong indptr length",
+			r:    3, c: 3,
+			indptr: []int{0, 1, 2},
+			ind:    []int{0, 1},
+			data:   []float64{1, 2},
+			errs:   1,
+			expected: []float64{
+				1, 0, 0,
+				0, 2, 0,
+				0, 0, 0,
+			},
+		},
+		{
+			desc: "data length mismatch",
+			r:    2, c: 3,
+			indptr: []int{0, 2, 3},
+			ind:    []int{0, 2, 1},
+			data:   []float64{1, 2},
+			errs:   1,
+			expected: []float64{
+				1, 0, 2,
+				0, 0, 0,
+			},
+		},
+		{
+			desc: "first indptr non-zero",
+			r:    2, c: 3,
+			indptr: []int{1, 2, 3},
+			ind:    []int{0, 2, 1},
+			data:   []float64{1, 2, 3},
+			errs:   1,
+			expected: []float64{
+				0, 0, 2,
+				0, 3, 0,
+			},
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d. %s\n", ti+1, test.desc)
+
+		csr := NewCSR(test.r, test.c, test.indptr, test.ind, test.data)
+
+		errs := Validate(csr)
+		if len(errs) != test.errs {
+			t.Errorf("Expected %d validation errors but received %d: %v", test.errs, len(errs), errs)
+		}
+
+		repaired := Repair(csr)
+		if errs := Validate(repaired); errs != nil {
+			t.Errorf("Expected repaired matrix to be valid but received errors: %v", errs)
+		}
+		expected := mat.NewDense(test.r, test.c, test.expected)
+		if !mat.Equal(expected, repaired) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(expected), mat.Formatted(repaired))
+			t.Fail()
+		}
+	}
+}
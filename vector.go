@@ -335,6 +335,86 @@ func (v *Vector) Norm(L float64) float64 {
 	return floats.Norm(v.data, L)
 }
 
+// Scale returns a new sparse Vector containing the elements of the receiver scaled
+// by alpha.  If alpha is 0, the returned vector will contain no stored elements.
+// The returned vector will not share underlying storage with the receiver nor is
+// the receiver modified by this call.  See ScaleVec for scaling into an existing
+// vector.
+func (v *Vector) Scale(alpha float64) *Vector {
+	if alpha == 0 {
+		return NewVector(v.len, nil, nil)
+	}
+	ind := make([]int, len(v.ind))
+	copy(ind, v.ind)
+	data := make([]float64, len(v.data))
+	for i, val := range v.data {
+		data[i] = alpha * val
+	}
+	return NewVector(v.len, ind, data)
+}
+
+// Add returns a new sparse Vector containing the sum of the receiver and b.  If b is
+// also a sparse Vector, the index sets of the two vectors are merged processing only
+// the non-zero elements of each.  Any elements of the result that cancel to exactly
+// zero are dropped and the indices of the returned vector are sorted in ascending
+// order.  As with Dot, the receiver and b may be sorted in place by this call if they
+// are not already sorted.  Add will panic if the receiver and b are not the same
+// length.  See AddVec for adding into an existing vector.
+func (v *Vector) Add(b mat.Vector) *Vector {
+	if v.len != b.Len() {
+		panic(mat.ErrShape)
+	}
+
+	sb, bIsSparse := b.(*Vector)
+	if !bIsSparse {
+		var ind []int
+		var data []float64
+		for i := 0; i < v.len; i++ {
+			if val := v.AtVec(i) + b.AtVec(i); val != 0 {
+				ind = append(ind, i)
+				data = append(data, val)
+			}
+		}
+		return NewVector(v.len, ind, data)
+	}
+
+	v.Sort()
+	sb.Sort()
+
+	ind := make([]int, 0, len(v.ind)+len(sb.ind))
+	data := make([]float64, 0, len(v.ind)+len(sb.ind))
+	appendNonZero := func(i int, val float64) {
+		if val != 0 {
+			ind = append(ind, i)
+			data = append(data, val)
+		}
+	}
+
+	var i, j int
+	for i < len(v.ind) && j < len(sb.ind) {
+		switch {
+		case v.ind[i] < sb.ind[j]:
+			appendNonZero(v.ind[i], v.data[i])
+			i++
+		case v.ind[i] > sb.ind[j]:
+			appendNonZero(sb.ind[j], sb.data[j])
+			j++
+		default:
+			appendNonZero(v.ind[i], v.data[i]+sb.data[j])
+			i++
+			j++
+		}
+	}
+	for ; i < len(v.ind); i++ {
+		appendNonZero(v.ind[i], v.data[i])
+	}
+	for ; j < len(sb.ind); j++ {
+		appendNonZero(sb.ind[j], sb.data[j])
+	}
+
+	return NewVector(v.len, ind, data)
+}
+
 // Dot returns the sum of the element-wise product (dot product) of a and b.
 // Dot panics if the matrix sizes are unequal.  For sparse vectors, Dot will
 // only process non-zero elements otherwise this method simply delegates to
@@ -378,6 +458,74 @@ func dotSparse(a *Vector, b mat.Vector) float64 {
 	return result
 }
 
+// Outer returns a new CSR matrix containing the outer product alpha*x*y^T of the vectors
+// x and y.  Elements are only stored at the cross products of the non-zero elements of x
+// and y so, where x and y are sparse Vectors, the cost is proportional to
+// NNZ(x)*NNZ(y).  The column indices of each row of the returned matrix are sorted.
+func Outer(alpha float64, x, y mat.Vector) *CSR {
+	r, c := x.Len(), y.Len()
+	indptr := make([]int, r+1)
+	if alpha == 0 {
+		return NewCSR(r, c, indptr, nil, nil)
+	}
+
+	xind, xdata := sortedNonZeros(x)
+	yind, ydata := sortedNonZeros(y)
+
+	ind := make([]int, 0, len(xind)*len(yind))
+	data := make([]float64, 0, len(xind)*len(yind))
+	k := 0
+	for i := 0; i < r; i++ {
+		if k < len(xind) && xind[k] == i {
+			ax := alpha * xdata[k]
+			for q, j := range yind {
+				ind = append(ind, j)
+				data = append(data, ax*ydata[q])
+			}
+			k++
+		}
+		indptr[i+1] = len(ind)
+	}
+
+	return NewCSR(r, c, indptr, ind, data)
+}
+
+// sortedNonZeros returns the indices and values of the non-zero elements of v with the
+// indices sorted in ascending order.  Where v is a sparse Vector, only its stored
+// elements are visited and, consistent with AtVec, only the first stored element for
+// any duplicated index is used.
+func sortedNonZeros(v mat.Vector) ([]int, []float64) {
+	var ind []int
+	var data []float64
+
+	sv, isSparse := v.(*Vector)
+	if !isSparse {
+		for i := 0; i < v.Len(); i++ {
+			if val := v.AtVec(i); val != 0 {
+				ind = append(ind, i)
+				data = append(data, val)
+			}
+		}
+		return ind, data
+	}
+
+	pairs := make([]indexPair, len(sv.ind))
+	for i, idx := range sv.ind {
+		pairs[i] = indexPair{index: idx, value: sv.data[i]}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].index < pairs[j].index
+	})
+	for i, p := range pairs {
+		if (i > 0 && p.index == pairs[i-1].index) || p.value == 0 {
+			continue
+		}
+		ind = append(ind, p.index)
+		data = append(data, p.value)
+	}
+	return ind, data
+}
+
 // Reset zeros the dimensions of the vector so that it can be reused as the
 // receiver of a dimensionally restricted operation.
 //
@@ -1,6 +1,7 @@
 package sparse
 
 import (
+	"math"
 	"testing"
 
 	"gonum.org/v1/gonum/mat"
@@ -235,6 +236,90 @@ func TestVectorNorm(t *testing.T) {
 	}
 }
 
+func TestVectorAddSparse(t *testing.T) {
+	tests := []struct {
+		a    *Vector
+		b    mat.Vector
+		r    mat.Vector
+		rnnz int
+	}{
+		{
+			// disjoint
+			a:    NewVector(6, []int{0, 4}, []float64{1, 2}),
+			b:    NewVector(6, []int{5, 1, 3}, []float64{3, 1, 1}),
+			r:    mat.NewVecDense(6, []float64{1, 1, 0, 1, 2, 3}),
+			rnnz: 5,
+		},
+		{
+			// overlapping
+			a:    NewVector(6, []int{1, 3, 4}, []float64{1, 2, 1}),
+			b:    NewVector(6, []int{0, 1, 3}, []float64{1, 1, 1}),
+			r:    mat.NewVecDense(6, []float64{1, 2, 0, 3, 1, 0}),
+			rnnz: 4,
+		},
+		{
+			// overlapping with cancellation
+			a:    NewVector(6, []int{1, 3, 4}, []float64{1, 2, 1}),
+			b:    NewVector(6, []int{1, 3}, []float64{-1, 1}),
+			r:    mat.NewVecDense(6, []float64{0, 0, 0, 3, 1, 0}),
+			rnnz: 2,
+		},
+		{
+			// dense operand
+			a:    NewVector(6, []int{1, 3}, []float64{1, 2}),
+			b:    mat.NewVecDense(6, []float64{1, -1, 0, 1, 0, 0}),
+			r:    mat.NewVecDense(6, []float64{1, 0, 0, 3, 0, 0}),
+			rnnz: 2,
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		result := test.a.Add(test.b)
+
+		if !mat.Equal(test.r, result) {
+			t.Errorf("Incorrect result for Add - expected:\n%v\nbut received:\n%v\n", mat.Formatted(test.r), mat.Formatted(result))
+		}
+		if result.NNZ() != test.rnnz {
+			t.Errorf("Expected %d non zero elements but received %d", test.rnnz, result.NNZ())
+		}
+		if !result.IsSorted() {
+			t.Errorf("Expected sorted indices but received %v", result.ind)
+		}
+	}
+}
+
+func TestVectorScaleNew(t *testing.T) {
+	a := NewVector(6, []int{1, 3, 4}, []float64{1, 2, -1})
+
+	result := a.Scale(2)
+	expected := mat.NewVecDense(6, []float64{0, 2, 0, 4, -2, 0})
+	if !mat.Equal(expected, result) {
+		t.Errorf("Incorrect result for Scale - expected:\n%v\nbut received:\n%v\n", mat.Formatted(expected), mat.Formatted(result))
+	}
+	if a.data[1] != 2 {
+		t.Errorf("Expected receiver to be unmodified but received %v", a.data)
+	}
+
+	result = a.Scale(0)
+	if result.NNZ() != 0 || result.Len() != 6 {
+		t.Errorf("Expected empty vector of length 6 but received length %d with %d non zero elements", result.Len(), result.NNZ())
+	}
+}
+
+func TestVectorNorms(t *testing.T) {
+	a := NewVector(6, []int{1, 2, 4}, []float64{1, -3, 2})
+	d := mat.NewVecDense(6, []float64{0, 1, -3, 0, 2, 0})
+
+	for _, L := range []float64{1, 2, math.Inf(1)} {
+		expected := mat.Norm(d, L)
+		if result := a.Norm(L); math.Abs(result-expected) > 1e-12 {
+			t.Errorf("Incorrect result for %v-norm - expected %v but received %v", L, expected, result)
+		}
+	}
+}
+
 func TestVectorDoNonZero(t *testing.T) {
 	var tests = []struct {
 		nnz  int
@@ -766,3 +851,64 @@ func TestMulMatSparseVec(t *testing.T) {
 		}
 	}
 }
+
+func TestOuter(t *testing.T) {
+	tests := []struct {
+		alpha float64
+		x, y  mat.Vector
+	}{
+		{
+			alpha: 2,
+			x:     NewVector(4, []int{3, 0}, []float64{2, 1}),
+			y:     NewVector(3, []int{2, 1}, []float64{5, 4}),
+		},
+		{
+			alpha: 1,
+			x:     mat.NewVecDense(3, []float64{1, 0, 2}),
+			y:     NewVector(5, []int{4, 0, 4}, []float64{1, 3, 2}),
+		},
+		{
+			alpha: -1,
+			x:     NewVector(2, []int{0, 1}, []float64{1, 0}),
+			y:     mat.NewVecDense(2, []float64{0, 7}),
+		},
+		{
+			alpha: 0,
+			x:     mat.NewVecDense(2, []float64{1, 2}),
+			y:     mat.NewVecDense(2, []float64{3, 4}),
+		},
+		{
+			alpha: 3,
+			x:     NewVector(3, nil, nil),
+			y:     mat.NewVecDense(2, []float64{3, 4}),
+		},
+	}
+
+	for ti, test := range tests {
+		t.Logf("**** Test Run %d.\n", ti+1)
+
+		var expected mat.Dense
+		expected.Outer(test.alpha, test.x, test.y)
+
+		outer := Outer(test.alpha, test.x, test.y)
+		if !mat.Equal(&expected, outer) {
+			t.Logf("Expected:\n%v\n but received:\n%v\n", mat.Formatted(&expected), mat.Formatted(outer))
+			t.Fail()
+		}
+		if errs := Validate(outer); len(errs) != 0 {
+			t.Logf("Outer product is invalid: %v\n", errs)
+			t.Fail()
+		}
+		var nnz int
+		expected.Apply(func(i, j int, v float64) float64 {
+			if v != 0 {
+				nnz++
+			}
+			return v
+		}, &expected)
+		if outer.NNZ() != nnz {
+			t.Logf("Expected %d stored elements but received %d\n", nnz, outer.NNZ())
+			t.Fail()
+		}
+	}
+}